@@ -5,8 +5,11 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"time"
 
 	"github.com/federus1105/weekly/internals/configs"
+	"github.com/federus1105/weekly/internals/jobs"
+	"github.com/federus1105/weekly/internals/repositories"
 	"github.com/federus1105/weekly/internals/routers"
 	"github.com/joho/godotenv"
 )
@@ -50,7 +53,49 @@ func main() {
 	}
 	log.Println("✅ REDIS Connected: ", Rdb)
 
-	router := routers.InitRouter(db, rdb)
+	// Inisialisasi storage untuk file upload
+	store, err := configs.InitStorage()
+	if err != nil {
+		log.Println("❌ Failed to init storage\nCause: ", err.Error())
+		return
+	}
+
+	// Biaya layanan & pajak tiket
+	charges, err := configs.InitCharges()
+	if err != nil {
+		log.Println("❌ Failed to init charges\nCause: ", err.Error())
+		return
+	}
+
+	// Garbage collector file gambar, otomatis jika STORAGE_GC_INTERVAL di-set (misal 24h)
+	gc := jobs.NewImageGC(repositories.NewImageRepository(db), store, jobs.DefaultGCGrace)
+	if interval, err := time.ParseDuration(os.Getenv("STORAGE_GC_INTERVAL")); err == nil && interval > 0 {
+		go jobs.Every(context.Background(), "image-gc", interval, func(ctx context.Context) error {
+			result, err := gc.Run(ctx, false)
+			if err == nil {
+				log.Printf("[job image-gc] deleted %d of %d files", len(result.Deleted), result.Scanned)
+			}
+			return err
+		})
+	}
+
+	// Refresh skor popularitas movie, interval dari POPULARITY_REFRESH_INTERVAL (default 15m, 0 = nonaktif)
+	popularity := jobs.NewPopularityRefresh(repositories.NewPopularityRepository(db, rdb))
+	popularityInterval := jobs.DefaultPopularityInterval
+	if v := os.Getenv("POPULARITY_REFRESH_INTERVAL"); v != "" {
+		if popularityInterval, err = time.ParseDuration(v); err != nil {
+			log.Println("❌ Invalid POPULARITY_REFRESH_INTERVAL\nCause: ", err.Error())
+			return
+		}
+	}
+	if popularityInterval > 0 {
+		go jobs.Every(context.Background(), "popularity-refresh", popularityInterval, func(ctx context.Context) error {
+			_, err := popularity.Run(ctx)
+			return err
+		})
+	}
+
+	router := routers.InitRouter(db, rdb, store, gc, popularity, charges)
 	//
 	router.Run("0.0.0.0:8080")
 	// router.Run("localhost:8080")
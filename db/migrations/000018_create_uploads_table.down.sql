@@ -0,0 +1 @@
+DROP TABLE public.uploads;
\ No newline at end of file
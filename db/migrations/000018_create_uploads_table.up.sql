@@ -0,0 +1,26 @@
+-- public.uploads definition
+
+-- Drop table
+
+-- DROP TABLE public.uploads;
+
+CREATE TABLE public.uploads (
+	id uuid DEFAULT gen_random_uuid() NOT NULL,
+	id_user int4 NOT NULL,
+	purpose varchar(20) NOT NULL,
+	object_key varchar(255) NOT NULL,
+	image_key varchar(255) NULL,
+	status varchar(20) DEFAULT 'pending' NOT NULL,
+	expires_at timestamptz NOT NULL,
+	created_at timestamptz DEFAULT now() NOT NULL,
+	CONSTRAINT uploads_pkey PRIMARY KEY (id),
+	CONSTRAINT uploads_purpose_check CHECK (purpose IN ('poster', 'backdrop', 'avatar')),
+	CONSTRAINT uploads_status_check CHECK (status IN ('pending', 'confirmed', 'attached'))
+);
+
+CREATE INDEX uploads_status_expires_at_idx ON public.uploads (status, expires_at);
+
+
+-- public.uploads foreign keys
+
+ALTER TABLE public.uploads ADD CONSTRAINT uploads_id_user_fkey FOREIGN KEY (id_user) REFERENCES public.users(id) ON DELETE CASCADE;
@@ -0,0 +1,2 @@
+DROP TABLE public.movie_stills;
+DROP TABLE public.movie_trailers;
\ No newline at end of file
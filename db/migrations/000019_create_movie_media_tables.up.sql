@@ -0,0 +1,46 @@
+-- public.movie_trailers definition
+
+-- Drop table
+
+-- DROP TABLE public.movie_trailers;
+
+CREATE TABLE public.movie_trailers (
+	id int4 GENERATED ALWAYS AS IDENTITY( INCREMENT BY 1 MINVALUE 1 MAXVALUE 2147483647 START 1 CACHE 1 NO CYCLE) NOT NULL,
+	id_movie int4 NOT NULL,
+	title varchar(255) DEFAULT '' NOT NULL,
+	url varchar(500) NOT NULL,
+	provider varchar(20) NOT NULL,
+	duration int4 DEFAULT 0 NOT NULL,
+	"position" int4 DEFAULT 0 NOT NULL,
+	created_at timestamp DEFAULT now() NOT NULL,
+	CONSTRAINT movie_trailers_pkey PRIMARY KEY (id),
+	CONSTRAINT movie_trailers_provider_check CHECK (provider IN ('youtube', 'vimeo', 'other')),
+	CONSTRAINT movie_trailers_duration_check CHECK (duration >= 0)
+);
+
+CREATE INDEX movie_trailers_id_movie_idx ON public.movie_trailers (id_movie, "position");
+
+
+-- public.movie_stills definition
+
+-- Drop table
+
+-- DROP TABLE public.movie_stills;
+
+CREATE TABLE public.movie_stills (
+	id int4 GENERATED ALWAYS AS IDENTITY( INCREMENT BY 1 MINVALUE 1 MAXVALUE 2147483647 START 1 CACHE 1 NO CYCLE) NOT NULL,
+	id_movie int4 NOT NULL,
+	image varchar(255) NOT NULL,
+	caption varchar(255) DEFAULT '' NOT NULL,
+	"position" int4 DEFAULT 0 NOT NULL,
+	created_at timestamp DEFAULT now() NOT NULL,
+	CONSTRAINT movie_stills_pkey PRIMARY KEY (id)
+);
+
+CREATE INDEX movie_stills_id_movie_idx ON public.movie_stills (id_movie, "position");
+
+
+-- public.movie_trailers & public.movie_stills foreign keys
+
+ALTER TABLE public.movie_trailers ADD CONSTRAINT movie_trailers_id_movie_fkey FOREIGN KEY (id_movie) REFERENCES public.movies(id) ON DELETE CASCADE;
+ALTER TABLE public.movie_stills ADD CONSTRAINT movie_stills_id_movie_fkey FOREIGN KEY (id_movie) REFERENCES public.movies(id) ON DELETE CASCADE;
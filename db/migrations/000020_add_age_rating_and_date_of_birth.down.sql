@@ -0,0 +1,3 @@
+ALTER TABLE public.account DROP COLUMN date_of_birth;
+DROP INDEX public.movies_age_rating_idx;
+ALTER TABLE public.movies DROP COLUMN age_rating;
@@ -0,0 +1,10 @@
+-- klasifikasi usia film (LSF): SU, 13+, 17+, 21+
+
+ALTER TABLE public.movies ADD age_rating varchar(3) DEFAULT 'SU' NOT NULL;
+ALTER TABLE public.movies ADD CONSTRAINT movies_age_rating_check CHECK (age_rating IN ('SU', '13+', '17+', '21+'));
+
+CREATE INDEX movies_age_rating_idx ON public.movies (age_rating);
+
+-- tanggal lahir untuk verifikasi usia saat checkout
+
+ALTER TABLE public.account ADD date_of_birth date NULL;
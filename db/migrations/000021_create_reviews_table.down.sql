@@ -0,0 +1,4 @@
+ALTER TABLE public.movies DROP COLUMN review_count;
+ALTER TABLE public.movies DROP COLUMN review_average;
+DROP TABLE public.review_reports;
+DROP TABLE public.reviews;
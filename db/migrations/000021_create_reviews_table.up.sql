@@ -0,0 +1,51 @@
+-- public.reviews definition
+
+-- Drop table
+
+-- DROP TABLE public.reviews;
+
+CREATE TABLE public.reviews (
+	id int4 GENERATED ALWAYS AS IDENTITY( INCREMENT BY 1 MINVALUE 1 MAXVALUE 2147483647 START 1 CACHE 1 NO CYCLE) NOT NULL,
+	id_movie int4 NOT NULL,
+	id_user int4 NOT NULL,
+	rating int2 NOT NULL,
+	review text DEFAULT '' NOT NULL,
+	status varchar(10) DEFAULT 'visible' NOT NULL,
+	created_at timestamp DEFAULT now() NOT NULL,
+	updated_at timestamp DEFAULT now() NOT NULL,
+	CONSTRAINT reviews_pkey PRIMARY KEY (id),
+	CONSTRAINT reviews_id_movie_id_user_key UNIQUE (id_movie, id_user),
+	CONSTRAINT reviews_rating_check CHECK (rating BETWEEN 1 AND 10),
+	CONSTRAINT reviews_status_check CHECK (status IN ('visible', 'hidden'))
+);
+
+CREATE INDEX reviews_id_movie_status_idx ON public.reviews (id_movie, status, id DESC);
+
+
+-- public.review_reports definition
+
+-- Drop table
+
+-- DROP TABLE public.review_reports;
+
+CREATE TABLE public.review_reports (
+	id_review int4 NOT NULL,
+	id_user int4 NOT NULL,
+	reason varchar(255) NOT NULL,
+	created_at timestamp DEFAULT now() NOT NULL,
+	CONSTRAINT review_reports_pkey PRIMARY KEY (id_review, id_user)
+);
+
+
+-- public.reviews & public.review_reports foreign keys
+
+ALTER TABLE public.reviews ADD CONSTRAINT reviews_id_movie_fkey FOREIGN KEY (id_movie) REFERENCES public.movies(id) ON DELETE CASCADE;
+ALTER TABLE public.reviews ADD CONSTRAINT reviews_id_user_fkey FOREIGN KEY (id_user) REFERENCES public.users(id) ON DELETE CASCADE;
+ALTER TABLE public.review_reports ADD CONSTRAINT review_reports_id_review_fkey FOREIGN KEY (id_review) REFERENCES public.reviews(id) ON DELETE CASCADE;
+ALTER TABLE public.review_reports ADD CONSTRAINT review_reports_id_user_fkey FOREIGN KEY (id_user) REFERENCES public.users(id) ON DELETE CASCADE;
+
+
+-- rata-rata & jumlah review yang tampil, diperbarui setiap review berubah
+
+ALTER TABLE public.movies ADD review_average float8 DEFAULT 0 NOT NULL;
+ALTER TABLE public.movies ADD review_count int4 DEFAULT 0 NOT NULL;
@@ -0,0 +1 @@
+DROP MATERIALIZED VIEW public.movie_popularity;
@@ -0,0 +1,28 @@
+-- public.movie_popularity definition
+
+-- Popularitas movie dari kursi yang terjual (order lunas) selama 7 hari terakhir.
+-- Setiap kursi diberi bobot peluruhan dengan half-life 3 hari, jadi penjualan
+-- kemarin bernilai lebih besar dari penjualan minggu lalu.
+-- Di-refresh berkala oleh job popularity-refresh (REFRESH ... CONCURRENTLY).
+
+-- Drop view
+
+-- DROP MATERIALIZED VIEW public.movie_popularity;
+
+CREATE MATERIALIZED VIEW public.movie_popularity AS
+SELECT
+	s.id_movie,
+	COUNT(DISTINCT o.id)::int4 AS orders,
+	COUNT(os.id_seats)::int4 AS seats_sold,
+	SUM(power(0.5, EXTRACT(EPOCH FROM (LOCALTIMESTAMP - o.created_at)) / 259200.0))::float8 AS score
+FROM public.orders o
+JOIN public.schedule s ON s.id = o.id_schedule
+JOIN public.order_seat os ON os.id_order = o.id
+WHERE o.paid = true
+	AND o.created_at >= LOCALTIMESTAMP - INTERVAL '7 days'
+GROUP BY s.id_movie
+WITH DATA;
+
+-- unique index wajib untuk REFRESH MATERIALIZED VIEW CONCURRENTLY
+CREATE UNIQUE INDEX movie_popularity_id_movie_idx ON public.movie_popularity (id_movie);
+CREATE INDEX movie_popularity_score_idx ON public.movie_popularity (score DESC, id_movie DESC);
@@ -0,0 +1,3 @@
+DROP TABLE public.notifications;
+DROP TABLE public.watchlist;
+ALTER TABLE public.account DROP COLUMN id_location;
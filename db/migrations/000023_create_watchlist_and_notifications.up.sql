@@ -0,0 +1,55 @@
+-- lokasi favorit user, dipakai untuk notifikasi jadwal pertama movie di watchlist
+
+ALTER TABLE public.account ADD id_location int4 NULL;
+ALTER TABLE public.account ADD CONSTRAINT account_id_location_fkey FOREIGN KEY (id_location) REFERENCES public."location"(id) ON DELETE SET NULL;
+
+
+-- public.watchlist definition
+
+-- Drop table
+
+-- DROP TABLE public.watchlist;
+
+CREATE TABLE public.watchlist (
+	id_user int4 NOT NULL,
+	id_movie int4 NOT NULL,
+	notify bool DEFAULT false NOT NULL,
+	created_at timestamp DEFAULT now() NOT NULL,
+	CONSTRAINT watchlist_pkey PRIMARY KEY (id_user, id_movie)
+);
+
+CREATE INDEX watchlist_id_user_created_at_idx ON public.watchlist (id_user, created_at DESC, id_movie DESC);
+CREATE INDEX watchlist_id_movie_idx ON public.watchlist (id_movie);
+
+
+-- public.notifications definition
+
+-- Drop table
+
+-- DROP TABLE public.notifications;
+
+CREATE TABLE public.notifications (
+	id int4 GENERATED ALWAYS AS IDENTITY( INCREMENT BY 1 MINVALUE 1 MAXVALUE 2147483647 START 1 CACHE 1 NO CYCLE) NOT NULL,
+	id_user int4 NOT NULL,
+	"type" varchar(30) NOT NULL,
+	id_movie int4 NULL,
+	id_location int4 NULL,
+	id_schedule int4 NULL,
+	read_at timestamp NULL,
+	created_at timestamp DEFAULT now() NOT NULL,
+	CONSTRAINT notifications_pkey PRIMARY KEY (id)
+);
+
+CREATE INDEX notifications_id_user_idx ON public.notifications (id_user, id DESC);
+-- satu notifikasi jadwal pertama per user, movie & lokasi
+CREATE UNIQUE INDEX notifications_first_schedule_key ON public.notifications (id_user, id_movie, id_location) WHERE "type" = 'first_schedule';
+
+
+-- public.watchlist & public.notifications foreign keys
+
+ALTER TABLE public.watchlist ADD CONSTRAINT watchlist_id_user_fkey FOREIGN KEY (id_user) REFERENCES public.users(id) ON DELETE CASCADE;
+ALTER TABLE public.watchlist ADD CONSTRAINT watchlist_id_movie_fkey FOREIGN KEY (id_movie) REFERENCES public.movies(id) ON DELETE CASCADE;
+ALTER TABLE public.notifications ADD CONSTRAINT notifications_id_user_fkey FOREIGN KEY (id_user) REFERENCES public.users(id) ON DELETE CASCADE;
+ALTER TABLE public.notifications ADD CONSTRAINT notifications_id_movie_fkey FOREIGN KEY (id_movie) REFERENCES public.movies(id) ON DELETE CASCADE;
+ALTER TABLE public.notifications ADD CONSTRAINT notifications_id_location_fkey FOREIGN KEY (id_location) REFERENCES public."location"(id) ON DELETE SET NULL;
+ALTER TABLE public.notifications ADD CONSTRAINT notifications_id_schedule_fkey FOREIGN KEY (id_schedule) REFERENCES public.schedule(id) ON DELETE SET NULL;
@@ -0,0 +1,22 @@
+-- cinema salinan hasil backfill tidak dihapus karena jadwal lama menunjuk ke sana
+
+ALTER TABLE public.schedule ADD id_cinema int4 NULL;
+ALTER TABLE public.schedule ADD id_location int4 NULL;
+
+UPDATE public.schedule s
+SET id_cinema = c.id, id_location = c.id_location
+FROM public.studio st
+JOIN public.cinema c ON c.id = st.id_cinema
+WHERE st.id = s.id_studio;
+
+ALTER TABLE public.schedule ADD CONSTRAINT schedule_id_cinema_fkey FOREIGN KEY (id_cinema) REFERENCES public.cinema(id);
+ALTER TABLE public.schedule ADD CONSTRAINT schedule_id_location_fkey FOREIGN KEY (id_location) REFERENCES public."location"(id);
+
+ALTER TABLE public.schedule DROP COLUMN id_studio;
+DROP TABLE public.studio;
+
+ALTER TABLE public.cinema DROP COLUMN facilities;
+ALTER TABLE public.cinema DROP COLUMN longitude;
+ALTER TABLE public.cinema DROP COLUMN latitude;
+ALTER TABLE public.cinema DROP COLUMN address;
+ALTER TABLE public.cinema DROP COLUMN id_location;
@@ -0,0 +1,106 @@
+-- Struktur jaringan bioskop: location -> cinema -> studio -> schedule.
+-- Sebelumnya schedule menyimpan id_cinema & id_location sendiri-sendiri sehingga
+-- satu jadwal bisa menunjuk cinema di kota yang salah.
+
+-- detail cinema, cinema dimiliki satu location
+
+ALTER TABLE public.cinema ADD id_location int4 NULL;
+ALTER TABLE public.cinema ADD address varchar(255) DEFAULT '' NOT NULL;
+ALTER TABLE public.cinema ADD latitude float8 NULL;
+ALTER TABLE public.cinema ADD longitude float8 NULL;
+ALTER TABLE public.cinema ADD facilities _text DEFAULT '{}' NOT NULL;
+ALTER TABLE public.cinema ADD CONSTRAINT cinema_id_location_fkey FOREIGN KEY (id_location) REFERENCES public."location"(id);
+ALTER TABLE public.cinema ADD CONSTRAINT cinema_coordinates_check CHECK (
+	(latitude IS NULL AND longitude IS NULL)
+	OR (latitude BETWEEN -90 AND 90 AND longitude BETWEEN -180 AND 180)
+);
+
+CREATE INDEX cinema_id_location_idx ON public.cinema (id_location);
+
+
+-- public.studio definition
+
+-- Drop table
+
+-- DROP TABLE public.studio;
+
+CREATE TABLE public.studio (
+	id int4 GENERATED ALWAYS AS IDENTITY( INCREMENT BY 1 MINVALUE 1 MAXVALUE 2147483647 START 1 CACHE 1 NO CYCLE) NOT NULL,
+	id_cinema int4 NOT NULL,
+	"name" varchar(100) NOT NULL,
+	capacity int4 NULL,
+	CONSTRAINT studio_pkey PRIMARY KEY (id),
+	CONSTRAINT studio_id_cinema_name_key UNIQUE (id_cinema, name),
+	CONSTRAINT studio_capacity_check CHECK (capacity IS NULL OR capacity > 0)
+);
+
+ALTER TABLE public.studio ADD CONSTRAINT studio_id_cinema_fkey FOREIGN KEY (id_cinema) REFERENCES public.cinema(id) ON DELETE CASCADE;
+
+ALTER TABLE public.schedule ADD id_studio int4 NULL;
+ALTER TABLE public.schedule ADD CONSTRAINT schedule_id_studio_fkey FOREIGN KEY (id_studio) REFERENCES public.studio(id);
+
+CREATE INDEX schedule_id_studio_idx ON public.schedule (id_studio);
+
+
+-- Backfill data lama.
+-- Cinema lama dipakai di beberapa location sekaligus. Location yang paling banyak
+-- jadwalnya tetap memakai cinema lama, location lain mendapat salinan cinema
+-- (nama, logo & harga sama). Setiap cinema mendapat satu studio default.
+
+DO $$
+DECLARE
+	r record;
+	branch int4;
+BEGIN
+	IF EXISTS (SELECT 1 FROM public.schedule WHERE id_cinema IS NULL) THEN
+		RAISE EXCEPTION 'schedule tanpa id_cinema tidak bisa dipindahkan ke studio, lengkapi datanya dulu';
+	END IF;
+
+	CREATE TEMP TABLE cinema_branch ON COMMIT DROP AS
+	SELECT
+		id_cinema,
+		id_location,
+		ROW_NUMBER() OVER (PARTITION BY id_cinema ORDER BY COUNT(*) DESC, id_location) AS priority,
+		NULL::int4 AS id_branch
+	FROM public.schedule
+	WHERE id_location IS NOT NULL
+	GROUP BY id_cinema, id_location;
+
+	UPDATE public.cinema c
+	SET id_location = b.id_location
+	FROM cinema_branch b
+	WHERE b.id_cinema = c.id AND b.priority = 1;
+
+	UPDATE cinema_branch SET id_branch = id_cinema WHERE priority = 1;
+
+	FOR r IN SELECT id_cinema, id_location FROM cinema_branch WHERE priority > 1 LOOP
+		INSERT INTO public.cinema (name, image, price, id_location)
+		SELECT name, image, price, r.id_location
+		FROM public.cinema
+		WHERE id = r.id_cinema
+		RETURNING id INTO branch;
+
+		UPDATE cinema_branch SET id_branch = branch
+		WHERE id_cinema = r.id_cinema AND id_location = r.id_location;
+	END LOOP;
+
+	INSERT INTO public.studio (id_cinema, name)
+	SELECT id, 'Studio 1' FROM public.cinema;
+
+	-- jadwal dengan location: studio milik cabang di location tersebut
+	UPDATE public.schedule s
+	SET id_studio = st.id
+	FROM cinema_branch b
+	JOIN public.studio st ON st.id_cinema = b.id_branch
+	WHERE s.id_cinema = b.id_cinema AND s.id_location = b.id_location;
+
+	-- jadwal tanpa location: studio milik cinema asal
+	UPDATE public.schedule s
+	SET id_studio = st.id
+	FROM public.studio st
+	WHERE s.id_studio IS NULL AND st.id_cinema = s.id_cinema;
+END $$;
+
+ALTER TABLE public.schedule ALTER COLUMN id_studio SET NOT NULL;
+ALTER TABLE public.schedule DROP COLUMN id_cinema;
+ALTER TABLE public.schedule DROP COLUMN id_location;
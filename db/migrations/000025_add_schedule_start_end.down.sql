@@ -0,0 +1,2 @@
+ALTER TABLE public.schedule DROP COLUMN end_at;
+ALTER TABLE public.schedule DROP COLUMN start_at;
@@ -0,0 +1,28 @@
+-- waktu mulai & selesai tayang yang sebenarnya.
+-- end_at = start_at + durasi movie + jeda bersih-bersih studio (15 menit),
+-- dipakai untuk mendeteksi jadwal yang bentrok di studio yang sama.
+
+ALTER TABLE public.schedule ADD start_at timestamp NULL;
+ALTER TABLE public.schedule ADD end_at timestamp NULL;
+
+-- jam mulai diambil dari nama slot time ("09.00-11.00"), durasi dari movies.duration ("130 min")
+UPDATE public.schedule s
+SET start_at = x.start_at,
+	end_at = x.start_at + make_interval(mins => x.minutes + 15)
+FROM (
+	SELECT
+		s2.id,
+		s2."date" + COALESCE(make_interval(hours => r.hm[1]::int, mins => r.hm[2]::int), INTERVAL '0') AS start_at,
+		COALESCE(NULLIF(regexp_replace(m.duration, '[^0-9]', '', 'g'), '')::int, 120) AS minutes
+	FROM public.schedule s2
+	JOIN public.movies m ON m.id = s2.id_movie
+	LEFT JOIN public."time" t ON t.id = s2.id_time
+	LEFT JOIN LATERAL regexp_match(t."name", '^\s*(\d{1,2})[.:](\d{2})') AS r(hm) ON true
+) x
+WHERE x.id = s.id;
+
+ALTER TABLE public.schedule ALTER COLUMN start_at SET NOT NULL;
+ALTER TABLE public.schedule ALTER COLUMN end_at SET NOT NULL;
+ALTER TABLE public.schedule ADD CONSTRAINT schedule_showtime_check CHECK (end_at > start_at);
+
+CREATE INDEX schedule_id_studio_start_at_idx ON public.schedule (id_studio, start_at, end_at);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS public.schedule_id_template_idx;
+ALTER TABLE public.schedule DROP COLUMN cancelled_at;
+ALTER TABLE public.schedule DROP COLUMN id_template;
+DROP TABLE IF EXISTS public.schedule_templates;
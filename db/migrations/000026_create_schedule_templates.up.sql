@@ -0,0 +1,30 @@
+-- Template jadwal berulang: movie, studio, jam mulai, hari dalam minggu,
+-- rentang tanggal & tanggal pengecualian. Template di-expand menjadi baris schedule
+-- dalam satu transaksi, jadwal hasil template bisa dibatalkan/digeser sekaligus.
+
+CREATE TABLE public.schedule_templates (
+	id int4 GENERATED ALWAYS AS IDENTITY( INCREMENT BY 1 MINVALUE 1 MAXVALUE 2147483647 START 1 CACHE 1 NO CYCLE) NOT NULL,
+	id_movie int4 NOT NULL,
+	id_studio int4 NOT NULL,
+	-- jam mulai "HH:MM"
+	start_times _text NOT NULL,
+	-- 0 = minggu ... 6 = sabtu
+	weekdays _int4 NOT NULL,
+	start_date date NOT NULL,
+	end_date date NOT NULL,
+	exceptions _date DEFAULT '{}'::date[] NOT NULL,
+	created_at timestamp DEFAULT now() NOT NULL,
+	CONSTRAINT schedule_templates_pkey PRIMARY KEY (id),
+	CONSTRAINT schedule_templates_date_check CHECK (end_date >= start_date),
+	CONSTRAINT schedule_templates_weekdays_check CHECK (weekdays <@ ARRAY[0, 1, 2, 3, 4, 5, 6])
+);
+
+ALTER TABLE public.schedule_templates ADD CONSTRAINT schedule_templates_id_movie_fkey FOREIGN KEY (id_movie) REFERENCES public.movies(id);
+ALTER TABLE public.schedule_templates ADD CONSTRAINT schedule_templates_id_studio_fkey FOREIGN KEY (id_studio) REFERENCES public.studio(id);
+
+-- jadwal tidak dihapus saat dibatalkan karena bisa sudah punya order
+ALTER TABLE public.schedule ADD id_template int4 NULL;
+ALTER TABLE public.schedule ADD cancelled_at timestamp NULL;
+ALTER TABLE public.schedule ADD CONSTRAINT schedule_id_template_fkey FOREIGN KEY (id_template) REFERENCES public.schedule_templates(id) ON DELETE SET NULL;
+
+CREATE INDEX schedule_id_template_idx ON public.schedule USING btree (id_template, start_at);
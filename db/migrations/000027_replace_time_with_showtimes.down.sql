@@ -0,0 +1,23 @@
+DROP INDEX IF EXISTS public.schedule_start_at_idx;
+
+CREATE TABLE public."time" (
+	id int4 GENERATED ALWAYS AS IDENTITY( INCREMENT BY 1 MINVALUE 1 MAXVALUE 2147483647 START 1 CACHE 1 NO CYCLE) NOT NULL,
+	"name" varchar(255) NOT NULL,
+	CONSTRAINT time_pkey PRIMARY KEY (id)
+);
+
+-- slot time dibuat ulang dari jam mulai yang dipakai jadwal
+INSERT INTO public."time" ("name")
+SELECT DISTINCT to_char(start_at AT TIME ZONE 'Asia/Jakarta', 'HH24.MI')
+FROM public.schedule
+ORDER BY 1;
+
+ALTER TABLE public.schedule ADD id_time int4 NULL;
+UPDATE public.schedule s
+SET id_time = t.id
+FROM public."time" t
+WHERE t."name" = to_char(s.start_at AT TIME ZONE 'Asia/Jakarta', 'HH24.MI');
+ALTER TABLE public.schedule ADD CONSTRAINT schedule_id_time_fkey FOREIGN KEY (id_time) REFERENCES public."time"(id);
+
+ALTER TABLE public.schedule ALTER COLUMN start_at TYPE timestamp USING start_at AT TIME ZONE 'Asia/Jakarta';
+ALTER TABLE public.schedule ALTER COLUMN end_at TYPE timestamp USING end_at AT TIME ZONE 'Asia/Jakarta';
@@ -0,0 +1,13 @@
+-- Jam tayang disimpan sebagai timestamptz di schedule, tabel "time" berisi
+-- string seperti "09.00-11.00" tidak dipakai lagi.
+-- Jadwal lama disimpan sebagai jam lokal WIB.
+
+ALTER TABLE public.schedule ALTER COLUMN start_at TYPE timestamptz USING start_at AT TIME ZONE 'Asia/Jakarta';
+ALTER TABLE public.schedule ALTER COLUMN end_at TYPE timestamptz USING end_at AT TIME ZONE 'Asia/Jakarta';
+
+-- "date" tetap disimpan sebagai tanggal tayang lokal untuk filter & batas usia
+ALTER TABLE public.schedule DROP CONSTRAINT schedule_id_time_fkey;
+ALTER TABLE public.schedule DROP COLUMN id_time;
+DROP TABLE public."time";
+
+CREATE INDEX schedule_start_at_idx ON public.schedule USING btree (start_at);
@@ -0,0 +1,9 @@
+ALTER TABLE public.schedule ADD "date" date NULL;
+UPDATE public.schedule s
+SET "date" = (s.start_at AT TIME ZONE c.time_zone)::date
+FROM public.studio st
+JOIN public.cinema c ON c.id = st.id_cinema
+WHERE st.id = s.id_studio;
+ALTER TABLE public.schedule ALTER COLUMN "date" SET NOT NULL;
+
+ALTER TABLE public.cinema DROP COLUMN time_zone;
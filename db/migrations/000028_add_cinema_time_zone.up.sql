@@ -0,0 +1,7 @@
+-- Setiap cinema punya zona waktu IANA (WIB, WITA, WIT). Jam tayang disimpan
+-- sebagai instant (timestamptz), tanggal & jam lokal dihitung dari zona cinema
+-- sehingga kolom schedule."date" tidak dipakai lagi.
+
+ALTER TABLE public.cinema ADD time_zone varchar(64) DEFAULT 'Asia/Jakarta' NOT NULL;
+
+ALTER TABLE public.schedule DROP COLUMN "date";
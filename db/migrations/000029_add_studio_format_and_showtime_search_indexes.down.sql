@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS public.cinema_coordinates_idx;
+DROP INDEX IF EXISTS public.order_seat_id_order_idx;
+DROP INDEX IF EXISTS public.orders_id_schedule_idx;
+DROP INDEX IF EXISTS public.schedule_id_movie_start_at_idx;
+
+ALTER TABLE public.studio DROP CONSTRAINT studio_format_check;
+ALTER TABLE public.studio DROP COLUMN format;
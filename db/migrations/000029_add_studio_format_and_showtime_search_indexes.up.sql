@@ -0,0 +1,10 @@
+-- Format studio (2D, 3D, IMAX, 4DX) untuk filter pencarian jadwal, dan index
+-- supaya pencarian jadwal per movie & sisa kursi per jadwal tidak scan penuh.
+
+ALTER TABLE public.studio ADD format varchar(10) DEFAULT '2D' NOT NULL;
+ALTER TABLE public.studio ADD CONSTRAINT studio_format_check CHECK (format IN ('2D', '3D', 'IMAX', '4DX'));
+
+CREATE INDEX schedule_id_movie_start_at_idx ON public.schedule USING btree (id_movie, start_at) WHERE cancelled_at IS NULL;
+CREATE INDEX orders_id_schedule_idx ON public.orders USING btree (id_schedule);
+CREATE INDEX order_seat_id_order_idx ON public.order_seat USING btree (id_order);
+CREATE INDEX cinema_coordinates_idx ON public.cinema USING btree (latitude, longitude) WHERE latitude IS NOT NULL;
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS public.price_rules;
+DROP TABLE IF EXISTS public.holidays;
+
+ALTER TABLE public.orders DROP CONSTRAINT orders_customer_category_check;
+ALTER TABLE public.orders DROP COLUMN customer_category;
+
+ALTER TABLE public.seats DROP CONSTRAINT seats_seat_type_check;
+ALTER TABLE public.seats DROP COLUMN seat_type;
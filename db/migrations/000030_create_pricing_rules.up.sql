@@ -0,0 +1,61 @@
+-- Harga tiket tidak lagi hanya cinema.price. cinema.price menjadi harga dasar, lalu
+-- price_rules yang cocok (tipe kursi, hari, jam tayang lokal, hari libur, format studio,
+-- kategori penonton) diterapkan berurutan dari priority terkecil.
+
+ALTER TABLE public.seats ADD seat_type varchar(20) DEFAULT 'regular' NOT NULL;
+ALTER TABLE public.seats ADD CONSTRAINT seats_seat_type_check CHECK (seat_type IN ('regular', 'premium', 'sweetbox'));
+
+ALTER TABLE public.orders ADD customer_category varchar(20) DEFAULT 'general' NOT NULL;
+ALTER TABLE public.orders ADD CONSTRAINT orders_customer_category_check CHECK (customer_category IN ('general', 'student', 'child', 'senior'));
+
+
+-- public.holidays definition
+
+-- Drop table
+
+-- DROP TABLE public.holidays;
+
+CREATE TABLE public.holidays (
+	"date" date NOT NULL,
+	"name" varchar(100) NOT NULL,
+	CONSTRAINT holidays_pkey PRIMARY KEY ("date")
+);
+
+
+-- public.price_rules definition
+
+-- Drop table
+
+-- DROP TABLE public.price_rules;
+
+CREATE TABLE public.price_rules (
+	id int4 GENERATED ALWAYS AS IDENTITY( INCREMENT BY 1 MINVALUE 1 MAXVALUE 2147483647 START 1 CACHE 1 NO CYCLE) NOT NULL,
+	"name" varchar(100) NOT NULL,
+	id_cinema int4 NULL,
+	seat_type varchar(20) NULL,
+	weekdays _int4 DEFAULT '{}' NOT NULL,
+	start_time time NULL,
+	end_time time NULL,
+	holiday bool NULL,
+	format varchar(10) NULL,
+	customer_category varchar(20) NULL,
+	adjustment varchar(10) NOT NULL,
+	amount numeric(12, 2) NOT NULL,
+	priority int4 DEFAULT 0 NOT NULL,
+	active bool DEFAULT true NOT NULL,
+	created_at timestamptz DEFAULT now() NOT NULL,
+	CONSTRAINT price_rules_pkey PRIMARY KEY (id),
+	CONSTRAINT price_rules_adjustment_check CHECK (adjustment IN ('set', 'add', 'percent')),
+	CONSTRAINT price_rules_amount_check CHECK (
+		(adjustment <> 'set' OR amount >= 0) AND (adjustment <> 'percent' OR amount >= -100)
+	),
+	CONSTRAINT price_rules_time_check CHECK (
+		(start_time IS NULL AND end_time IS NULL)
+		OR (start_time IS NOT NULL AND end_time IS NOT NULL AND start_time <> end_time)
+	),
+	CONSTRAINT price_rules_weekdays_check CHECK (weekdays <@ '{0,1,2,3,4,5,6}'::int4[])
+);
+
+ALTER TABLE public.price_rules ADD CONSTRAINT price_rules_id_cinema_fkey FOREIGN KEY (id_cinema) REFERENCES public.cinema(id) ON DELETE CASCADE;
+
+CREATE INDEX price_rules_id_cinema_idx ON public.price_rules USING btree (id_cinema) WHERE active;
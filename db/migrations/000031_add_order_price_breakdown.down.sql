@@ -0,0 +1,12 @@
+ALTER TABLE public.order_seat DROP COLUMN total;
+ALTER TABLE public.order_seat DROP COLUMN tax;
+ALTER TABLE public.order_seat DROP COLUMN subtotal;
+ALTER TABLE public.order_seat DROP COLUMN adjustments;
+ALTER TABLE public.order_seat DROP COLUMN base_price;
+ALTER TABLE public.order_seat DROP COLUMN seat_type;
+ALTER TABLE public.order_seat DROP COLUMN seat_code;
+
+ALTER TABLE public.orders DROP COLUMN tax_rate;
+ALTER TABLE public.orders DROP COLUMN tax_total;
+ALTER TABLE public.orders DROP COLUMN fee_total;
+ALTER TABLE public.orders DROP COLUMN subtotal;
@@ -0,0 +1,16 @@
+-- Order menyimpan rincian harga yang sama dengan quote supaya struk selalu cocok.
+-- Kolom rincian null untuk order lama yang dibuat sebelum rincian disimpan.
+
+ALTER TABLE public.orders ADD subtotal numeric(10, 2) NULL;
+ALTER TABLE public.orders ADD fee_total numeric(10, 2) NULL;
+ALTER TABLE public.orders ADD tax_total numeric(10, 2) NULL;
+ALTER TABLE public.orders ADD tax_rate numeric(5, 2) NULL;
+
+-- adjustments berisi {"modifiers": [...], "discounts": [...], "fees": [...]}
+ALTER TABLE public.order_seat ADD seat_code varchar(255) NULL;
+ALTER TABLE public.order_seat ADD seat_type varchar(20) NULL;
+ALTER TABLE public.order_seat ADD base_price numeric(10, 2) NULL;
+ALTER TABLE public.order_seat ADD adjustments jsonb DEFAULT '{}'::jsonb NOT NULL;
+ALTER TABLE public.order_seat ADD subtotal numeric(10, 2) NULL;
+ALTER TABLE public.order_seat ADD tax numeric(10, 2) NULL;
+ALTER TABLE public.order_seat ADD total numeric(10, 2) NULL;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS public.promo_redemptions;
+DROP TABLE IF EXISTS public.promo_codes;
@@ -0,0 +1,64 @@
+-- Kode promo memotong subtotal tiket order. Kode disimpan huruf besar. Array kosong
+-- pada id_movies, id_cinemas & id_payment_methods berarti berlaku untuk semua.
+
+-- public.promo_codes definition
+
+-- Drop table
+
+-- DROP TABLE public.promo_codes;
+
+CREATE TABLE public.promo_codes (
+	id int4 GENERATED ALWAYS AS IDENTITY( INCREMENT BY 1 MINVALUE 1 MAXVALUE 2147483647 START 1 CACHE 1 NO CYCLE) NOT NULL,
+	code varchar(32) NOT NULL,
+	description varchar(255) DEFAULT '' NOT NULL,
+	discount_type varchar(10) NOT NULL,
+	amount numeric(10, 2) NOT NULL,
+	min_spend numeric(10, 2) DEFAULT 0 NOT NULL,
+	max_discount numeric(10, 2) NULL,
+	valid_from timestamptz NOT NULL,
+	valid_until timestamptz NULL,
+	usage_limit int4 NULL,
+	per_user_limit int4 NULL,
+	id_movies _int4 DEFAULT '{}' NOT NULL,
+	id_cinemas _int4 DEFAULT '{}' NOT NULL,
+	id_payment_methods _int4 DEFAULT '{}' NOT NULL,
+	active bool DEFAULT true NOT NULL,
+	created_at timestamptz DEFAULT now() NOT NULL,
+	CONSTRAINT promo_codes_pkey PRIMARY KEY (id),
+	CONSTRAINT promo_codes_code_key UNIQUE (code),
+	CONSTRAINT promo_codes_discount_type_check CHECK (discount_type IN ('percent', 'fixed')),
+	CONSTRAINT promo_codes_amount_check CHECK (amount > 0 AND (discount_type <> 'percent' OR amount <= 100)),
+	CONSTRAINT promo_codes_min_spend_check CHECK (min_spend >= 0),
+	CONSTRAINT promo_codes_max_discount_check CHECK (max_discount IS NULL OR max_discount > 0),
+	CONSTRAINT promo_codes_valid_check CHECK (valid_until IS NULL OR valid_until > valid_from),
+	CONSTRAINT promo_codes_limit_check CHECK (
+		(usage_limit IS NULL OR usage_limit > 0) AND (per_user_limit IS NULL OR per_user_limit > 0)
+	)
+);
+
+
+-- public.promo_redemptions definition
+
+-- Drop table
+
+-- DROP TABLE public.promo_redemptions;
+
+CREATE TABLE public.promo_redemptions (
+	id int4 GENERATED ALWAYS AS IDENTITY( INCREMENT BY 1 MINVALUE 1 MAXVALUE 2147483647 START 1 CACHE 1 NO CYCLE) NOT NULL,
+	id_promo int4 NOT NULL,
+	id_order int4 NOT NULL,
+	id_user int4 NOT NULL,
+	discount numeric(10, 2) NOT NULL,
+	created_at timestamptz DEFAULT now() NOT NULL,
+	CONSTRAINT promo_redemptions_pkey PRIMARY KEY (id),
+	CONSTRAINT promo_redemptions_id_order_key UNIQUE (id_order)
+);
+CREATE INDEX promo_redemptions_id_promo_id_user_idx ON public.promo_redemptions USING btree (id_promo, id_user);
+CREATE INDEX promo_redemptions_created_at_idx ON public.promo_redemptions USING btree (created_at);
+
+
+-- public.promo_redemptions foreign keys
+
+ALTER TABLE public.promo_redemptions ADD CONSTRAINT promo_redemptions_id_promo_fkey FOREIGN KEY (id_promo) REFERENCES public.promo_codes(id);
+ALTER TABLE public.promo_redemptions ADD CONSTRAINT promo_redemptions_id_order_fkey FOREIGN KEY (id_order) REFERENCES public.orders(id);
+ALTER TABLE public.promo_redemptions ADD CONSTRAINT promo_redemptions_id_user_fkey FOREIGN KEY (id_user) REFERENCES public.users(id);
@@ -1,4 +0,0 @@
-INSERT INTO public."time" ("name") VALUES
-	 ('09.00-11.00'),
-	 ('11.00-13.00'),
-	 ('13.00-15.00');
@@ -1,4 +1,10 @@
-INSERT INTO public.cinema ("name",image,price) VALUES
-	 ('Cinepolis City','/images/cinema/cinepolis.jpg',45000.0),
-	 ('XXI Grand Mall','/images/cinema/xxi_grand.jpg',50000.0),
-	 ('CGV Cinemas','https://example.com/images/cgv_cinemas.jpg',55000.0);
+INSERT INTO public.cinema ("name",image,price,id_location,address,facilities,time_zone) VALUES
+	 ('Cinepolis City','/images/cinema/cinepolis.jpg',45000.0,1,'Jl. MH Thamrin No. 1, Jakarta','{Dolby Atmos}','Asia/Jakarta'),
+	 ('XXI Grand Mall','/images/cinema/xxi_grand.jpg',50000.0,1,'Jl. Jend. Sudirman No. 5, Jakarta','{IMAX,Premiere}','Asia/Jakarta'),
+	 ('CGV Cinemas','https://example.com/images/cgv_cinemas.jpg',55000.0,2,'Jl. Asia Afrika No. 10, Bandung','{4DX}','Asia/Jakarta');
+
+INSERT INTO public.studio (id_cinema,"name",capacity,format) VALUES
+	 (1,'Studio 1',NULL,'2D'),
+	 (2,'Studio 1',NULL,'2D'),
+	 (3,'Studio 1',NULL,'2D'),
+	 (2,'Studio 2',NULL,'IMAX');
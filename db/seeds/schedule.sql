@@ -1,5 +1,5 @@
-INSERT INTO public.schedule (id_movie,"date",id_cinema,id_time,id_location) VALUES
-	 (6,'2025-09-08',2,1,1),
-	 (8,'2025-09-09',3,2,2),
-	 (7,'2025-09-09',4,3,1),
-	 (33,'2025-09-10',2,2,1);
+INSERT INTO public.schedule (id_movie,id_studio,start_at,end_at) VALUES
+	 (6,2,'2025-09-08 09:00:00+07','2025-09-08 11:15:00+07'),
+	 (8,3,'2025-09-09 11:00:00+07','2025-09-09 13:15:00+07'),
+	 (7,1,'2025-09-09 13:00:00+07','2025-09-09 15:15:00+07'),
+	 (33,4,'2025-09-10 11:00:00+07','2025-09-10 13:15:00+07');
@@ -0,0 +1,21 @@
+package configs
+
+import (
+	"fmt"
+
+	"github.com/federus1105/weekly/pkg/pricing"
+	"github.com/shopspring/decimal"
+)
+
+// InitCharges membaca biaya layanan per tiket (BOOKING_FEE) dan persen pajak (TAX_RATE), default 0
+func InitCharges() (pricing.Charges, error) {
+	fee, err := decimal.NewFromString(getEnv("BOOKING_FEE", "0"))
+	if err != nil || fee.IsNegative() {
+		return pricing.Charges{}, fmt.Errorf("invalid BOOKING_FEE %q", getEnv("BOOKING_FEE", "0"))
+	}
+	rate, err := decimal.NewFromString(getEnv("TAX_RATE", "0"))
+	if err != nil || rate.IsNegative() || rate.GreaterThan(decimal.NewFromInt(100)) {
+		return pricing.Charges{}, fmt.Errorf("invalid TAX_RATE %q", getEnv("TAX_RATE", "0"))
+	}
+	return pricing.Charges{BookingFee: fee, TaxRate: rate}, nil
+}
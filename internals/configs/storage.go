@@ -0,0 +1,39 @@
+package configs
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/federus1105/weekly/pkg/storage"
+)
+
+// InitStorage memilih driver storage dari STORAGE_DRIVER (local | s3)
+func InitStorage() (storage.Storage, error) {
+	switch driver := os.Getenv("STORAGE_DRIVER"); driver {
+	case "", "local":
+		return storage.NewLocal(
+			getEnv("STORAGE_LOCAL_DIR", "public"),
+			getEnv("STORAGE_LOCAL_URL", "/img"),
+			getEnv("STORAGE_LOCAL_UPLOAD_URL", "/uploads/local"),
+			os.Getenv("STORAGE_LOCAL_SECRET"),
+		)
+	case "s3":
+		return storage.NewS3(storage.S3Config{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			Region:    os.Getenv("S3_REGION"),
+			Bucket:    os.Getenv("S3_BUCKET"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+			PathStyle: os.Getenv("S3_PATH_STYLE") != "false",
+		})
+	default:
+		return nil, fmt.Errorf("unknown STORAGE_DRIVER %q", driver)
+	}
+}
+
+func getEnv(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
@@ -1,16 +1,17 @@
 package handlers
 
 import (
+	"errors"
 	"log"
-	"net/http"
 	"regexp"
-	"strings"
 	"time"
 
 	"github.com/federus1105/weekly/internals/models"
 	"github.com/federus1105/weekly/internals/repositories"
+	"github.com/federus1105/weekly/internals/response"
 	"github.com/federus1105/weekly/internals/utils"
 	"github.com/federus1105/weekly/pkg"
+	"github.com/federus1105/weekly/pkg/apperror"
 	"github.com/gin-gonic/gin"
 	"github.com/redis/go-redis/v9"
 )
@@ -36,42 +37,17 @@ func (a *AuthHandler) Login(ctx *gin.Context) {
 	// menerima body
 	var body models.UserAuth
 	if err := ctx.ShouldBind(&body); err != nil {
-		if strings.Contains(err.Error(), "required") {
-			ctx.JSON(http.StatusBadRequest, gin.H{
-				"success": false,
-				"error":   "Nama dan Password harus diisi",
-			})
-			return
-		}
-
-		if strings.Contains(err.Error(), "min") {
-			ctx.JSON(http.StatusBadRequest, gin.H{
-				"success": false,
-				"error":   "Password minimum 4 karakter",
-			})
-			return
-		}
-		log.Println("Internal Server Error.\nCause: ", err.Error())
-		ctx.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   "internal server error",
-		})
+		response.Error(ctx, bindError(err))
 		return
 	}
 	// ambil data user
 	user, err := a.ar.GetUserWithPasswordAndRole(ctx.Request.Context(), body.Email)
 	if err != nil {
-		if strings.Contains(err.Error(), "user not found") {
-			ctx.JSON(http.StatusBadRequest, gin.H{
-				"success": false,
-				"error":   "Nama atau Password salah",
-			})
+		if errors.Is(err, apperror.ErrNotFound) {
+			response.Error(ctx, apperror.Unauthorized(apperror.CodeInvalidCredentials, ""))
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   "internal server errorr",
-		})
+		response.Error(ctx, err)
 		return
 	}
 
@@ -79,40 +55,25 @@ func (a *AuthHandler) Login(ctx *gin.Context) {
 	hc := pkg.NewHashConfig()
 	isMatched, err := hc.CompareHashAndPassword(body.Password, user.Password)
 	if err != nil {
-		log.Println("Internal Server Error.\nCause: ", err)
 		re := regexp.MustCompile("hash|crypto|argon2id|format")
 		if re.Match([]byte(err.Error())) {
 			log.Println("Error during Hashing")
 		}
-		ctx.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   "internal server error",
-		})
+		response.Error(ctx, err)
 		return
 	}
 	if !isMatched {
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Nama atau Password salah",
-		})
+		response.Error(ctx, apperror.Unauthorized(apperror.CodeInvalidCredentials, ""))
 		return
 	}
 	// jika match, maka buatkan jwt dan kirim via response
 	claims := pkg.NewJWTClaims(user.Id, user.Role)
 	jwtToken, err := claims.GenToken()
 	if err != nil {
-		log.Println("Internal Server Error.\nCause: ", err)
-		ctx.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   "internal server errorrr",
-		})
+		response.Error(ctx, err)
 		return
 	}
-	ctx.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"token":   jwtToken,
-	})
-
+	response.OK(ctx, gin.H{"token": jwtToken})
 }
 
 // Register godoc
@@ -126,83 +87,65 @@ func (a *AuthHandler) Login(ctx *gin.Context) {
 func (a *AuthHandler) Register(ctx *gin.Context) {
 	var body models.UserRegister
 	if err := ctx.ShouldBindJSON(&body); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Invalid request body",
-		})
+		response.Error(ctx, bindError(err))
 		return
 	}
-	newOrder, err := a.ar.Register(ctx.Request.Context(), body)
+	newUser, err := a.ar.Register(ctx.Request.Context(), body)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   err.Error(),
-		})
+		response.Error(ctx, err)
 		return
 	}
-	// You can add further logic to process the order here
-	ctx.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"order":   newOrder,
-	})
+	// password hash tidak perlu dikirim ke client
+	newUser.Password = ""
+	response.Created(ctx, newUser)
 }
 
 func (a *AuthHandler) ResetPassword(c *gin.Context) {
 	var body models.ChangePassword
 	if err := c.ShouldBindJSON(&body); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		response.Error(c, bindError(err))
 		return
 	}
 
 	// mengambil user yang lagi login sekarang
-	userIDInterface, exists := c.Get("user_id")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
-		return
-	}
-
-	userID, ok := userIDInterface.(int)
-	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal error"})
+	userID, err := userIDFromContext(c)
+	if err != nil {
+		response.Error(c, err)
 		return
 	}
 
-	err := a.ar.ResetPassword(c.Request.Context(), userID, body.OldPassword, body.NewPassword)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if err := a.ar.ResetPassword(c.Request.Context(), userID, body.OldPassword, body.NewPassword); err != nil {
+		response.Error(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Password berhasil direset"})
+	response.OKMessage(c, "PASSWORD_RESET", nil)
 }
 
 func (a *AuthHandler) Logout(ctx *gin.Context) {
 	token := utils.GetTokenFromHeader(ctx)
 	if token == "" {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Token tidak ditemukan"})
+		response.Error(ctx, apperror.Unauthorized(apperror.CodeTokenMissing, ""))
 		return
 	}
 
 	expiry := utils.GetTokenExpiry(token)
 	if expiry.IsZero() {
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"error": "Token tidak valid atau tidak memiliki expiry"})
+		response.Error(ctx, apperror.Unauthorized(apperror.CodeTokenInvalid, ""))
 		return
 	}
 
 	duration := time.Until(expiry)
 	if duration <= 0 {
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"error": "Token sudah kedaluwarsa"})
+		response.Error(ctx, apperror.Unauthorized(apperror.CodeTokenExpired, ""))
 		return
 	}
 
 	// Simpan token ke Redis blacklist dengan TTL sesuai sisa masa berlaku token
-	err := a.redisClient.Set(ctx, "blacklist:"+token, true, duration).Err()
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal logout"})
+	if err := a.redisClient.Set(ctx, "blacklist:"+token, true, duration).Err(); err != nil {
+		response.Error(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"message": "Berhasil logout"})
+	response.OKMessage(ctx, "LOGGED_OUT", nil)
 }
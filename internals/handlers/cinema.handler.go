@@ -0,0 +1,243 @@
+package handlers
+
+import (
+	"github.com/federus1105/weekly/internals/models"
+	"github.com/federus1105/weekly/internals/repositories"
+	"github.com/federus1105/weekly/internals/response"
+	"github.com/federus1105/weekly/pkg/apperror"
+	"github.com/gin-gonic/gin"
+)
+
+type CinemaHandler struct {
+	cr *repositories.CinemaRepository
+}
+
+func NewCinemaHandler(cr *repositories.CinemaRepository) *CinemaHandler {
+	return &CinemaHandler{cr: cr}
+}
+
+// GetLocations godoc
+// @Summary Get locations
+// @Tags Cinemas
+// @Produce json
+// @Success 200 {object} response.Envelope
+// @Router /locations [get]
+func (ch *CinemaHandler) GetLocations(ctx *gin.Context) {
+	locations, err := ch.cr.GetLocations(ctx.Request.Context())
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.List(ctx, locations, nil)
+}
+
+// CreateLocation godoc
+// @Summary Create location
+// @Tags Cinemas
+// @Accept json
+// @Produce json
+// @Param body body models.LocationBody true "Location"
+// @Success 201 {object} response.Envelope
+// @Security BearerAuth
+// @Router /locations [post]
+func (ch *CinemaHandler) CreateLocation(ctx *gin.Context) {
+	var body models.LocationBody
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		response.Error(ctx, bindError(err))
+		return
+	}
+	location, err := ch.cr.CreateLocation(ctx.Request.Context(), body.Name)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.Created(ctx, location)
+}
+
+// GetCinemas godoc
+// @Summary Get cinemas
+// @Tags Cinemas
+// @Produce json
+// @Param location query int false "Location ID"
+// @Success 200 {object} response.Envelope
+// @Router /cinemas [get]
+func (ch *CinemaHandler) GetCinemas(ctx *gin.Context) {
+	var filter models.CinemaFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		response.Error(ctx, apperror.BadRequest(apperror.CodeInvalidFilter, "").Wrap(err))
+		return
+	}
+	cinemas, err := ch.cr.GetCinemas(ctx.Request.Context(), filter)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.List(ctx, cinemas, nil)
+}
+
+// GetCinema godoc
+// @Summary Get cinema detail with studios
+// @Tags Cinemas
+// @Produce json
+// @Param id path int true "Cinema ID"
+// @Success 200 {object} response.Envelope
+// @Router /cinemas/{id} [get]
+func (ch *CinemaHandler) GetCinema(ctx *gin.Context) {
+	cinemaID, err := paramID(ctx, "id")
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	cinema, err := ch.cr.GetCinema(ctx.Request.Context(), cinemaID)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.OK(ctx, cinema)
+}
+
+// CreateCinema godoc
+// @Summary Create cinema
+// @Description Cinema selalu berada di satu location. latitude & longitude diisi berpasangan. time_zone adalah zona waktu IANA (default Asia/Jakarta), jam tayang ditampilkan dengan offset zona ini.
+// @Tags Cinemas
+// @Accept json
+// @Produce json
+// @Param body body models.CinemaBody true "Cinema"
+// @Success 201 {object} response.Envelope
+// @Security BearerAuth
+// @Router /cinemas [post]
+func (ch *CinemaHandler) CreateCinema(ctx *gin.Context) {
+	var body models.CinemaBody
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		response.Error(ctx, bindError(err))
+		return
+	}
+	cinema, err := ch.cr.CreateCinema(ctx.Request.Context(), body)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.Created(ctx, cinema)
+}
+
+// UpdateCinema godoc
+// @Summary Edit cinema
+// @Description Mengubah time_zone tidak menggeser jadwal yang sudah ada, hanya jam lokal yang ditampilkan.
+// @Tags Cinemas
+// @Accept json
+// @Produce json
+// @Param id path int true "Cinema ID"
+// @Param body body models.CinemaUpdateBody true "Cinema"
+// @Success 200 {object} response.Envelope
+// @Security BearerAuth
+// @Router /cinemas/{id} [patch]
+func (ch *CinemaHandler) UpdateCinema(ctx *gin.Context) {
+	cinemaID, err := paramID(ctx, "id")
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	var body models.CinemaUpdateBody
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		response.Error(ctx, bindError(err))
+		return
+	}
+	cinema, err := ch.cr.UpdateCinema(ctx.Request.Context(), cinemaID, body)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.OK(ctx, cinema)
+}
+
+// CreateStudio godoc
+// @Summary Add studio to cinema
+// @Tags Cinemas
+// @Accept json
+// @Produce json
+// @Param id path int true "Cinema ID"
+// @Param body body models.StudioBody true "Studio"
+// @Success 201 {object} response.Envelope
+// @Security BearerAuth
+// @Router /cinemas/{id}/studios [post]
+func (ch *CinemaHandler) CreateStudio(ctx *gin.Context) {
+	cinemaID, err := paramID(ctx, "id")
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	var body models.StudioBody
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		response.Error(ctx, bindError(err))
+		return
+	}
+	studio, err := ch.cr.CreateStudio(ctx.Request.Context(), cinemaID, body)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.Created(ctx, studio)
+}
+
+// UpdateStudio godoc
+// @Summary Edit studio
+// @Tags Cinemas
+// @Accept json
+// @Produce json
+// @Param id path int true "Cinema ID"
+// @Param studio_id path int true "Studio ID"
+// @Param body body models.StudioUpdateBody true "Studio"
+// @Success 200 {object} response.Envelope
+// @Security BearerAuth
+// @Router /cinemas/{id}/studios/{studio_id} [patch]
+func (ch *CinemaHandler) UpdateStudio(ctx *gin.Context) {
+	cinemaID, err := paramID(ctx, "id")
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	studioID, err := paramID(ctx, "studio_id")
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	var body models.StudioUpdateBody
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		response.Error(ctx, bindError(err))
+		return
+	}
+	studio, err := ch.cr.UpdateStudio(ctx.Request.Context(), cinemaID, studioID, body)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.OK(ctx, studio)
+}
+
+// DeleteStudio godoc
+// @Summary Delete studio
+// @Description Studio yang sudah punya jadwal tidak bisa dihapus
+// @Tags Cinemas
+// @Produce json
+// @Param cinema_id path int true "Cinema ID"
+// @Param studio_id path int true "Studio ID"
+// @Success 200 {object} response.Envelope
+// @Security BearerAuth
+// @Router /cinemas/{cinema_id}/studios/{studio_id} [delete]
+func (ch *CinemaHandler) DeleteStudio(ctx *gin.Context) {
+	cinemaID, err := paramID(ctx, "cinema_id")
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	studioID, err := paramID(ctx, "studio_id")
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	if err := ch.cr.DeleteStudio(ctx.Request.Context(), cinemaID, studioID); err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.OKMessage(ctx, "STUDIO_DELETED", gin.H{"id": studioID})
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/federus1105/weekly/pkg"
+	"github.com/federus1105/weekly/pkg/apperror"
+	"github.com/gin-gonic/gin"
+)
+
+// userIDFromContext mengambil user id yang di-set oleh AuthMiddleware
+func userIDFromContext(ctx *gin.Context) (int, error) {
+	userIDRaw, exists := ctx.Get("user_id")
+	if !exists {
+		return 0, apperror.Unauthorized(apperror.CodeUnauthorized, "")
+	}
+	userID, ok := userIDRaw.(int)
+	if !ok {
+		return 0, errors.New("invalid user id type in context")
+	}
+	return userID, nil
+}
+
+// claimsFromContext mengambil claims JWT yang di-set oleh VerifyToken
+func claimsFromContext(ctx *gin.Context) (pkg.Claims, error) {
+	claims, exists := ctx.Get("claims")
+	if !exists {
+		return pkg.Claims{}, apperror.Unauthorized(apperror.CodeTokenMissing, "")
+	}
+	user, ok := claims.(pkg.Claims)
+	if !ok {
+		return pkg.Claims{}, errors.New("cannot cast claims into pkg.Claims")
+	}
+	return user, nil
+}
+
+// paramID membaca path param numerik
+func paramID(ctx *gin.Context, name string) (int, error) {
+	id, err := strconv.Atoi(ctx.Param(name))
+	if err != nil || id < 1 {
+		return 0, apperror.BadRequest(apperror.CodeInvalidID, "")
+	}
+	return id, nil
+}
+
+// bindError membungkus error dari ShouldBind menjadi validation error
+func bindError(err error) error {
+	return apperror.Validation(apperror.CodeValidation, "").Wrap(err)
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/federus1105/weekly/internals/response"
+	"github.com/federus1105/weekly/internals/utils"
+	"github.com/federus1105/weekly/pkg/apperror"
+	"github.com/federus1105/weekly/pkg/storage"
+	"github.com/gin-gonic/gin"
+)
+
+// lama berlaku URL yang diberikan ke client
+const signedURLTTL = 15 * time.Minute
+
+// servableExts adalah ekstensi variant hasil imaging.Process, hanya ini yang disajikan
+var servableExts = map[string]bool{".jpg": true, ".png": true, ".webp": true}
+
+// publicKey menormalkan key dari URL seperti yang dilakukan http.FileServer lalu hanya
+// menerima gambar hasil proses. File mentah di staging upload tidak pernah disajikan.
+func publicKey(raw string) (string, bool) {
+	key := strings.TrimPrefix(path.Clean("/"+raw), "/")
+	if strings.HasPrefix(key, utils.UploadStagingPrefix) || !servableExts[path.Ext(key)] {
+		return "", false
+	}
+	return key, true
+}
+
+type FileHandler struct {
+	store storage.Storage
+}
+
+func NewFileHandler(store storage.Storage) *FileHandler {
+	return &FileHandler{store: store}
+}
+
+// Redirect mengarahkan /img/<key> ke signed URL storage, supaya URL gambar
+// di frontend tetap sama walaupun file tidak ada di disk server
+func (fh *FileHandler) Redirect(ctx *gin.Context) {
+	key, ok := publicKey(ctx.Param("key"))
+	if !ok {
+		response.Error(ctx, apperror.NotFound(apperror.CodeNotFound, ""))
+		return
+	}
+	url, err := fh.store.SignedURL(ctx.Request.Context(), key, signedURLTTL)
+	if err != nil {
+		if errors.Is(err, storage.ErrInvalidKey) {
+			response.Error(ctx, apperror.NotFound(apperror.CodeNotFound, ""))
+			return
+		}
+		response.Error(ctx, err)
+		return
+	}
+	ctx.Header("Cache-Control", "private, max-age=600")
+	ctx.Redirect(http.StatusFound, url)
+}
+
+// ServeLocal menyajikan file dari driver lokal. File mentah hasil presigned
+// upload belum diproses sehingga tidak boleh disajikan ke publik
+func (fh *FileHandler) ServeLocal(ctx *gin.Context) {
+	local, isLocal := fh.store.(*storage.Local)
+	key, ok := publicKey(ctx.Param("key"))
+	if !isLocal || !ok {
+		response.Error(ctx, apperror.NotFound(apperror.CodeNotFound, ""))
+		return
+	}
+	ctx.Header("X-Content-Type-Options", "nosniff")
+	ctx.FileFromFS(key, gin.Dir(local.Root, false))
+}
+
+// LocalUpload menerima PUT dari URL hasil PresignPut driver lokal
+func (fh *FileHandler) LocalUpload(ctx *gin.Context) {
+	local, ok := fh.store.(*storage.Local)
+	if !ok {
+		response.Error(ctx, apperror.NotFound(apperror.CodeRouteNotFound, ""))
+		return
+	}
+	key := strings.TrimPrefix(ctx.Param("key"), "/")
+	if !strings.HasPrefix(key, utils.UploadStagingPrefix) {
+		response.Error(ctx, apperror.Forbidden(apperror.CodeForbidden, ""))
+		return
+	}
+	if err := local.VerifyPut(key, ctx.Query("expires"), ctx.Query("signature")); err != nil {
+		response.Error(ctx, apperror.Forbidden(apperror.CodeForbidden, ""))
+		return
+	}
+	if ctx.Request.ContentLength > utils.MaxDirectUploadSize {
+		response.Error(ctx, utils.FileTooLarge(utils.MaxDirectUploadSize))
+		return
+	}
+
+	body := http.MaxBytesReader(ctx.Writer, ctx.Request.Body, utils.MaxDirectUploadSize)
+	err := local.Put(ctx.Request.Context(), key, body, ctx.Request.ContentLength, ctx.ContentType())
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			response.Error(ctx, utils.FileTooLarge(utils.MaxDirectUploadSize))
+			return
+		}
+		response.Error(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusOK)
+}
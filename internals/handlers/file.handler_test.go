@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/federus1105/weekly/pkg/storage"
+	"github.com/gin-gonic/gin"
+)
+
+func TestServeLocalHidesStagedUploads(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	root := t.TempDir()
+	for name, data := range map[string]string{
+		"uploads/abc":    "<script>alert(1)</script>",
+		"123_poster.png": "png",
+	} {
+		full := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	store, err := storage.NewLocal(root, "/img", "http://localhost/uploads/local", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	router := gin.New()
+	router.GET("/img/*key", NewFileHandler(store).ServeLocal)
+
+	tests := []struct {
+		path string
+		want int
+	}{
+		{"/img/123_poster.png", http.StatusOK},
+		{"/img/./123_poster.png", http.StatusOK},
+		{"/img/uploads/abc", http.StatusNotFound},
+		{"/img//uploads/abc", http.StatusNotFound},
+		{"/img/./uploads/abc", http.StatusNotFound},
+		{"/img/x/../uploads/abc", http.StatusNotFound},
+		{"/img/uploads/abc.png", http.StatusNotFound},
+		{"/img/../go.mod", http.StatusNotFound},
+		{"/img/", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		// path mentah supaya tidak dinormalkan lebih dulu oleh httptest
+		req.URL.Path = tt.path
+		router.ServeHTTP(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("GET %s = %d, want %d", tt.path, rec.Code, tt.want)
+		}
+		if rec.Code == http.StatusOK && rec.Header().Get("X-Content-Type-Options") != "nosniff" {
+			t.Errorf("GET %s without nosniff", tt.path)
+		}
+	}
+}
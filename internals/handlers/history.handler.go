@@ -1,9 +1,10 @@
 package handlers
 
 import (
-	"net/http"
-
 	"github.com/federus1105/weekly/internals/repositories"
+	"github.com/federus1105/weekly/internals/response"
+	"github.com/federus1105/weekly/pkg/apperror"
+	"github.com/federus1105/weekly/pkg/pagination"
 	"github.com/gin-gonic/gin"
 )
 
@@ -16,47 +17,36 @@ func NewHistoryHandler(hr *repositories.HistoryRepository) *HistoryHandler {
 }
 
 // GetHistory godoc
-// @Summary Get History by ID
-// @Description Mengambil detail histori berdasarkan ID
+// @Summary Get order history
+// @Description Mengambil histori order milik user yang login
 // @Tags History
 // @Produce json
-// @Param id path int true "ID History"
-// @Success 200 {object} map[string]interface{}
+// @Param cursor query string false "Cursor"
+// @Param page query int false "Page"
+// @Param limit query int false "Page size"
+// @Success 200 {object} response.Envelope
 // @Security BearerAuth
-// @Router /history/{id} [get]
+// @Router /history [get]
 func (hr *HistoryHandler) GetHistory(ctx *gin.Context) {
 	// Ambil user_id dari Gin Context
-	userIDRaw, exists := ctx.Get("user_id")
-	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{
-			"success": false,
-			"message": "Unauthorized: user ID tidak ditemukan di context",
-		})
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		response.Error(ctx, err)
 		return
 	}
 
-	userID, ok := userIDRaw.(int)
-	if !ok {
-		ctx.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"message": "User ID dalam context tidak valid",
-		})
+	req, err := pagination.Parse(ctx, 10, 50)
+	if err != nil {
+		response.Error(ctx, apperror.BadRequest(apperror.CodeInvalidCursor, ""))
 		return
 	}
 
 	// Kirim userID ke repository
-	profiles, err := hr.hr.GetHistory(ctx.Request.Context(), userID)
+	histories, meta, err := hr.hr.GetHistory(ctx.Request.Context(), userID, req)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"message": "Gagal mengambil data History",
-			"error":   err.Error(),
-		})
+		response.Error(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    profiles,
-	})
+	response.List(ctx, histories, meta)
 }
@@ -0,0 +1,290 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/federus1105/weekly/internals/models"
+	"github.com/federus1105/weekly/internals/repositories"
+	"github.com/federus1105/weekly/internals/response"
+	"github.com/federus1105/weekly/internals/utils"
+	"github.com/federus1105/weekly/pkg/apperror"
+	"github.com/federus1105/weekly/pkg/imaging"
+	"github.com/federus1105/weekly/pkg/storage"
+	"github.com/gin-gonic/gin"
+)
+
+type MediaHandler struct {
+	mdr   *repositories.MediaRepository
+	store storage.Storage
+}
+
+func NewMediaHandler(mdr *repositories.MediaRepository, store storage.Storage) *MediaHandler {
+	return &MediaHandler{mdr: mdr, store: store}
+}
+
+// GetMovieMedia godoc
+// @Summary Get movie media gallery
+// @Tags Media
+// @Produce json
+// @Param id path int true "Movie ID"
+// @Success 200 {object} response.Envelope
+// @Router /movies/{id}/media [get]
+func (mh *MediaHandler) GetMovieMedia(ctx *gin.Context) {
+	movieID, err := paramID(ctx, "id")
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	media, err := mh.mdr.GetMovieMedia(ctx.Request.Context(), movieID)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.OK(ctx, media)
+}
+
+// CreateTrailer godoc
+// @Summary Add movie trailer
+// @Description Provider diisi otomatis dari URL (youtube, vimeo, other) jika kosong. Duration dalam detik.
+// @Tags Media
+// @Accept json
+// @Produce json
+// @Param id path int true "Movie ID"
+// @Param body body models.TrailerBody true "Trailer"
+// @Success 201 {object} response.Envelope
+// @Security BearerAuth
+// @Router /movies/{id}/trailers [post]
+func (mh *MediaHandler) CreateTrailer(ctx *gin.Context) {
+	movieID, err := paramID(ctx, "id")
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	var body models.TrailerBody
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		response.Error(ctx, bindError(err))
+		return
+	}
+	if body.Provider == "" {
+		body.Provider = models.DetectProvider(body.URL)
+	}
+
+	trailer, err := mh.mdr.CreateTrailer(ctx.Request.Context(), movieID, body)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.Created(ctx, trailer)
+}
+
+// UpdateTrailer godoc
+// @Summary Edit movie trailer
+// @Tags Media
+// @Accept json
+// @Produce json
+// @Param id path int true "Movie ID"
+// @Param trailer_id path int true "Trailer ID"
+// @Param body body models.TrailerUpdateBody true "Trailer"
+// @Success 200 {object} response.Envelope
+// @Security BearerAuth
+// @Router /movies/{id}/trailers/{trailer_id} [patch]
+func (mh *MediaHandler) UpdateTrailer(ctx *gin.Context) {
+	movieID, err := paramID(ctx, "id")
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	trailerID, err := paramID(ctx, "trailer_id")
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	var body models.TrailerUpdateBody
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		response.Error(ctx, bindError(err))
+		return
+	}
+	// URL baru tanpa provider, tentukan ulang provider dari URL
+	if body.URL != nil && body.Provider == nil {
+		provider := models.DetectProvider(*body.URL)
+		body.Provider = &provider
+	}
+
+	trailer, err := mh.mdr.UpdateTrailer(ctx.Request.Context(), movieID, trailerID, body)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.OK(ctx, trailer)
+}
+
+// DeleteTrailer godoc
+// @Summary Delete movie trailer
+// @Tags Media
+// @Produce json
+// @Param movie_id path int true "Movie ID"
+// @Param trailer_id path int true "Trailer ID"
+// @Success 200 {object} response.Envelope
+// @Security BearerAuth
+// @Router /movies/{movie_id}/trailers/{trailer_id} [delete]
+func (mh *MediaHandler) DeleteTrailer(ctx *gin.Context) {
+	movieID, err := paramID(ctx, "movie_id")
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	trailerID, err := paramID(ctx, "trailer_id")
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	if err := mh.mdr.DeleteTrailer(ctx.Request.Context(), movieID, trailerID); err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.OKMessage(ctx, "TRAILER_DELETED", gin.H{"id": trailerID})
+}
+
+// CreateStill godoc
+// @Summary Add movie still
+// @Tags Media
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Movie ID"
+// @Param image formData file true "Still image"
+// @Param caption formData string false "Caption"
+// @Param position formData int false "Position"
+// @Success 201 {object} response.Envelope
+// @Security BearerAuth
+// @Router /movies/{id}/stills [post]
+func (mh *MediaHandler) CreateStill(ctx *gin.Context) {
+	movieID, err := paramID(ctx, "id")
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	var body models.StillBody
+	if err := ctx.ShouldBind(&body); err != nil {
+		response.Error(ctx, bindError(err))
+		return
+	}
+	if body.Image == nil {
+		response.Error(ctx, apperror.Validation(apperror.CodeFileRequired, ""))
+		return
+	}
+
+	// still memakai ukuran yang sama dengan backdrop
+	still, err := utils.PrepareImage(body.Image, fmt.Sprintf("still_%d", movieID), imaging.BackdropPreset)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	storeImage := func(rctx context.Context) error {
+		return utils.StoreImages(rctx, mh.store, still)
+	}
+
+	result, err := mh.mdr.CreateStill(ctx.Request.Context(), movieID, still.Key, body.Caption, body.Position, storeImage)
+	if err != nil {
+		utils.DiscardImages(mh.store, still)
+		response.Error(ctx, err)
+		return
+	}
+	response.Created(ctx, result)
+}
+
+// UpdateStill godoc
+// @Summary Edit movie still caption or position
+// @Tags Media
+// @Accept json
+// @Produce json
+// @Param id path int true "Movie ID"
+// @Param still_id path int true "Still ID"
+// @Param body body models.StillUpdateBody true "Still"
+// @Success 200 {object} response.Envelope
+// @Security BearerAuth
+// @Router /movies/{id}/stills/{still_id} [patch]
+func (mh *MediaHandler) UpdateStill(ctx *gin.Context) {
+	movieID, err := paramID(ctx, "id")
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	stillID, err := paramID(ctx, "still_id")
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	var body models.StillUpdateBody
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		response.Error(ctx, bindError(err))
+		return
+	}
+
+	still, err := mh.mdr.UpdateStill(ctx.Request.Context(), movieID, stillID, body)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.OK(ctx, still)
+}
+
+// ReorderStills godoc
+// @Summary Reorder movie stills
+// @Description ids harus berisi semua still movie dengan urutan yang baru
+// @Tags Media
+// @Accept json
+// @Produce json
+// @Param id path int true "Movie ID"
+// @Param body body models.StillOrderBody true "Still IDs"
+// @Success 200 {object} response.Envelope
+// @Security BearerAuth
+// @Router /movies/{id}/stills/order [put]
+func (mh *MediaHandler) ReorderStills(ctx *gin.Context) {
+	movieID, err := paramID(ctx, "id")
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	var body models.StillOrderBody
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		response.Error(ctx, bindError(err))
+		return
+	}
+
+	stills, err := mh.mdr.ReorderStills(ctx.Request.Context(), movieID, body.IDs)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.OK(ctx, stills)
+}
+
+// DeleteStill godoc
+// @Summary Delete movie still
+// @Tags Media
+// @Produce json
+// @Param movie_id path int true "Movie ID"
+// @Param still_id path int true "Still ID"
+// @Success 200 {object} response.Envelope
+// @Security BearerAuth
+// @Router /movies/{movie_id}/stills/{still_id} [delete]
+func (mh *MediaHandler) DeleteStill(ctx *gin.Context) {
+	movieID, err := paramID(ctx, "movie_id")
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	stillID, err := paramID(ctx, "still_id")
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	image, err := mh.mdr.DeleteStill(ctx.Request.Context(), movieID, stillID)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	utils.DeleteImage(mh.store, image)
+	response.OKMessage(ctx, "STILL_DELETED", gin.H{"id": stillID})
+}
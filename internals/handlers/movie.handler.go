@@ -1,25 +1,32 @@
 package handlers
 
 import (
+	"context"
 	"fmt"
 	"log"
-	"net/http"
 	"strconv"
 	"strings"
 
 	"github.com/federus1105/weekly/internals/models"
 	"github.com/federus1105/weekly/internals/repositories"
+	"github.com/federus1105/weekly/internals/response"
 	"github.com/federus1105/weekly/internals/utils"
-	"github.com/federus1105/weekly/pkg"
+	"github.com/federus1105/weekly/pkg/apperror"
+	"github.com/federus1105/weekly/pkg/imaging"
+	"github.com/federus1105/weekly/pkg/pagination"
+	"github.com/federus1105/weekly/pkg/storage"
+	"github.com/federus1105/weekly/pkg/validation"
 	"github.com/gin-gonic/gin"
 )
 
 type movieHandler struct {
-	mr *repositories.MoviesRepository
+	mr    *repositories.MoviesRepository
+	ur    *repositories.UploadRepository
+	store storage.Storage
 }
 
-func NewMovieHandler(mr *repositories.MoviesRepository) *movieHandler {
-	return &movieHandler{mr: mr}
+func NewMovieHandler(mr *repositories.MoviesRepository, ur *repositories.UploadRepository, store storage.Storage) *movieHandler {
+	return &movieHandler{mr: mr, ur: ur, store: store}
 }
 
 // GetUpcomingMovies godoc
@@ -27,37 +34,24 @@ func NewMovieHandler(mr *repositories.MoviesRepository) *movieHandler {
 // @Tags Movies
 // @Produce json
 // @Param page query int false "Page"
-// @Success 200 {object} map[string]interface{}
+// @Param cursor query string false "Cursor"
+// @Param limit query int false "Page size"
+// @Success 200 {object} response.Envelope
 // @Router /movies/upcoming [get]
 func (mh *movieHandler) GetUpcomingMovies(ctx *gin.Context) {
 	// pagination
-	page, err := strconv.Atoi(ctx.Query("page"))
+	req, err := pagination.Parse(ctx, 5, 20)
 	if err != nil {
-		page = 1
+		response.Error(ctx, apperror.BadRequest(apperror.CodeInvalidCursor, ""))
+		return
 	}
-	limit := 5
-	offset := (page - 1) * limit
 
-	movies, err := mh.mr.GetUpcomingMovies(ctx.Request.Context(), limit, offset)
+	movies, meta, err := mh.mr.GetUpcomingMovies(ctx.Request.Context(), req)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{
-			"succes": false,
-			"data":   movies,
-		})
-		return
-	}
-	if len(movies) == 0 {
-		ctx.JSON(http.StatusOK, gin.H{
-			"success": true,
-			"data":    []string{},
-			"message": "Tidak ada data movie",
-		})
+		response.Error(ctx, err)
 		return
 	}
-	ctx.JSON(http.StatusOK, gin.H{
-		"succes": true,
-		"data":   movies,
-	})
+	response.List(ctx, movies, meta)
 }
 
 // GetPopularMovies godoc
@@ -65,36 +59,84 @@ func (mh *movieHandler) GetUpcomingMovies(ctx *gin.Context) {
 // @Tags Movies
 // @Produce json
 // @Param page query int false "Page"
-// @Success 200 {object} map[string]interface{}
-// @Router /movies/popular [get]|
+// @Param cursor query string false "Cursor"
+// @Param limit query int false "Page size"
+// @Success 200 {object} response.Envelope
+// @Router /movies/popular [get]
 func (mh *movieHandler) GetPopularMovies(ctx *gin.Context) {
-	page, err := strconv.Atoi(ctx.Query("page"))
+	req, err := pagination.Parse(ctx, 5, 20)
+	if err != nil {
+		response.Error(ctx, apperror.BadRequest(apperror.CodeInvalidCursor, ""))
+		return
+	}
+	movies, meta, err := mh.mr.GetPopularMovies(ctx.Request.Context(), req)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.List(ctx, movies, meta)
+}
 
+// GetNowShowingMovies godoc
+// @Summary Get now showing movies
+// @Description Movie dengan jadwal mendatang beserta beberapa jadwal terdekat
+// @Tags Movies
+// @Produce json
+// @Param location query int false "Location ID"
+// @Param cinema query int false "Cinema ID"
+// @Param date query string false "Tanggal tayang lokal (YYYY-MM-DD)"
+// @Param page query int false "Page"
+// @Param cursor query string false "Cursor"
+// @Param limit query int false "Page size"
+// @Success 200 {object} response.Envelope
+// @Router /movies/now-showing [get]
+func (mh *movieHandler) GetNowShowingMovies(ctx *gin.Context) {
+	var filter models.NowShowingFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		response.Error(ctx, apperror.BadRequest(apperror.CodeInvalidFilter, "").Wrap(err))
+		return
+	}
+	req, err := pagination.Parse(ctx, 5, 20)
 	if err != nil {
-		page = 1
+		response.Error(ctx, apperror.BadRequest(apperror.CodeInvalidCursor, ""))
+		return
 	}
-	limit := 5
-	offset := (page - 1) * limit
-	movies, err := mh.mr.GetPopularMovies(ctx.Request.Context(), limit, offset)
+	movies, meta, err := mh.mr.GetNowShowingMovies(ctx.Request.Context(), filter, req)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"data":    movies,
-		})
+		response.Error(ctx, err)
 		return
 	}
-	if len(movies) == 0 {
-		ctx.JSON(http.StatusOK, gin.H{
-			"success": true,
-			"data":    []string{},
-			"message": "Tidak ada data movie",
-		})
+	response.List(ctx, movies, meta)
+}
+
+// GetRecommendedMovies godoc
+// @Summary Get recommended movies
+// @Description Movie yang sedang tayang diurutkan dari kemiripan genre, sutradara dan aktor dengan histori order user serta popularitas. User tanpa histori mendapat movie populer.
+// @Tags Movies
+// @Produce json
+// @Param cursor query string false "Cursor"
+// @Param page query int false "Page"
+// @Param limit query int false "Page size"
+// @Success 200 {object} response.Envelope
+// @Security BearerAuth
+// @Router /movies/recommended [get]
+func (mh *movieHandler) GetRecommendedMovies(ctx *gin.Context) {
+	user, err := claimsFromContext(ctx)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	req, err := pagination.Parse(ctx, 10, 20)
+	if err != nil {
+		response.Error(ctx, apperror.BadRequest(apperror.CodeInvalidCursor, ""))
+		return
+	}
+	movies, meta, err := mh.mr.GetRecommendedMovies(ctx.Request.Context(), user.UserId, req)
+	if err != nil {
+		response.Error(ctx, err)
 		return
 	}
-	ctx.JSON(http.StatusOK, gin.H{
-		"succes": true,
-		"data":   movies,
-	})
+	response.List(ctx, movies, meta)
 }
 
 // GetDetailMovie godoc
@@ -102,143 +144,155 @@ func (mh *movieHandler) GetPopularMovies(ctx *gin.Context) {
 // @Tags Movies
 // @Produce json
 // @Param id path int true "Movie Detail"
-// @Success 200 {object} map[string]interface{}
+// @Param cursor query string false "Review cursor"
+// @Param limit query int false "Review page size"
+// @Success 200 {object} response.Envelope
 // @Security BearerAuth
 // @Router /movies/{id} [get]
 func (mh *movieHandler) GetDetailMovie(ctx *gin.Context) {
-	movieIDStr := ctx.Param("id")
-	movieID, err := strconv.Atoi(movieIDStr)
+	movieID, err := paramID(ctx, "id")
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"message": "ID Movie tidak valid",
-		})
+		response.Error(ctx, err)
 		return
 	}
-	movies, err := mh.mr.GetDetailMovie(ctx.Request.Context(), movieID)
+	// pagination review terbaru di detail movie
+	reviewReq, err := pagination.Parse(ctx, defaultReviewLimit, maxReviewLimit)
+	if err != nil {
+		response.Error(ctx, apperror.BadRequest(apperror.CodeInvalidCursor, ""))
+		return
+	}
+	movies, err := mh.mr.GetDetailMovie(ctx.Request.Context(), movieID, reviewReq)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"message": "Gagal mengambil data Movie",
-		})
 		log.Println("Error GetDetailMovie:", err)
+		response.Error(ctx, err)
+		return
+	}
+	if len(movies) == 0 {
+		response.Error(ctx, apperror.NotFound(apperror.CodeMovieNotFound, ""))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    movies,
-	})
+	response.OK(ctx, movies)
 }
 
+// batas page size katalog movie
+const (
+	defaultMovieLimit = 20
+	maxMovieLimit     = 50
+)
+
+var movieSorts = map[string]bool{"title": true, "release_date": true, "rating": true, "popularity": true}
+
+// GetAllMovie godoc
+// @Summary Get movie catalog
+// @Tags Movies
+// @Produce json
+// @Param title query string false "Title"
+// @Param genre query []string false "Genre" collectionFormat(multi)
+// @Param release_from query string false "Release date from (YYYY-MM-DD)"
+// @Param release_to query string false "Release date to (YYYY-MM-DD)"
+// @Param rating_min query number false "Minimum rating"
+// @Param rating_max query number false "Maximum rating"
+// @Param duration_min query int false "Minimum duration (minutes)"
+// @Param duration_max query int false "Maximum duration (minutes)"
+// @Param director query int false "Director ID"
+// @Param actor query []int false "Actor ID" collectionFormat(multi)
+// @Param cinema query int false "Cinema ID"
+// @Param location query int false "Location ID"
+// @Param showing_on query string false "Showing on date (YYYY-MM-DD)"
+// @Param age_rating query []string false "Age rating (SU, 13+, 17+, 21+)" collectionFormat(multi)
+// @Param sort query string false "title | release_date | rating | popularity"
+// @Param order query string false "asc | desc"
+// @Param page query int false "Page (fallback jika tanpa cursor)"
+// @Param cursor query string false "Cursor next_cursor / prev_cursor"
+// @Param limit query int false "Page size"
+// @Success 200 {object} response.Envelope
+// @Router /movies/ [get]
 func (mh *movieHandler) GetAllMovie(ctx *gin.Context) {
 	// Ambil query parameter
-	page, err := strconv.Atoi(ctx.Query("page"))
+	var filter models.MovieFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		response.Error(ctx, apperror.BadRequest(apperror.CodeInvalidFilter, "").Wrap(err))
+		return
+	}
+	if filter.Sort != "" && !movieSorts[filter.Sort] {
+		response.Error(ctx, apperror.BadRequest(apperror.CodeInvalidSort, ""))
+		return
+	}
+	filter.Order = strings.ToLower(filter.Order)
+	if filter.Order != "" && filter.Order != "asc" && filter.Order != "desc" {
+		response.Error(ctx, apperror.BadRequest(apperror.CodeInvalidSortOrder, ""))
+		return
+	}
+	req, err := pagination.Parse(ctx, defaultMovieLimit, maxMovieLimit)
 	if err != nil {
-		page = 1
+		response.Error(ctx, apperror.BadRequest(apperror.CodeInvalidCursor, ""))
+		return
 	}
-	limit := 20
-	offset := (page - 1) * limit
 
-	// Ambil filter jika ada
-	title := ctx.Query("title") // kosong jika tidak ada
-	// ambil genre dari query, pisah dengan koma
-	genreParam := ctx.QueryArray("genre")
+	// genre unik & lowercase
 	genreMap := make(map[string]bool)
 	var genre []string
-	for _, g := range genreParam {
+	for _, g := range filter.Genres {
 		name := strings.ToLower(strings.TrimSpace(g))
-		if !genreMap[name] {
+		if name != "" && !genreMap[name] {
 			genreMap[name] = true
 			genre = append(genre, name)
 		}
 	}
-	// var genre []string
-	// if len(genreParam) >0 {
-	// 	// genre = strings.Split(genreParam, ",")
-	// 	for _, g := range (genreParam) {
-	// 		genre = append(genre, strings.TrimSpace(g))
-	// 	}
-	// }
-	// genre := genreParam
+	filter.Genres = genre
 
-	// Ambil data dari repository
-	movies, err := mh.mr.GetAllOrFilteredMovies(ctx.Request.Context(), title, genre, limit, offset)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"message": "Gagal mengambil data movie",
-			"error":   err.Error(),
-		})
-		return
+	// klasifikasi usia harus salah satu dari SU, 13+, 17+, 21+
+	for i, rating := range filter.AgeRatings {
+		normalized, ok := models.NormalizeAgeRating(rating)
+		if !ok {
+			response.Error(ctx, apperror.BadRequest(apperror.CodeInvalidFilter, "").
+				WithDetails(map[string]any{"age_rating": rating}))
+			return
+		}
+		filter.AgeRatings[i] = normalized
 	}
 
-	// Jika tidak ada data ditemukan
-	if len(movies) == 0 {
-		ctx.JSON(http.StatusOK, gin.H{
-			"success": true,
-			"data":    []string{},
-			"message": "Tidak ada data movie ditemukan",
-		})
+	// Ambil data dari repository
+	movies, meta, err := mh.mr.GetAllOrFilteredMovies(ctx.Request.Context(), filter, req)
+	if err != nil {
+		response.Error(ctx, err)
 		return
 	}
 
-	// Sukses
-	ctx.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    movies,
-	})
+	response.List(ctx, movies, meta)
 }
 
 func (mh *movieHandler) DeleteMovie(ctx *gin.Context) {
 	// Ambil param ID
-	movieIDStr := ctx.Param("movie_id")
-	movieID, err := strconv.Atoi(movieIDStr)
+	movieID, err := paramID(ctx, "movie_id")
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"message": "movie id tidak valid",
-		})
+		response.Error(ctx, err)
 		return
 	}
 
 	// Panggil method dari repository
-	err = mh.mr.DeleteMovie(ctx, movieID)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"message": err.Error(),
-		})
+	if err := mh.mr.DeleteMovie(ctx, movieID); err != nil {
+		response.Error(ctx, err)
 		return
 	}
 
 	// Sukses
-	ctx.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"message": fmt.Sprintf("movie dengan id %d berhasil dihapus", movieID),
-	})
+	response.OKMessage(ctx, "MOVIE_DELETED", gin.H{"id": movieID})
 }
 
 func (mh *movieHandler) EditMovie(ctx *gin.Context) {
 	// Ambil parameter movie ID dari URL
-	MovieIDStr := ctx.Param("id")
-	movieID, err := strconv.Atoi(MovieIDStr)
+	movieID, err := paramID(ctx, "id")
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"message": "Movie ID tidak valid",
-		})
+		response.Error(ctx, err)
 		return
 	}
 
 	// Bind form data ke struct MovieBody
 	var body models.MovieBody
 	if err := ctx.ShouldBind(&body); err != nil {
-		log.Println("Gagal bind data.\nSebab:", err.Error())
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Format data tidak valid",
-		})
+		response.Error(ctx, bindError(err))
 		return
 	}
 
@@ -246,83 +300,46 @@ func (mh *movieHandler) EditMovie(ctx *gin.Context) {
 	body.Id = movieID
 
 	// Ambil claims JWT (jika perlu otentikasi)
-	claims, isExist := ctx.Get("claims")
-	if !isExist {
-		ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{
-			"success": false,
-			"error":   "Silakan login kembali",
-		})
+	user, err := claimsFromContext(ctx)
+	if err != nil {
+		response.Abort(ctx, err)
+		return
+	}
+
+	// Proses gambar dulu, file baru ditulis ke storage sebelum transaksi di-commit
+	poster, err := utils.PrepareImage(body.Image, fmt.Sprintf("poster_%d", user.UserId), imaging.PosterPreset)
+	if err != nil {
+		response.Error(ctx, err)
 		return
 	}
-	user, ok := claims.(pkg.Claims)
-	if !ok {
-		ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   "Terjadi kesalahan internal",
-		})
+	backdrop, err := utils.PrepareImage(body.Backdrop, fmt.Sprintf("backdrop_%d", user.UserId), imaging.BackdropPreset)
+	if err != nil {
+		response.Error(ctx, err)
 		return
 	}
-	fmt.Println("User claims:", user)
-
-	// Upload gambar Poster (Image)
-	var imagePath *string
-	if body.Image != nil {
-		savePath, generatedFilename, err := utils.UploadImageFile(ctx, body.Image, "public", fmt.Sprintf("user_%d", user.UserId))
-		if err != nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{
-				"success": false,
-				"error":   err.Error(),
-			})
-			return
-		}
-		if err := ctx.SaveUploadedFile(body.Image, savePath); err != nil {
-			log.Println("Gagal menyimpan file poster.\nSebab:", err.Error())
-			ctx.JSON(http.StatusInternalServerError, gin.H{
-				"success": false,
-				"error":   "Gagal menyimpan file poster",
-			})
-			return
-		}
-		imagePath = &generatedFilename
+	storeImages := func(rctx context.Context) error {
+		return utils.StoreImages(rctx, mh.store, poster, backdrop)
 	}
 
-	// Upload gambar Backdrop
-	var backdropPath *string
-	if body.Backdrop != nil {
-		savePath, generatedFilename, err := utils.UploadImageFile(ctx, body.Backdrop, "public", fmt.Sprintf("user_%d", user.UserId))
-		if err != nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{
-				"success": false,
-				"error":   err.Error(),
-			})
-			return
-		}
-		if err := ctx.SaveUploadedFile(body.Backdrop, savePath); err != nil {
-			log.Println("Gagal menyimpan file backdrop.\nSebab:", err.Error())
-			ctx.JSON(http.StatusInternalServerError, gin.H{
-				"success": false,
-				"error":   "Gagal menyimpan file backdrop",
-			})
-			return
-		}
-		backdropPath = &generatedFilename
+	// Gambar dari presigned upload dipakai jika file tidak dikirim langsung
+	claimed := claimedUploads{ur: mh.ur}
+	posterKey, backdropKey, err := mh.claimMovieUploads(ctx, &claimed, body, user.UserId, poster, backdrop)
+	if err != nil {
+		response.Error(ctx, err)
+		return
 	}
 
 	// Panggil repository untuk update data lengkap dengan transaction
-	updatedMovie, err := mh.mr.EditMovie(ctx.Request.Context(), body, imagePath, backdropPath)
+	updatedMovie, err := mh.mr.EditMovie(ctx.Request.Context(), body, posterKey, backdropKey, storeImages)
 	if err != nil {
-		log.Println("Gagal update movie.\nSebab:", err.Error())
-		ctx.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   "Terjadi kesalahan saat menyimpan data",
-		})
+		utils.DiscardImages(mh.store, poster, backdrop)
+		claimed.release()
+		response.Error(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    updatedMovie,
-	})
+	// gambar lama dibersihkan image GC jika tidak dirujuk row lain
+	response.OK(ctx, updatedMovie)
 }
 
 func (mh *movieHandler) CreateMovie(ctx *gin.Context) {
@@ -330,153 +347,120 @@ func (mh *movieHandler) CreateMovie(ctx *gin.Context) {
 
 	// Ambil form data biasa
 	if err := ctx.ShouldBind(&body); err != nil {
-		log.Println("Gagal bind data:", err)
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Format data tidak valid",
-		})
-		return
-	}
-	 // Buat satu BodySchedules dari arrays
-    bs := models.BodySchedules{}
-
-    bs.Date = ctx.PostFormArray("date[]")
-    idCinemaStr := ctx.PostFormArray("id_cinema[]")
-    idTimeStr := ctx.PostFormArray("id_time[]")
-    idLocationStr := ctx.PostFormArray("id_location[]")
-
-    // Convert strings ke ints
-    for _, s := range idCinemaStr {
-        v, _ := strconv.Atoi(s)
-        bs.IdCinema = append(bs.IdCinema, v)
-    }
-    for _, s := range idTimeStr {
-        v, _ := strconv.Atoi(s)
-        bs.IdTime = append(bs.IdTime, v)
-    }
-    for _, s := range idLocationStr {
-        v, _ := strconv.Atoi(s)
-        bs.IdLocation = append(bs.IdLocation, v)
-    }
-
-    body.Schedules = []models.BodySchedules{bs}
+		response.Error(ctx, bindError(err))
+		return
+	}
+	// Buat satu BodySchedules dari arrays
+	bs := models.BodySchedules{}
 
-	// Ambil JWT claims
-	claims, exists := ctx.Get("claims")
-	if !exists {
-		ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{
-			"success": false,
-			"error":   "Silakan login kembali",
-		})
+	bs.Date = ctx.PostFormArray("date[]")
+	idStudioStr := ctx.PostFormArray("id_studio[]")
+	bs.StartTime = ctx.PostFormArray("start_time[]")
+
+	// Convert strings ke ints
+	for _, s := range idStudioStr {
+		v, _ := strconv.Atoi(s)
+		bs.IdStudio = append(bs.IdStudio, v)
+	}
+	if len(bs.IdStudio) != len(bs.Date) || len(bs.StartTime) != len(bs.Date) {
+		response.Error(ctx, apperror.Validation(apperror.CodeScheduleMismatch, ""))
 		return
 	}
-	user, ok := claims.(pkg.Claims)
-	if !ok {
-		ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   "Terjadi kesalahan internal",
-		})
+	if err := validation.Struct(bs); err != nil {
+		response.Error(ctx, bindError(err))
 		return
 	}
 
-	// Upload Poster (Image)
-	if body.Image != nil {
-		savePath, generatedFilename, err := utils.UploadImageFile(ctx, body.Image, "public", fmt.Sprintf("poster_path%d", user.UserId))
-		if err != nil {
-			log.Println("Upload poster gagal:", err)
-			ctx.JSON(http.StatusBadRequest, gin.H{
-				"success": false,
-				"error":   err.Error(),
-			})
-			return
-		}
-		if err := ctx.SaveUploadedFile(body.Image, savePath); err != nil {
-			log.Println("Gagal menyimpan file.\nSebab:", err.Error())
-			ctx.JSON(http.StatusInternalServerError, gin.H{
-				"success": false,
-				"error":   "Gagal menyimpan file gambar",
-			})
-			return
-		}
-		body.Imagestr = generatedFilename
+	body.Schedules = []models.BodySchedules{bs}
+
+	// Ambil JWT claims
+	user, err := claimsFromContext(ctx)
+	if err != nil {
+		response.Abort(ctx, err)
+		return
 	}
 
-	// Upload Backdrop
-	if body.Backdrop != nil {
-		savePath, generatedFilename, err := utils.UploadImageFile(ctx, body.Backdrop, "public", fmt.Sprintf("backdrop_path%d", user.UserId))
-		if err != nil {
-			log.Println("Upload backdrop gagal:", err)
-			ctx.JSON(http.StatusBadRequest, gin.H{
-				"success": false,
-				"error":   err.Error(),
-			})
-			return
-		}
-		if err := ctx.SaveUploadedFile(body.Backdrop, savePath); err != nil {
-			log.Println("Gagal menyimpan file.\nSebab:", err.Error())
-			ctx.JSON(http.StatusInternalServerError, gin.H{
-				"success": false,
-				"error":   "Gagal menyimpan file gambar",
-			})
-			return
-		}
-		body.Backdropstr = generatedFilename
+	// Proses gambar dulu, file baru ditulis ke storage sebelum transaksi di-commit
+	poster, err := utils.PrepareImage(body.Image, fmt.Sprintf("poster_%d", user.UserId), imaging.PosterPreset)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	backdrop, err := utils.PrepareImage(body.Backdrop, fmt.Sprintf("backdrop_%d", user.UserId), imaging.BackdropPreset)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	// Gambar dari presigned upload dipakai jika file tidak dikirim langsung
+	claimed := claimedUploads{ur: mh.ur}
+	posterKey, backdropKey, err := mh.claimMovieUploads(ctx, &claimed, body, user.UserId, poster, backdrop)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	if posterKey != nil {
+		body.Imagestr = *posterKey
+	}
+	if backdropKey != nil {
+		body.Backdropstr = *backdropKey
+	}
+	storeImages := func(rctx context.Context) error {
+		return utils.StoreImages(rctx, mh.store, poster, backdrop)
 	}
 
 	// Simpan ke database lewat repository
-	movie, err := mh.mr.CreateMovie(ctx.Request.Context(), body)
+	movie, err := mh.mr.CreateMovie(ctx.Request.Context(), body, storeImages)
 	if err != nil {
-		log.Println("Gagal simpan movie:", err)
-		ctx.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   "Terjadi kesalahan saat menyimpan data",
-		})
+		utils.DiscardImages(mh.store, poster, backdrop)
+		claimed.release()
+		response.Error(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    movie,
-	})
+	response.Created(ctx, movie)
 }
 
-func (mh *movieHandler) GetMovieAdmin(ctx *gin.Context) {
-	page, err := strconv.Atoi(ctx.Query("page"))
-	if err != nil {
-		page = 1
+// claimMovieUploads menentukan key poster & backdrop: file yang dikirim langsung
+// diutamakan, jika tidak ada dipakai upload ID dari presigned upload
+func (mh *movieHandler) claimMovieUploads(ctx *gin.Context, claimed *claimedUploads, body models.MovieBody,
+	userID int, poster, backdrop *utils.PendingImage) (*string, *string, error) {
+	posterKey, backdropKey := poster.KeyPtr(), backdrop.KeyPtr()
+	var err error
+	if posterKey == nil {
+		posterKey, err = claimed.claim(ctx.Request.Context(), body.PosterUploadID, userID, models.UploadPoster)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if backdropKey == nil {
+		backdropKey, err = claimed.claim(ctx.Request.Context(), body.BackdropUploadID, userID, models.UploadBackdrop)
+		if err != nil {
+			claimed.release()
+			return nil, nil, err
+		}
 	}
-	limit := 20
-	offset := (page - 1) * limit
+	return posterKey, backdropKey, nil
+}
 
-	movies, err := mh.mr.GetMovieAdmin(ctx.Request.Context(), limit, offset)
+func (mh *movieHandler) GetMovieAdmin(ctx *gin.Context) {
+	req, err := pagination.Parse(ctx, 20, 50)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{
-			"succes": false,
-			"data":   movies,
-		})
+		response.Error(ctx, apperror.BadRequest(apperror.CodeInvalidCursor, ""))
 		return
 	}
-	if len(movies) == 0 {
-		ctx.JSON(http.StatusOK, gin.H{
-			"success": true,
-			"data":    []string{},
-			"message": "Tidak ada data movie",
-		})
+
+	movies, meta, err := mh.mr.GetMovieAdmin(ctx.Request.Context(), req)
+	if err != nil {
+		response.Error(ctx, err)
 		return
 	}
-	ctx.JSON(http.StatusOK, gin.H{
-		"succes": true,
-		"data":   movies,
-	})
+	response.List(ctx, movies, meta)
 }
 
 func (h *movieHandler) GetMoviesByGenres(w *gin.Context) {
 	genreParam := w.Query("genres")
 	if genreParam == "" {
-		w.JSON(http.StatusBadRequest, gin.H{
-			"succes": false,
-			"error":  "No genre IDs provided",
-		})
+		response.Error(w, apperror.BadRequest(apperror.CodeGenreRequired, ""))
 		return
 	}
 
@@ -487,18 +471,10 @@ func (h *movieHandler) GetMoviesByGenres(w *gin.Context) {
 
 	movies, err := h.mr.GetMoviesByAllGenres(ctx, genreIDs)
 	if err != nil {
-		w.JSON(http.StatusInternalServerError, gin.H{
-			"succes":  false,
-			"message": "Failed to get movies",
-			"error":   err.Error(),
-		})
-		fmt.Println("Genre IDs:", genreIDs)
-		return
-	}
-	w.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    movies,
-	})
+		response.Error(w, err)
+		return
+	}
+	response.List(w, movies, nil)
 }
 
 func (h *movieHandler) GetAllGenres(c *gin.Context) {
@@ -506,9 +482,9 @@ func (h *movieHandler) GetAllGenres(c *gin.Context) {
 
 	genres, err := h.mr.GetAllGenres(ctx)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		response.Error(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, genres)
+	response.List(c, genres, nil)
 }
@@ -1,10 +1,9 @@
 package handlers
 
 import (
-	"net/http"
-
 	"github.com/federus1105/weekly/internals/models"
 	"github.com/federus1105/weekly/internals/repositories"
+	"github.com/federus1105/weekly/internals/response"
 	"github.com/gin-gonic/gin"
 )
 
@@ -22,7 +21,7 @@ func NewOrderHandler(or *repositories.OrderRepository) *OrderHandler {
 // @Accept json
 // @Produce json
 // @Param order body models.Order true "Order Request"
-// @Success 201 {object} models.Order
+// @Success 201 {object} response.Envelope
 // @Security BearerAuth
 // @Router /order [post]
 func (oh *OrderHandler) CreateOrder(ctx *gin.Context) {
@@ -30,33 +29,14 @@ func (oh *OrderHandler) CreateOrder(ctx *gin.Context) {
 
 	// Step 1: Bind request JSON
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   err.Error(),
-		})
+		response.Error(ctx, bindError(err))
 		return
 	}
 
 	// Step 2: Ambil user ID dari context (JWT middleware harus isi ini)
-	userIDInterface, exists := ctx.Get("user_id")
-	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{
-			"success": false,
-			"error":   "Unauthorized: user not logged in",
-		})
-		return
-	}
-	var userID int
-	switch v := userIDInterface.(type) {
-	case int:
-		userID = v
-	case float64:
-		userID = int(v)
-	default:
-		ctx.JSON(http.StatusUnauthorized, gin.H{
-			"success": false,
-			"error":   "Invalid user ID type in context",
-		})
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		response.Error(ctx, err)
 		return
 	}
 	// Step 3: Siapkan order model untuk dikirim ke repository
@@ -69,21 +49,75 @@ func (oh *OrderHandler) CreateOrder(ctx *gin.Context) {
 		Email:    req.Email,
 		Phone:    req.Phone,
 		Paid:     req.Paid,
+		Category: req.Category,
+		// kode promo divalidasi & dikunci di transaksi order
+		PromoCode: req.PromoCode,
 	}
 
 	// Step 4: Jalankan transaksi di repository (order + kursi)
 	newOrder, err := oh.or.CreateOrder(ctx.Request.Context(), order, req.Seats)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   err.Error(),
-		})
+		response.Error(ctx, err)
 		return
 	}
 	newOrder.Seats = req.Seats
 	// Step 5: Kirim response
-	ctx.JSON(http.StatusCreated, gin.H{
-		"success": true,
-		"data":    newOrder,
-	})
+	response.Created(ctx, newOrder)
+}
+
+// QuoteOrder godoc
+// @Summary Quote order price
+// @Description Rincian harga per kursi: harga dasar cinema, modifier & diskon dari aturan harga (tipe kursi, hari, jam tayang, hari libur, format studio, kategori penonton), biaya layanan dan pajak. Uang berupa string decimal. Kode promo opsional dipotong dari subtotal tiket & dibagi ke setiap kursi, payment dibutuhkan jika promo dibatasi metode pembayaran. Rincian sama dengan yang ditagih & disimpan saat order dibuat dengan isi yang sama selama aturan harga & kuota promo tidak berubah.
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Param body body models.QuoteBody true "Quote Request"
+// @Success 200 {object} response.Envelope
+// @Security BearerAuth
+// @Router /order/quote [post]
+func (oh *OrderHandler) QuoteOrder(ctx *gin.Context) {
+	var req models.QuoteBody
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.Error(ctx, bindError(err))
+		return
+	}
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	quote, err := oh.or.QuoteOrder(ctx.Request.Context(), userID, req)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.OK(ctx, quote)
+}
+
+// GetOrder godoc
+// @Summary Get order receipt
+// @Description Order milik user beserta rincian harga per kursi yang disimpan saat order dibuat
+// @Tags Orders
+// @Produce json
+// @Param id path int true "Order ID"
+// @Success 200 {object} response.Envelope
+// @Security BearerAuth
+// @Router /order/{id} [get]
+func (oh *OrderHandler) GetOrder(ctx *gin.Context) {
+	orderID, err := paramID(ctx, "id")
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	order, err := oh.or.GetOrder(ctx.Request.Context(), userID, orderID)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.OK(ctx, order)
 }
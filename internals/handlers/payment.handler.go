@@ -1,9 +1,8 @@
 package handlers
 
 import (
-	"net/http"
-
 	"github.com/federus1105/weekly/internals/repositories"
+	"github.com/federus1105/weekly/internals/response"
 	"github.com/gin-gonic/gin"
 )
 
@@ -20,9 +19,9 @@ func (p *PaymentHandler) GetPayment(c *gin.Context) {
 
 	payment, err := p.pr.GetPayment(ctx)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		response.Error(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, payment)
+	response.List(c, payment, nil)
 }
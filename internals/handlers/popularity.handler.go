@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"github.com/federus1105/weekly/internals/jobs"
+	"github.com/federus1105/weekly/internals/response"
+	"github.com/gin-gonic/gin"
+)
+
+type PopularityHandler struct {
+	job *jobs.PopularityRefresh
+}
+
+func NewPopularityHandler(job *jobs.PopularityRefresh) *PopularityHandler {
+	return &PopularityHandler{job: job}
+}
+
+// RefreshPopularity godoc
+// @Summary Refresh movie popularity
+// @Description Menghitung ulang skor popularitas dari penjualan tiket 7 hari terakhir dan menghapus cache movie populer
+// @Tags Admin
+// @Produce json
+// @Success 200 {object} response.Envelope
+// @Security BearerAuth
+// @Router /admin/popularity/refresh [post]
+func (ph *PopularityHandler) RefreshPopularity(ctx *gin.Context) {
+	result, err := ph.job.Run(ctx.Request.Context())
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.OK(ctx, result)
+}
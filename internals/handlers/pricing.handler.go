@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/federus1105/weekly/internals/models"
+	"github.com/federus1105/weekly/internals/repositories"
+	"github.com/federus1105/weekly/internals/response"
+	"github.com/federus1105/weekly/pkg/apperror"
+	"github.com/gin-gonic/gin"
+)
+
+type PricingHandler struct {
+	pr *repositories.PricingRepository
+}
+
+func NewPricingHandler(pr *repositories.PricingRepository) *PricingHandler {
+	return &PricingHandler{pr: pr}
+}
+
+// GetPriceRules godoc
+// @Summary List price rules
+// @Description Diurutkan sesuai urutan penerapan (priority lalu id)
+// @Tags Admin
+// @Produce json
+// @Success 200 {object} response.Envelope
+// @Security BearerAuth
+// @Router /admin/pricing/rules [get]
+func (ph *PricingHandler) GetPriceRules(ctx *gin.Context) {
+	rules, err := ph.pr.GetPriceRules(ctx.Request.Context())
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.List(ctx, rules, nil)
+}
+
+// CreatePriceRule godoc
+// @Summary Create price rule
+// @Description Harga tiket = cinema.price lalu setiap rule aktif yang cocok diterapkan berurutan dari priority terkecil. Kondisi null/kosong berlaku untuk semua.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param body body models.PriceRuleBody true "Price rule"
+// @Success 201 {object} response.Envelope
+// @Security BearerAuth
+// @Router /admin/pricing/rules [post]
+func (ph *PricingHandler) CreatePriceRule(ctx *gin.Context) {
+	var body models.PriceRuleBody
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		response.Error(ctx, bindError(err))
+		return
+	}
+	rule, err := ph.pr.CreatePriceRule(ctx.Request.Context(), body)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.Created(ctx, rule)
+}
+
+// UpdatePriceRule godoc
+// @Summary Replace price rule
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Price rule ID"
+// @Param body body models.PriceRuleBody true "Price rule"
+// @Success 200 {object} response.Envelope
+// @Security BearerAuth
+// @Router /admin/pricing/rules/{id} [put]
+func (ph *PricingHandler) UpdatePriceRule(ctx *gin.Context) {
+	ruleID, err := paramID(ctx, "id")
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	var body models.PriceRuleBody
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		response.Error(ctx, bindError(err))
+		return
+	}
+	rule, err := ph.pr.UpdatePriceRule(ctx.Request.Context(), ruleID, body)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.OK(ctx, rule)
+}
+
+// DeletePriceRule godoc
+// @Summary Delete price rule
+// @Tags Admin
+// @Produce json
+// @Param id path int true "Price rule ID"
+// @Success 200 {object} response.Envelope
+// @Security BearerAuth
+// @Router /admin/pricing/rules/{id} [delete]
+func (ph *PricingHandler) DeletePriceRule(ctx *gin.Context) {
+	ruleID, err := paramID(ctx, "id")
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	if err := ph.pr.DeletePriceRule(ctx.Request.Context(), ruleID); err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.OKMessage(ctx, "PRICE_RULE_DELETED", gin.H{"id": ruleID})
+}
+
+// GetHolidays godoc
+// @Summary List holidays
+// @Tags Admin
+// @Produce json
+// @Param year query int false "Year"
+// @Success 200 {object} response.Envelope
+// @Security BearerAuth
+// @Router /admin/pricing/holidays [get]
+func (ph *PricingHandler) GetHolidays(ctx *gin.Context) {
+	var filter models.HolidayFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		response.Error(ctx, apperror.BadRequest(apperror.CodeInvalidFilter, "").Wrap(err))
+		return
+	}
+	holidays, err := ph.pr.GetHolidays(ctx.Request.Context(), filter)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.List(ctx, holidays, nil)
+}
+
+// SaveHoliday godoc
+// @Summary Add holiday
+// @Description Tanggal tayang lokal cinema yang jatuh di hari libur memakai rule dengan holiday = true. Nama diganti jika tanggal sudah ada.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param body body models.HolidayBody true "Holiday"
+// @Success 200 {object} response.Envelope
+// @Security BearerAuth
+// @Router /admin/pricing/holidays [post]
+func (ph *PricingHandler) SaveHoliday(ctx *gin.Context) {
+	var body models.HolidayBody
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		response.Error(ctx, bindError(err))
+		return
+	}
+	holiday, err := ph.pr.SaveHoliday(ctx.Request.Context(), body)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.OK(ctx, holiday)
+}
+
+// DeleteHoliday godoc
+// @Summary Delete holiday
+// @Tags Admin
+// @Produce json
+// @Param date path string true "Tanggal (YYYY-MM-DD)"
+// @Success 200 {object} response.Envelope
+// @Security BearerAuth
+// @Router /admin/pricing/holidays/{date} [delete]
+func (ph *PricingHandler) DeleteHoliday(ctx *gin.Context) {
+	date := ctx.Param("date")
+	if _, err := time.Parse(time.DateOnly, date); err != nil {
+		response.Error(ctx, apperror.BadRequest(apperror.CodeBadRequest, "").Wrap(err))
+		return
+	}
+	if err := ph.pr.DeleteHoliday(ctx.Request.Context(), date); err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.OKMessage(ctx, "HOLIDAY_DELETED", gin.H{"date": date})
+}
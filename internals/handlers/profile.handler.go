@@ -1,112 +1,97 @@
 package handlers
 
 import (
+	"context"
 	"fmt"
-	"net/http"
+	"time"
 
 	"github.com/federus1105/weekly/internals/models"
 	"github.com/federus1105/weekly/internals/repositories"
+	"github.com/federus1105/weekly/internals/response"
 	"github.com/federus1105/weekly/internals/utils"
+	"github.com/federus1105/weekly/pkg/imaging"
+	"github.com/federus1105/weekly/pkg/storage"
+	"github.com/federus1105/weekly/pkg/validation"
 	"github.com/gin-gonic/gin"
 )
 
 type ProfileHandler struct {
-	pr *repositories.ProfileRepository
+	pr    *repositories.ProfileRepository
+	store storage.Storage
 }
 
-func NewProfileHandler(pr *repositories.ProfileRepository) *ProfileHandler {
-	return &ProfileHandler{pr: pr}
+func NewProfileHandler(pr *repositories.ProfileRepository, store storage.Storage) *ProfileHandler {
+	return &ProfileHandler{pr: pr, store: store}
 }
 
 // GetProfile godoc
 // @Summary Get Profile
 // @Tags Profile
 // @Produce json
-// @Param id path int true "ID Profile"
-// @Success 200 {object} map[string]interface{}
+// @Success 200 {object} response.Envelope
 // @Security BearerAuth
-// @Router /profile/{id} [get]
+// @Router /profile [get]
 func (ph *ProfileHandler) GetProfile(ctx *gin.Context) {
 	// Ambil user_id dari Gin Context
-	userIDRaw, exists := ctx.Get("user_id")
-	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{
-			"success": false,
-			"message": "Unauthorized: user ID tidak ditemukan di context",
-		})
-		return
-	}
-
-	profileID, ok := userIDRaw.(int)
-	if !ok {
-		ctx.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"message": "User dalam context tidak valid",
-		})
+	profileID, err := userIDFromContext(ctx)
+	if err != nil {
+		response.Error(ctx, err)
 		return
 	}
 	profiles, err := ph.pr.GetProfile(ctx.Request.Context(), profileID)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"message": "Gagal mengambil data Profile",
-		})
+		response.Error(ctx, err)
 		return
 	}
-	ctx.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    profiles,
-	})
-
+	response.List(ctx, profiles, nil)
 }
 
 func (s *ProfileHandler) EditProfile(ctx *gin.Context) {
-	userIDRaw, exists := ctx.Get("user_id")
-	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "Unauthorized"})
-		return
-	}
-
-	userID, ok := userIDRaw.(int)
-	if !ok {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "Invalid user ID"})
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		response.Error(ctx, err)
 		return
 	}
 
 	var body models.ProfileBody
 	if err := ctx.ShouldBind(&body); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "Format data tidak valid"})
+		response.Error(ctx, bindError(err))
 		return
 	}
 
-	var filename *string
-	if body.Image != nil {
-		savePath, generatedFilename, err := utils.UploadImageFile(ctx, body.Image, "public", fmt.Sprintf("user_%d", userID))
-		if err != nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{"success": false, "error": err.Error()})
-			return
-		}
-
-		if err := ctx.SaveUploadedFile(body.Image, savePath); err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "Gagal menyimpan file gambar"})
-			return
-		}
+	var birthDate *time.Time
+	if body.BirthDate != nil {
+		dob, _ := time.Parse(validation.DateLayout, *body.BirthDate)
+		birthDate = &dob
+	}
 
-		filename = &generatedFilename
+	// Proses avatar dulu, file baru ditulis ke storage sebelum update di-commit
+	avatar, err := utils.PrepareImage(body.Image, fmt.Sprintf("user_%d", userID), imaging.AvatarPreset)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	storeAvatar := func(rctx context.Context) error {
+		return utils.StoreImages(rctx, s.store, avatar)
 	}
 
 	// Panggil fungsi PATCH di repository
 	profile, err := s.pr.EditProfile(
 		ctx.Request.Context(),
-		filename,
+		avatar.KeyPtr(),
 		body.FirstName,
 		body.LastName,
 		body.Phone,
+		birthDate,
+		body.Location,
+		storeAvatar,
 	)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": err.Error()})
+		utils.DiscardImages(s.store, avatar)
+		response.Error(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"success": true, "data": profile})
+	// avatar lama dibersihkan image GC jika tidak dirujuk row lain
+	response.OK(ctx, profile)
 }
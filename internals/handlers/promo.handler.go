@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"github.com/federus1105/weekly/internals/models"
+	"github.com/federus1105/weekly/internals/repositories"
+	"github.com/federus1105/weekly/internals/response"
+	"github.com/federus1105/weekly/pkg/apperror"
+	"github.com/federus1105/weekly/pkg/pagination"
+	"github.com/gin-gonic/gin"
+)
+
+type PromoHandler struct {
+	pr *repositories.PromoRepository
+}
+
+func NewPromoHandler(pr *repositories.PromoRepository) *PromoHandler {
+	return &PromoHandler{pr: pr}
+}
+
+// GetPromos godoc
+// @Summary List promo codes
+// @Tags Admin
+// @Produce json
+// @Success 200 {object} response.Envelope
+// @Security BearerAuth
+// @Router /admin/promos [get]
+func (ph *PromoHandler) GetPromos(ctx *gin.Context) {
+	promos, err := ph.pr.GetPromos(ctx.Request.Context())
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.List(ctx, promos, nil)
+}
+
+// GetPromo godoc
+// @Summary Get promo code
+// @Tags Admin
+// @Produce json
+// @Param id path int true "Promo ID"
+// @Success 200 {object} response.Envelope
+// @Security BearerAuth
+// @Router /admin/promos/{id} [get]
+func (ph *PromoHandler) GetPromo(ctx *gin.Context) {
+	promoID, err := paramID(ctx, "id")
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	promo, err := ph.pr.GetPromo(ctx.Request.Context(), promoID)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.OK(ctx, promo)
+}
+
+// CreatePromo godoc
+// @Summary Create promo code
+// @Description Kode disimpan huruf besar. Potongan dihitung dari subtotal tiket setelah aturan harga lalu dibagi ke setiap kursi, pajak dihitung setelah potongan. Daftar movie/cinema/metode pembayaran kosong berlaku untuk semua.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param body body models.PromoBody true "Promo"
+// @Success 201 {object} response.Envelope
+// @Security BearerAuth
+// @Router /admin/promos [post]
+func (ph *PromoHandler) CreatePromo(ctx *gin.Context) {
+	var body models.PromoBody
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		response.Error(ctx, bindError(err))
+		return
+	}
+	promo, err := ph.pr.CreatePromo(ctx.Request.Context(), body)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.Created(ctx, promo)
+}
+
+// UpdatePromo godoc
+// @Summary Replace promo code
+// @Description Set active = false untuk menghentikan promo yang sudah pernah dipakai
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Promo ID"
+// @Param body body models.PromoBody true "Promo"
+// @Success 200 {object} response.Envelope
+// @Security BearerAuth
+// @Router /admin/promos/{id} [put]
+func (ph *PromoHandler) UpdatePromo(ctx *gin.Context) {
+	promoID, err := paramID(ctx, "id")
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	var body models.PromoBody
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		response.Error(ctx, bindError(err))
+		return
+	}
+	promo, err := ph.pr.UpdatePromo(ctx.Request.Context(), promoID, body)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.OK(ctx, promo)
+}
+
+// DeletePromo godoc
+// @Summary Delete promo code
+// @Description Hanya promo yang belum pernah dipakai
+// @Tags Admin
+// @Produce json
+// @Param id path int true "Promo ID"
+// @Success 200 {object} response.Envelope
+// @Security BearerAuth
+// @Router /admin/promos/{id} [delete]
+func (ph *PromoHandler) DeletePromo(ctx *gin.Context) {
+	promoID, err := paramID(ctx, "id")
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	if err := ph.pr.DeletePromo(ctx.Request.Context(), promoID); err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.OKMessage(ctx, "PROMO_DELETED", gin.H{"id": promoID})
+}
+
+// GetPromoRedemptions godoc
+// @Summary List promo redemptions
+// @Tags Admin
+// @Produce json
+// @Param id path int true "Promo ID"
+// @Param cursor query string false "Cursor"
+// @Param limit query int false "Page size"
+// @Success 200 {object} response.Envelope
+// @Security BearerAuth
+// @Router /admin/promos/{id}/redemptions [get]
+func (ph *PromoHandler) GetPromoRedemptions(ctx *gin.Context) {
+	promoID, err := paramID(ctx, "id")
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	req, err := pagination.Parse(ctx, 20, 50)
+	if err != nil {
+		response.Error(ctx, apperror.BadRequest(apperror.CodeInvalidCursor, ""))
+		return
+	}
+	redemptions, meta, err := ph.pr.GetPromoRedemptions(ctx.Request.Context(), promoID, req)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.List(ctx, redemptions, meta)
+}
+
+// GetPromoReport godoc
+// @Summary Promo redemption report
+// @Description Jumlah pemakaian, user unik, total potongan dan total order per kode promo
+// @Tags Admin
+// @Produce json
+// @Param from query string false "Tanggal awal (YYYY-MM-DD)"
+// @Param to query string false "Tanggal akhir (YYYY-MM-DD)"
+// @Success 200 {object} response.Envelope
+// @Security BearerAuth
+// @Router /admin/promos/report [get]
+func (ph *PromoHandler) GetPromoReport(ctx *gin.Context) {
+	var filter models.PromoReportFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		response.Error(ctx, apperror.BadRequest(apperror.CodeInvalidFilter, "").Wrap(err))
+		return
+	}
+	report, err := ph.pr.GetPromoReport(ctx.Request.Context(), filter)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.List(ctx, report, nil)
+}
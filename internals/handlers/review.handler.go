@@ -0,0 +1,265 @@
+package handlers
+
+import (
+	"github.com/federus1105/weekly/internals/models"
+	"github.com/federus1105/weekly/internals/repositories"
+	"github.com/federus1105/weekly/internals/response"
+	"github.com/federus1105/weekly/pkg/apperror"
+	"github.com/federus1105/weekly/pkg/pagination"
+	"github.com/gin-gonic/gin"
+)
+
+// batas page size review
+const (
+	defaultReviewLimit = 5
+	maxReviewLimit     = 20
+)
+
+type ReviewHandler struct {
+	rr *repositories.ReviewRepository
+}
+
+func NewReviewHandler(rr *repositories.ReviewRepository) *ReviewHandler {
+	return &ReviewHandler{rr: rr}
+}
+
+// GetReviews godoc
+// @Summary Get movie reviews
+// @Tags Reviews
+// @Produce json
+// @Param id path int true "Movie ID"
+// @Param cursor query string false "Cursor"
+// @Param limit query int false "Page size"
+// @Success 200 {object} response.Envelope
+// @Router /movies/{id}/reviews [get]
+func (rh *ReviewHandler) GetReviews(ctx *gin.Context) {
+	movieID, err := paramID(ctx, "id")
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	req, err := pagination.Parse(ctx, defaultReviewLimit, maxReviewLimit)
+	if err != nil {
+		response.Error(ctx, apperror.BadRequest(apperror.CodeInvalidCursor, ""))
+		return
+	}
+	page, err := rh.rr.GetReviews(ctx.Request.Context(), movieID, req)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.List(ctx, page.Items, page.Meta)
+}
+
+// CreateReview godoc
+// @Summary Review a movie
+// @Description Rating 1-10 dan review opsional. Hanya untuk user yang punya order lunas untuk movie tersebut.
+// @Tags Reviews
+// @Accept json
+// @Produce json
+// @Param id path int true "Movie ID"
+// @Param body body models.ReviewBody true "Review"
+// @Success 201 {object} response.Envelope
+// @Security BearerAuth
+// @Router /movies/{id}/reviews [post]
+func (rh *ReviewHandler) CreateReview(ctx *gin.Context) {
+	movieID, err := paramID(ctx, "id")
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	var body models.ReviewBody
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		response.Error(ctx, bindError(err))
+		return
+	}
+	user, err := claimsFromContext(ctx)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	review, err := rh.rr.CreateReview(ctx.Request.Context(), movieID, user.UserId, body)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.Created(ctx, review)
+}
+
+// UpdateReview godoc
+// @Summary Edit own review
+// @Tags Reviews
+// @Accept json
+// @Produce json
+// @Param id path int true "Movie ID"
+// @Param review_id path int true "Review ID"
+// @Param body body models.ReviewUpdateBody true "Review"
+// @Success 200 {object} response.Envelope
+// @Security BearerAuth
+// @Router /movies/{id}/reviews/{review_id} [patch]
+func (rh *ReviewHandler) UpdateReview(ctx *gin.Context) {
+	movieID, err := paramID(ctx, "id")
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	reviewID, err := paramID(ctx, "review_id")
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	var body models.ReviewUpdateBody
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		response.Error(ctx, bindError(err))
+		return
+	}
+	user, err := claimsFromContext(ctx)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	review, err := rh.rr.UpdateReview(ctx.Request.Context(), movieID, reviewID, user.UserId, body)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.OK(ctx, review)
+}
+
+// DeleteReview godoc
+// @Summary Delete review
+// @Description User hanya bisa menghapus review miliknya, admin bisa menghapus semua review
+// @Tags Reviews
+// @Produce json
+// @Param movie_id path int true "Movie ID"
+// @Param review_id path int true "Review ID"
+// @Success 200 {object} response.Envelope
+// @Security BearerAuth
+// @Router /movies/{movie_id}/reviews/{review_id} [delete]
+func (rh *ReviewHandler) DeleteReview(ctx *gin.Context) {
+	movieID, err := paramID(ctx, "movie_id")
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	reviewID, err := paramID(ctx, "review_id")
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	user, err := claimsFromContext(ctx)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	if err := rh.rr.DeleteReview(ctx.Request.Context(), movieID, reviewID, user.UserId, user.Role == "Admin"); err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.OKMessage(ctx, "REVIEW_DELETED", gin.H{"id": reviewID})
+}
+
+// ReportReview godoc
+// @Summary Report review
+// @Tags Reviews
+// @Accept json
+// @Produce json
+// @Param id path int true "Movie ID"
+// @Param review_id path int true "Review ID"
+// @Param body body models.ReviewReportBody true "Reason"
+// @Success 200 {object} response.Envelope
+// @Security BearerAuth
+// @Router /movies/{id}/reviews/{review_id}/report [post]
+func (rh *ReviewHandler) ReportReview(ctx *gin.Context) {
+	movieID, err := paramID(ctx, "id")
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	reviewID, err := paramID(ctx, "review_id")
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	var body models.ReviewReportBody
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		response.Error(ctx, bindError(err))
+		return
+	}
+	user, err := claimsFromContext(ctx)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	if err := rh.rr.ReportReview(ctx.Request.Context(), movieID, reviewID, user.UserId, body.Reason); err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.OKMessage(ctx, "REVIEW_REPORTED", gin.H{"id": reviewID})
+}
+
+// GetReviewsAdmin godoc
+// @Summary List reviews for moderation
+// @Tags Admin
+// @Produce json
+// @Param status query string false "visible | hidden"
+// @Param reported query bool false "Only reported reviews"
+// @Param id_movie query int false "Movie ID"
+// @Param cursor query string false "Cursor"
+// @Param limit query int false "Page size"
+// @Success 200 {object} response.Envelope
+// @Security BearerAuth
+// @Router /admin/reviews [get]
+func (rh *ReviewHandler) GetReviewsAdmin(ctx *gin.Context) {
+	var filter models.ReviewFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		response.Error(ctx, apperror.BadRequest(apperror.CodeInvalidFilter, "").Wrap(err))
+		return
+	}
+	req, err := pagination.Parse(ctx, 20, 50)
+	if err != nil {
+		response.Error(ctx, apperror.BadRequest(apperror.CodeInvalidCursor, ""))
+		return
+	}
+	reviews, meta, err := rh.rr.GetReviewsAdmin(ctx.Request.Context(), filter, req)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.List(ctx, reviews, meta)
+}
+
+// ModerateReview godoc
+// @Summary Hide or restore review
+// @Description Review hidden tidak tampil dan tidak dihitung di rata-rata rating. Laporan review dianggap selesai.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Review ID"
+// @Param body body models.ReviewModerationBody true "Status"
+// @Success 200 {object} response.Envelope
+// @Security BearerAuth
+// @Router /admin/reviews/{id} [patch]
+func (rh *ReviewHandler) ModerateReview(ctx *gin.Context) {
+	reviewID, err := paramID(ctx, "id")
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	var body models.ReviewModerationBody
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		response.Error(ctx, bindError(err))
+		return
+	}
+
+	review, err := rh.rr.ModerateReview(ctx.Request.Context(), reviewID, body.Status)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.OK(ctx, review)
+}
@@ -1,12 +1,10 @@
 package handlers
 
 import (
-	"fmt"
-	"net/http"
-	"strconv"
-
 	"github.com/federus1105/weekly/internals/models"
 	"github.com/federus1105/weekly/internals/repositories"
+	"github.com/federus1105/weekly/internals/response"
+	"github.com/federus1105/weekly/pkg/apperror"
 	"github.com/gin-gonic/gin"
 )
 
@@ -22,61 +20,246 @@ func NewScheduleHandler(sr *repositories.ScheduleRepository) *ScheduleHandler {
 // @Summary Get Schedule
 // @Tags Schedule
 // @Produce json
-// @Param id path int true "Movie Schedule"
-// @Success 200 {object} map[string]interface{}
+// @Description Hanya jadwal yang belum mulai tayang. date, from & to memakai tanggal & jam lokal cinema; from & to adalah rentang jam mulai (HH:MM), mis. from=19:00 untuk jadwal setelah jam 7 malam.
+// @Param id_movie path int true "Movie Schedule"
+// @Param date query string false "Tanggal tayang (YYYY-MM-DD)"
+// @Param from query string false "Jam mulai paling awal (HH:MM)"
+// @Param to query string false "Jam mulai paling akhir (HH:MM)"
+// @Param cinema query int false "Cinema ID"
+// @Param location query int false "Location ID"
+// @Success 200 {object} response.Envelope
 // @Security BearerAuth
-// @Router /schedule/{id} [get]
+// @Router /schedule/{id_movie} [get]
 func (sh *ScheduleHandler) GetSchedule(ctx *gin.Context) {
-	Idmovie := ctx.Param("id_movie")
-	schedule, err := strconv.Atoi(Idmovie)
+	movieID, err := paramID(ctx, "id_movie")
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"message": "Data tidak ada",
-		})
+		response.Error(ctx, err)
+		return
+	}
+	var filter models.ScheduleFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		response.Error(ctx, apperror.BadRequest(apperror.CodeInvalidFilter, "").Wrap(err))
 		return
 	}
-	fmt.Println("Result Schedules:", schedule)
-	schedules, err := sh.sr.GetSchedule(ctx.Request.Context(), schedule)
+	schedules, err := sh.sr.GetSchedule(ctx.Request.Context(), movieID, filter)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"message": "Gagal mengambil data schedule",
-			"erro": err.Error(),
-		})
+		response.Error(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    schedules,
-	})
+	response.List(ctx, schedules, nil)
+}
+
+// SearchShowtimes godoc
+// @Summary Search showtimes across cinemas
+// @Description Jadwal movie yang belum mulai dikelompokkan per cinema beserta sisa kursi & harga. Tanpa date berarti hari ini di zona waktu cinema; from & to adalah rentang jam mulai lokal (HH:MM). Dengan lat & lng, hanya cinema dalam radius (km, default 10) yang ditampilkan, diurutkan dari yang terdekat.
+// @Tags Schedule
+// @Produce json
+// @Param movie query int true "Movie ID"
+// @Param date query string false "Tanggal tayang (YYYY-MM-DD)"
+// @Param from query string false "Jam mulai paling awal (HH:MM)"
+// @Param to query string false "Jam mulai paling akhir (HH:MM)"
+// @Param location query int false "Location ID"
+// @Param lat query number false "Latitude"
+// @Param lng query number false "Longitude"
+// @Param radius query number false "Radius (km, maks 100)"
+// @Param format query []string false "Format studio (2D, 3D, IMAX, 4DX)" collectionFormat(multi)
+// @Success 200 {object} response.Envelope
+// @Router /schedule/search [get]
+func (sh *ScheduleHandler) SearchShowtimes(ctx *gin.Context) {
+	var filter models.ShowtimeSearchFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		response.Error(ctx, apperror.BadRequest(apperror.CodeInvalidFilter, "").Wrap(err))
+		return
+	}
+	cinemas, err := sh.sr.SearchShowtimes(ctx.Request.Context(), filter)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.List(ctx, cinemas, nil)
 }
+
+// CreateSchedule godoc
+// @Summary Create schedules
+// @Description Membuat jadwal untuk setiap kombinasi id_studio & start_times (HH:MM, jam lokal zona waktu cinema studio) di tanggal yang sama. end_at = start_at + durasi movie + 15 menit jeda. Jika ada jadwal yang bentrok, movie dihapus atau waktu sudah lewat, tidak ada jadwal yang dibuat dan semua masalah dikembalikan di error.details.conflicts (409).
+// @Tags Schedule
+// @Accept json
+// @Produce json
+// @Param body body models.BodyScheduleInput true "Schedule"
+// @Success 201 {object} response.Envelope
+// @Failure 409 {object} response.Envelope
+// @Security BearerAuth
+// @Router /schedule/create [post]
 func (sh *ScheduleHandler) CreateSchedule(ctx *gin.Context) {
-    var input models.BodyScheduleInput
-
-    if err := ctx.ShouldBindJSON(&input); err != nil {
-        ctx.JSON(http.StatusBadRequest, gin.H{
-            "success": false,
-            "message": "Invalid request body",
-            "error":   err.Error(),
-        })
-        return
-    }
-
-    newSchedules, err := sh.sr.CreateSchedule(ctx.Request.Context(), input)
-    if err != nil {
-        ctx.JSON(http.StatusInternalServerError, gin.H{
-            "success": false,
-            "message": "Internal server error",
-            "error":   err.Error(),
-        })
-        return
-    }
-
-    ctx.JSON(http.StatusOK, gin.H{
-        "success":  true,
-        "message":  "Create Schedule Successfully",
-        "schedule": newSchedules,
-    })
+	var input models.BodyScheduleInput
+
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		response.Error(ctx, bindError(err))
+		return
+	}
+
+	newSchedules, err := sh.sr.CreateSchedule(ctx.Request.Context(), input)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	response.Created(ctx, newSchedules)
+}
+
+// GetScheduleTemplates godoc
+// @Summary Get schedule templates
+// @Tags Schedule
+// @Produce json
+// @Param movie query int false "Movie ID"
+// @Success 200 {object} response.Envelope
+// @Security BearerAuth
+// @Router /schedule/templates [get]
+func (sh *ScheduleHandler) GetScheduleTemplates(ctx *gin.Context) {
+	var filter models.ScheduleTemplateFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		response.Error(ctx, apperror.BadRequest(apperror.CodeInvalidFilter, "").Wrap(err))
+		return
+	}
+	templates, err := sh.sr.GetScheduleTemplates(ctx.Request.Context(), filter)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.List(ctx, templates, nil)
+}
+
+// GetScheduleTemplate godoc
+// @Summary Get schedule template with upcoming schedules
+// @Tags Schedule
+// @Produce json
+// @Param id path int true "Template ID"
+// @Success 200 {object} response.Envelope
+// @Security BearerAuth
+// @Router /schedule/templates/{id} [get]
+func (sh *ScheduleHandler) GetScheduleTemplate(ctx *gin.Context) {
+	templateID, err := paramID(ctx, "id")
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	template, err := sh.sr.GetScheduleTemplate(ctx.Request.Context(), templateID)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.OK(ctx, template)
+}
+
+// PreviewScheduleTemplate godoc
+// @Summary Preview schedule template
+// @Description Menampilkan jadwal yang akan dibuat template beserta masalahnya (bentrok, waktu lewat, dll) tanpa menyimpan apa pun.
+// @Tags Schedule
+// @Accept json
+// @Produce json
+// @Param body body models.ScheduleTemplateBody true "Template"
+// @Success 200 {object} response.Envelope
+// @Security BearerAuth
+// @Router /schedule/templates/preview [post]
+func (sh *ScheduleHandler) PreviewScheduleTemplate(ctx *gin.Context) {
+	var body models.ScheduleTemplateBody
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		response.Error(ctx, bindError(err))
+		return
+	}
+	preview, err := sh.sr.PreviewScheduleTemplate(ctx.Request.Context(), body)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.OK(ctx, preview)
+}
+
+// CreateScheduleTemplate godoc
+// @Summary Create schedule template
+// @Description Template di-expand menjadi jadwal untuk setiap start_times di tanggal start_date..end_date yang harinya ada di weekdays (0 = minggu) dan bukan exceptions, maksimal 500 jadwal. Jika ada jadwal yang bermasalah tidak ada yang dibuat dan semua masalah dikembalikan di error.details.conflicts (409).
+// @Tags Schedule
+// @Accept json
+// @Produce json
+// @Param body body models.ScheduleTemplateBody true "Template"
+// @Success 201 {object} response.Envelope
+// @Failure 409 {object} response.Envelope
+// @Security BearerAuth
+// @Router /schedule/templates [post]
+func (sh *ScheduleHandler) CreateScheduleTemplate(ctx *gin.Context) {
+	var body models.ScheduleTemplateBody
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		response.Error(ctx, bindError(err))
+		return
+	}
+	template, err := sh.sr.CreateScheduleTemplate(ctx.Request.Context(), body)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.Created(ctx, template)
+}
+
+// CancelTemplateSchedules godoc
+// @Summary Cancel schedules generated by a template
+// @Description Membatalkan jadwal template yang belum tayang, bisa dibatasi dengan from/to. paid_orders adalah jumlah order lunas yang terkena.
+// @Tags Schedule
+// @Accept json
+// @Produce json
+// @Param id path int true "Template ID"
+// @Param body body models.ScheduleBulkBody false "Rentang tanggal"
+// @Success 200 {object} response.Envelope
+// @Security BearerAuth
+// @Router /schedule/templates/{id}/cancel [post]
+func (sh *ScheduleHandler) CancelTemplateSchedules(ctx *gin.Context) {
+	templateID, err := paramID(ctx, "id")
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	var body models.ScheduleBulkBody
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&body); err != nil {
+			response.Error(ctx, bindError(err))
+			return
+		}
+	}
+	result, err := sh.sr.CancelTemplateSchedules(ctx.Request.Context(), templateID, body)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.OKMessage(ctx, "SCHEDULES_CANCELLED", result)
+}
+
+// ShiftTemplateSchedules godoc
+// @Summary Shift schedules generated by a template
+// @Description Menggeser jadwal template yang belum tayang sebanyak minutes (negatif = lebih awal), bisa dibatasi dengan from/to. Jika ada hasil geseran yang bentrok atau sudah lewat tidak ada yang digeser (409).
+// @Tags Schedule
+// @Accept json
+// @Produce json
+// @Param id path int true "Template ID"
+// @Param body body models.ScheduleShiftBody true "Pergeseran"
+// @Success 200 {object} response.Envelope
+// @Failure 409 {object} response.Envelope
+// @Security BearerAuth
+// @Router /schedule/templates/{id}/shift [post]
+func (sh *ScheduleHandler) ShiftTemplateSchedules(ctx *gin.Context) {
+	templateID, err := paramID(ctx, "id")
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	var body models.ScheduleShiftBody
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		response.Error(ctx, bindError(err))
+		return
+	}
+	result, err := sh.sr.ShiftTemplateSchedules(ctx.Request.Context(), templateID, body)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.OKMessage(ctx, "SCHEDULES_SHIFTED", result)
 }
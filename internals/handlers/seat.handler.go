@@ -1,10 +1,8 @@
 package handlers
 
 import (
-	"net/http"
-	"strconv"
-
 	"github.com/federus1105/weekly/internals/repositories"
+	"github.com/federus1105/weekly/internals/response"
 	"github.com/gin-gonic/gin"
 )
 
@@ -21,34 +19,22 @@ func NewSeatHandler(sr *repositories.SeatRepository) *seatHandler {
 // @Tags Seat
 // @Produce json
 // @Param id path int true "ID Schedule"
-// @Success 200 {object} map[string]interface{}
+// @Success 200 {object} response.Envelope
 // @Security BearerAuth
 // @Router /seats/{id} [get]
 func (h *seatHandler) GetSeats(ctx *gin.Context) {
-	ID := ctx.Param("id")
-	schedule, err := strconv.Atoi(ID)
+	schedule, err := paramID(ctx, "id")
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"message": "Data tidak ada",
-		})
+		response.Error(ctx, err)
 		return
 	}
-	// fmt.Println("Result Kursi:", schedule)
-	schedules, err := h.sr.GetSeats(ctx.Request.Context(), schedule)
+	seats, err := h.sr.GetSeats(ctx.Request.Context(), schedule)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"message": "Gagal mengambil data Kursi",
-			"error":   err.Error(),
-		})
+		response.Error(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"data":    schedules,
-	})
+	response.List(ctx, seats, nil)
 }
 
 //  SELECT
@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"github.com/federus1105/weekly/internals/jobs"
+	"github.com/federus1105/weekly/internals/response"
+	"github.com/gin-gonic/gin"
+)
+
+type StorageHandler struct {
+	gc *jobs.ImageGC
+}
+
+func NewStorageHandler(gc *jobs.ImageGC) *StorageHandler {
+	return &StorageHandler{gc: gc}
+}
+
+// CollectGarbage godoc
+// @Summary Remove orphaned image files
+// @Description Menghapus file di storage yang tidak dirujuk database. Gunakan dry_run=true untuk melihat daftarnya saja.
+// @Tags Admin
+// @Produce json
+// @Param dry_run query bool false "Only list files"
+// @Success 200 {object} response.Envelope
+// @Security BearerAuth
+// @Router /admin/storage/gc [post]
+func (sh *StorageHandler) CollectGarbage(ctx *gin.Context) {
+	dryRun := ctx.Query("dry_run") == "true"
+	result, err := sh.gc.Run(ctx.Request.Context(), dryRun)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.OK(ctx, result)
+}
@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"time"
+
+	"github.com/federus1105/weekly/internals/models"
+	"github.com/federus1105/weekly/internals/repositories"
+	"github.com/federus1105/weekly/internals/response"
+	"github.com/federus1105/weekly/internals/utils"
+	"github.com/federus1105/weekly/pkg/apperror"
+	"github.com/federus1105/weekly/pkg/imaging"
+	"github.com/federus1105/weekly/pkg/storage"
+	"github.com/gin-gonic/gin"
+)
+
+// lama berlaku presigned upload URL
+const uploadURLTTL = 15 * time.Minute
+
+// preset gambar untuk tiap tujuan upload
+var uploadPresets = map[string]imaging.Preset{
+	models.UploadPoster:   imaging.PosterPreset,
+	models.UploadBackdrop: imaging.BackdropPreset,
+	models.UploadAvatar:   imaging.AvatarPreset,
+}
+
+type UploadHandler struct {
+	ur    *repositories.UploadRepository
+	mr    *repositories.MoviesRepository
+	pr    *repositories.ProfileRepository
+	store storage.Storage
+}
+
+func NewUploadHandler(ur *repositories.UploadRepository, mr *repositories.MoviesRepository,
+	pr *repositories.ProfileRepository, store storage.Storage) *UploadHandler {
+	return &UploadHandler{ur: ur, mr: mr, pr: pr, store: store}
+}
+
+// CreateUpload godoc
+// @Summary Create presigned upload
+// @Description Membuat upload ID dan URL untuk upload file langsung ke storage dengan method PUT
+// @Tags Uploads
+// @Accept json
+// @Produce json
+// @Param body body models.UploadBody true "Upload purpose"
+// @Success 201 {object} response.Envelope
+// @Security BearerAuth
+// @Router /uploads [post]
+func (uh *UploadHandler) CreateUpload(ctx *gin.Context) {
+	var body models.UploadBody
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		response.Error(ctx, bindError(err))
+		return
+	}
+	user, err := claimsFromContext(ctx)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	// poster & backdrop hanya untuk admin
+	if body.Purpose != models.UploadAvatar && user.Role != "Admin" {
+		response.Error(ctx, apperror.Forbidden(apperror.CodeForbidden, ""))
+		return
+	}
+
+	expiresAt := time.Now().Add(uploadURLTTL)
+	upload, err := uh.ur.CreateUpload(ctx.Request.Context(), user.UserId, body.Purpose, utils.UploadStagingPrefix, expiresAt)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	url, err := uh.store.PresignPut(ctx.Request.Context(), upload.ObjectKey, uploadURLTTL)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	response.Created(ctx, models.UploadTicket{
+		UploadID:  upload.Id,
+		Method:    "PUT",
+		URL:       url,
+		MaxSize:   utils.MaxDirectUploadSize,
+		ExpiresAt: upload.ExpiresAt,
+	})
+}
+
+// ConfirmUpload godoc
+// @Summary Confirm presigned upload
+// @Description Memvalidasi file yang sudah diupload lalu membuat variant gambar. Avatar langsung dipasang ke profile, poster/backdrop dipasang ke movie_id jika diisi; tanpa movie_id upload tetap confirmed dan bisa dipakai di create/edit movie.
+// @Tags Uploads
+// @Accept json
+// @Produce json
+// @Param id path string true "Upload ID"
+// @Param body body models.ConfirmUploadBody false "Target movie"
+// @Success 200 {object} response.Envelope
+// @Security BearerAuth
+// @Router /uploads/{id}/confirm [post]
+func (uh *UploadHandler) ConfirmUpload(ctx *gin.Context) {
+	var body models.ConfirmUploadBody
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&body); err != nil {
+			response.Error(ctx, bindError(err))
+			return
+		}
+	}
+	user, err := claimsFromContext(ctx)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	rctx := ctx.Request.Context()
+
+	upload, err := uh.ur.GetUpload(rctx, ctx.Param("id"), user.UserId)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	if upload.Status != models.UploadPending || time.Now().After(upload.ExpiresAt) {
+		response.Error(ctx, apperror.Conflict(apperror.CodeUploadNotPending, ""))
+		return
+	}
+	// validasi isi file: ukuran, tipe asli dan dimensi
+	data, err := utils.ReadUpload(rctx, uh.store, upload.ObjectKey)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	img, err := utils.ProcessImage(bytes.NewReader(data), upload.Purpose, uploadPresets[upload.Purpose])
+	if err != nil {
+		utils.DeleteObject(uh.store, upload.ObjectKey)
+		response.Error(ctx, err)
+		return
+	}
+	storeImage := func(rctx context.Context) error {
+		return utils.StoreImages(rctx, uh.store, img)
+	}
+	upload, err = uh.ur.ConfirmUpload(rctx, upload.Id, user.UserId, img.Key, storeImage)
+	if err != nil {
+		utils.DiscardImages(uh.store, img)
+		response.Error(ctx, err)
+		return
+	}
+	// file mentah sudah tidak diperlukan
+	utils.DeleteObject(uh.store, upload.ObjectKey)
+
+	// tanpa movie_id, poster/backdrop menunggu dipasang lewat create/edit movie
+	if upload.Purpose != models.UploadAvatar && body.MovieID == nil {
+		upload.Images = models.NewImageSet(img.Key)
+		response.OK(ctx, upload)
+		return
+	}
+
+	// pasang ke movie atau profile
+	claimed := claimedUploads{ur: uh.ur}
+	key, err := claimed.claim(rctx, upload.Id, user.UserId, upload.Purpose)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	switch upload.Purpose {
+	case models.UploadAvatar:
+		_, err = uh.pr.EditProfile(rctx, key, nil, nil, nil, nil, nil, nil)
+	case models.UploadPoster:
+		_, err = uh.mr.EditMovie(rctx, models.MovieBody{Id: *body.MovieID}, key, nil, nil)
+	case models.UploadBackdrop:
+		_, err = uh.mr.EditMovie(rctx, models.MovieBody{Id: *body.MovieID}, nil, key, nil)
+	}
+	if err != nil {
+		// upload tetap confirmed dan bisa dipasang lewat create/edit movie
+		claimed.release()
+		response.Error(ctx, err)
+		return
+	}
+	upload.Status = models.UploadAttached
+	upload.Images = models.NewImageSet(*key)
+	response.OK(ctx, upload)
+}
+
+// claimedUploads mencatat upload yang sudah di-claim supaya bisa
+// dikembalikan ke status confirmed jika penyimpanan gagal
+type claimedUploads struct {
+	ur  *repositories.UploadRepository
+	ids []string
+}
+
+// claim mengambil key gambar dari upload confirmed. id kosong berarti tidak ada upload.
+func (c *claimedUploads) claim(rctx context.Context, id string, userID int, purpose string) (*string, error) {
+	if id == "" {
+		return nil, nil
+	}
+	key, err := c.ur.ClaimUpload(rctx, id, userID, purpose)
+	if err != nil {
+		return nil, err
+	}
+	c.ids = append(c.ids, id)
+	return &key, nil
+}
+
+func (c *claimedUploads) release() {
+	for _, id := range c.ids {
+		if err := c.ur.ReleaseUpload(context.Background(), id); err != nil {
+			log.Println("Failed to release upload", id, "\nCause:", err)
+		}
+	}
+}
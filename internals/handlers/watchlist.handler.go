@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"github.com/federus1105/weekly/internals/models"
+	"github.com/federus1105/weekly/internals/repositories"
+	"github.com/federus1105/weekly/internals/response"
+	"github.com/federus1105/weekly/pkg/apperror"
+	"github.com/federus1105/weekly/pkg/pagination"
+	"github.com/gin-gonic/gin"
+)
+
+type WatchlistHandler struct {
+	wr *repositories.WatchlistRepository
+}
+
+func NewWatchlistHandler(wr *repositories.WatchlistRepository) *WatchlistHandler {
+	return &WatchlistHandler{wr: wr}
+}
+
+// GetWatchlist godoc
+// @Summary Get watchlist
+// @Description Movie yang disimpan user, terbaru lebih dulu
+// @Tags Profile
+// @Produce json
+// @Param cursor query string false "Cursor"
+// @Param page query int false "Page"
+// @Param limit query int false "Page size"
+// @Success 200 {object} response.Envelope
+// @Security BearerAuth
+// @Router /profile/watchlist [get]
+func (wh *WatchlistHandler) GetWatchlist(ctx *gin.Context) {
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	req, err := pagination.Parse(ctx, 10, 50)
+	if err != nil {
+		response.Error(ctx, apperror.BadRequest(apperror.CodeInvalidCursor, ""))
+		return
+	}
+
+	items, meta, err := wh.wr.GetWatchlist(ctx.Request.Context(), userID, req)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.List(ctx, items, meta)
+}
+
+// AddToWatchlist godoc
+// @Summary Add movie to watchlist
+// @Description notify = true untuk mendapat notifikasi saat movie punya jadwal pertama di lokasi favorit (id_location di profile). Jika movie sudah ada di watchlist, hanya notify yang diperbarui.
+// @Tags Profile
+// @Accept json
+// @Produce json
+// @Param body body models.WatchlistBody true "Movie"
+// @Success 201 {object} response.Envelope
+// @Security BearerAuth
+// @Router /profile/watchlist [post]
+func (wh *WatchlistHandler) AddToWatchlist(ctx *gin.Context) {
+	var body models.WatchlistBody
+	if err := ctx.ShouldBindJSON(&body); err != nil {
+		response.Error(ctx, bindError(err))
+		return
+	}
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	item, err := wh.wr.AddToWatchlist(ctx.Request.Context(), userID, body)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.Created(ctx, item)
+}
+
+// RemoveFromWatchlist godoc
+// @Summary Remove movie from watchlist
+// @Tags Profile
+// @Produce json
+// @Param movie_id path int true "Movie ID"
+// @Success 200 {object} response.Envelope
+// @Security BearerAuth
+// @Router /profile/watchlist/{movie_id} [delete]
+func (wh *WatchlistHandler) RemoveFromWatchlist(ctx *gin.Context) {
+	movieID, err := paramID(ctx, "movie_id")
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	if err := wh.wr.RemoveFromWatchlist(ctx.Request.Context(), userID, movieID); err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.OKMessage(ctx, "WATCHLIST_REMOVED", gin.H{"id_movie": movieID})
+}
+
+// GetNotifications godoc
+// @Summary Get notifications
+// @Tags Profile
+// @Produce json
+// @Param unread query bool false "Only unread notifications"
+// @Param cursor query string false "Cursor"
+// @Param page query int false "Page"
+// @Param limit query int false "Page size"
+// @Success 200 {object} response.Envelope
+// @Security BearerAuth
+// @Router /profile/notifications [get]
+func (wh *WatchlistHandler) GetNotifications(ctx *gin.Context) {
+	var filter models.NotificationFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		response.Error(ctx, apperror.BadRequest(apperror.CodeInvalidFilter, "").Wrap(err))
+		return
+	}
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	req, err := pagination.Parse(ctx, 20, 50)
+	if err != nil {
+		response.Error(ctx, apperror.BadRequest(apperror.CodeInvalidCursor, ""))
+		return
+	}
+
+	notifications, meta, err := wh.wr.GetNotifications(ctx.Request.Context(), userID, filter, req)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.List(ctx, notifications, meta)
+}
+
+// ReadNotification godoc
+// @Summary Mark notification as read
+// @Tags Profile
+// @Produce json
+// @Param id path int true "Notification ID"
+// @Success 200 {object} response.Envelope
+// @Security BearerAuth
+// @Router /profile/notifications/{id}/read [patch]
+func (wh *WatchlistHandler) ReadNotification(ctx *gin.Context) {
+	notificationID, err := paramID(ctx, "id")
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	notification, err := wh.wr.MarkNotificationRead(ctx.Request.Context(), userID, notificationID)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.OK(ctx, notification)
+}
@@ -0,0 +1,75 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/federus1105/weekly/internals/repositories"
+	"github.com/federus1105/weekly/internals/utils"
+	"github.com/federus1105/weekly/pkg/storage"
+)
+
+// DefaultGCGrace melindungi file yang baru saja ditulis tetapi transaksinya
+// belum di-commit dari ikut terhapus
+const DefaultGCGrace = time.Hour
+
+// ImageGC menghapus file di storage yang tidak dirujuk movie, account,
+// cinema maupun payment method
+type ImageGC struct {
+	ir    *repositories.ImageRepository
+	store storage.Storage
+	grace time.Duration
+}
+
+type GCResult struct {
+	Scanned    int      `json:"scanned"`
+	Referenced int      `json:"referenced"`
+	Deleted    []string `json:"deleted"`
+	DryRun     bool     `json:"dry_run"`
+}
+
+func NewImageGC(ir *repositories.ImageRepository, store storage.Storage, grace time.Duration) *ImageGC {
+	return &ImageGC{ir: ir, store: store, grace: grace}
+}
+
+// Run mencari file yatim dan menghapusnya. Jika dryRun, file hanya dilaporkan.
+func (gc *ImageGC) Run(ctx context.Context, dryRun bool) (GCResult, error) {
+	result := GCResult{Deleted: []string{}, DryRun: dryRun}
+
+	// daftar file diambil lebih dulu supaya file yang ditulis setelah query
+	// referensi tidak dianggap yatim
+	objects, err := gc.store.List(ctx, "")
+	if err != nil {
+		return result, err
+	}
+	keys, err := gc.ir.GetImageKeys(ctx)
+	if err != nil {
+		return result, err
+	}
+
+	referenced := make(map[string]bool, len(keys)*5)
+	for _, key := range keys {
+		for _, k := range utils.ImageKeys(key) {
+			referenced[k] = true
+		}
+	}
+
+	cutoff := time.Now().Add(-gc.grace)
+	result.Scanned = len(objects)
+	for _, obj := range objects {
+		if referenced[obj.Key] {
+			result.Referenced++
+			continue
+		}
+		if obj.ModTime.After(cutoff) {
+			continue
+		}
+		if !dryRun {
+			if err := gc.store.Delete(ctx, obj.Key); err != nil {
+				return result, err
+			}
+		}
+		result.Deleted = append(result.Deleted, obj.Key)
+	}
+	return result, nil
+}
@@ -0,0 +1,27 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Every menjalankan fn setiap interval sampai ctx dibatalkan.
+// Error hanya dicatat supaya job tetap jalan di putaran berikutnya.
+func Every(ctx context.Context, name string, interval time.Duration, fn func(ctx context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			start := time.Now()
+			if err := fn(ctx); err != nil {
+				log.Printf("[job %s] failed: %v", name, err)
+				continue
+			}
+			log.Printf("[job %s] done in %s", name, time.Since(start))
+		}
+	}
+}
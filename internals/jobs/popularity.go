@@ -0,0 +1,40 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/federus1105/weekly/internals/repositories"
+)
+
+// DefaultPopularityInterval adalah jeda refresh movie_popularity jika
+// POPULARITY_REFRESH_INTERVAL tidak di-set
+const DefaultPopularityInterval = 15 * time.Minute
+
+// PopularityRefresh menghitung ulang skor popularitas movie lalu menghapus
+// cache movie populer supaya endpoint langsung memakai skor terbaru
+type PopularityRefresh struct {
+	pr *repositories.PopularityRepository
+}
+
+type PopularityResult struct {
+	RefreshedAt     time.Time `json:"refreshed_at"`
+	InvalidatedKeys int       `json:"invalidated_keys"`
+}
+
+func NewPopularityRefresh(pr *repositories.PopularityRepository) *PopularityRefresh {
+	return &PopularityRefresh{pr: pr}
+}
+
+func (p *PopularityRefresh) Run(ctx context.Context) (PopularityResult, error) {
+	if err := p.pr.Refresh(ctx); err != nil {
+		return PopularityResult{}, err
+	}
+	result := PopularityResult{RefreshedAt: time.Now()}
+	deleted, err := p.pr.InvalidateCache(ctx)
+	if err != nil {
+		return result, err
+	}
+	result.InvalidatedKeys = deleted
+	return result, nil
+}
@@ -1,10 +1,12 @@
 package middlewares
 
 import (
-	"net/http"
+	"errors"
 	"slices"
 
+	"github.com/federus1105/weekly/internals/response"
 	"github.com/federus1105/weekly/pkg"
+	"github.com/federus1105/weekly/pkg/apperror"
 	"github.com/gin-gonic/gin"
 )
 
@@ -13,26 +15,17 @@ func Access(roles ...string) func(*gin.Context) {
 		// ambil data claim
 		claims, isExist := ctx.Get("claims")
 		if !isExist {
-			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{
-				"success": false,
-				"error":   "Silahkan login kembali",
-			})
+			response.Abort(ctx, apperror.Unauthorized(apperror.CodeTokenMissing, ""))
 			return
 		}
 		user, ok := claims.(pkg.Claims)
 		if !ok {
 			// log.Println("Cannot cast claims into pkg.claims")
-			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-				"success": false,
-				"error":   "Internal server error",
-			})
+			response.Abort(ctx, errors.New("cannot cast claims into pkg.Claims"))
 			return
 		}
 		if !slices.Contains(roles, user.Role) {
-			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{
-				"success": false,
-				"error":   "Anda tidak punya hak akses untuk resource ini",
-			})
+			response.Abort(ctx, apperror.Forbidden(apperror.CodeForbidden, ""))
 			return
 		}
 		ctx.Next()
@@ -2,10 +2,11 @@ package middlewares
 
 import (
 	"context"
-	"net/http"
 	"strings"
 
+	"github.com/federus1105/weekly/internals/response"
 	"github.com/federus1105/weekly/pkg"
+	"github.com/federus1105/weekly/pkg/apperror"
 	"github.com/gin-gonic/gin"
 )
 
@@ -78,10 +79,7 @@ func AuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"success": false,
-				"error":   "Authorization header missing or invalid",
-			})
+			response.Abort(c, apperror.Unauthorized(apperror.CodeTokenMissing, ""))
 			return
 		}
 
@@ -93,10 +91,7 @@ func AuthMiddleware() gin.HandlerFunc {
 
 		// Verifikasi token
 		if err := claims.VerifyToken(tokenString); err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"success": false,
-				"error":   "Invalid token: " + err.Error(),
-			})
+			response.Abort(c, tokenError(err))
 			return
 		}
 
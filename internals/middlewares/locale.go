@@ -0,0 +1,14 @@
+package middlewares
+
+import (
+	"github.com/federus1105/weekly/pkg/i18n"
+	"github.com/gin-gonic/gin"
+)
+
+// Locale memilih bahasa response dari header Accept-Language
+func Locale(ctx *gin.Context) {
+	loc := i18n.Parse(ctx.GetHeader("Accept-Language"))
+	ctx.Set(i18n.ContextKey, loc)
+	ctx.Header("Content-Language", string(loc))
+	ctx.Next()
+}
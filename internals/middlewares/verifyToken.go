@@ -1,12 +1,13 @@
 package middlewares
 
 import (
-	"fmt"
+	"errors"
 	"log"
-	"net/http"
 	"strings"
 
+	"github.com/federus1105/weekly/internals/response"
 	"github.com/federus1105/weekly/pkg"
+	"github.com/federus1105/weekly/pkg/apperror"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 )
@@ -15,41 +16,29 @@ func VerifyToken(ctx *gin.Context) {
 	// ambil token dari header
 	bearerToken := ctx.GetHeader("Authorization")
 	// Bearer token
-	token := strings.Split(bearerToken, " ")[1]
-	if token == "" {
-		ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-			"success": false,
-			"error":   "Silahkan login terlebih dahulu",
-		})
+	token, found := strings.CutPrefix(bearerToken, "Bearer ")
+	if !found || token == "" {
+		response.Abort(ctx, apperror.Unauthorized(apperror.CodeTokenMissing, ""))
 		return
 	}
 
 	var claims pkg.Claims
 	if err := claims.VerifyToken(token); err != nil {
-		if strings.Contains(err.Error(), jwt.ErrTokenInvalidIssuer.Error()) {
-			log.Println("JWT Error.\nCause: ", err.Error())
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"success": false,
-				"error":   "Silahkan login kembali",
-			})
-			return
-		}
-		if strings.Contains(err.Error(), jwt.ErrTokenExpired.Error()) {
-			log.Println("JWT Error.\nCause: ", err.Error())
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"success": false,
-				"error":   "Silahkan login kembali",
-			})
-			return
-		}
-		fmt.Println(jwt.ErrTokenExpired)
-		log.Println("Internal Server Error.\nCause: ", err.Error())
-		ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   "Internal Server Error",
-		})
+		log.Println("JWT Error.\nCause: ", err.Error())
+		response.Abort(ctx, tokenError(err))
 		return
 	}
 	ctx.Set("claims", claims)
 	ctx.Next()
 }
+
+// tokenError membedakan token kedaluwarsa dengan token yang tidak valid
+func tokenError(err error) error {
+	if errors.Is(err, jwt.ErrTokenExpired) {
+		return apperror.Unauthorized(apperror.CodeTokenExpired, "")
+	}
+	if strings.Contains(err.Error(), "no secret found") {
+		return err
+	}
+	return apperror.Unauthorized(apperror.CodeTokenInvalid, "")
+}
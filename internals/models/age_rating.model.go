@@ -0,0 +1,42 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// klasifikasi usia film dari LSF
+const (
+	AgeRatingSU = "SU"
+	AgeRating13 = "13+"
+	AgeRating17 = "17+"
+	AgeRating21 = "21+"
+)
+
+// AgeRatings berisi usia minimal penonton untuk tiap klasifikasi
+var AgeRatings = map[string]int{
+	AgeRatingSU: 0,
+	AgeRating13: 13,
+	AgeRating17: 17,
+	AgeRating21: 21,
+}
+
+// NormalizeAgeRating merapikan klasifikasi dari query string. Tanda "+" yang
+// tidak di-encode terbaca sebagai spasi, jadi "13", "13 " dan "13+" dianggap sama.
+func NormalizeAgeRating(rating string) (string, bool) {
+	rating = strings.ToUpper(strings.TrimSpace(rating))
+	if rating != AgeRatingSU && !strings.HasSuffix(rating, "+") {
+		rating += "+"
+	}
+	_, ok := AgeRatings[rating]
+	return rating, ok
+}
+
+// AgeOn menghitung usia (tahun penuh) pada tanggal day
+func AgeOn(dob, day time.Time) int {
+	age := day.Year() - dob.Year()
+	if day.Month() < dob.Month() || (day.Month() == dob.Month() && day.Day() < dob.Day()) {
+		age--
+	}
+	return age
+}
@@ -0,0 +1,88 @@
+package models
+
+type Location struct {
+	Id   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type LocationBody struct {
+	Name string `json:"name" binding:"required,max=255"`
+}
+
+type Cinema struct {
+	Id         int       `json:"id"`
+	Name       string    `json:"name"`
+	Image      string    `json:"image,omitempty"`
+	Price      float64   `json:"price"`
+	LocationID *int      `json:"id_location"`
+	Location   *string   `json:"location"`
+	Address    string    `json:"address"`
+	Latitude   *float64  `json:"latitude"`
+	Longitude  *float64  `json:"longitude"`
+	Facilities []string  `json:"facilities"`
+	TimeZone   string    `json:"time_zone"`
+	Images     *ImageSet `json:"images,omitempty"`
+	Studios    []Studio  `json:"studios,omitempty"`
+}
+
+// SetImages mengisi URL variant logo cinema dari key di database
+func (c *Cinema) SetImages() {
+	c.Images = NewImageSet(c.Image)
+}
+
+// CinemaBody dipakai untuk membuat cinema baru, latitude & longitude harus diisi berpasangan
+type CinemaBody struct {
+	Name       string   `json:"name" binding:"required,max=255"`
+	LocationID int      `json:"id_location" binding:"required,gt=0"`
+	Price      float64  `json:"price" binding:"gte=0"`
+	Address    string   `json:"address" binding:"max=255"`
+	Latitude   *float64 `json:"latitude" binding:"required_with=Longitude,omitempty,latitude"`
+	Longitude  *float64 `json:"longitude" binding:"required_with=Latitude,omitempty,longitude"`
+	Facilities []string `json:"facilities" binding:"omitempty,max=20,dive,required,max=50"`
+	// zona waktu IANA, default Asia/Jakarta
+	TimeZone string `json:"time_zone" binding:"omitempty,timezone"`
+}
+
+type CinemaUpdateBody struct {
+	Name       *string  `json:"name" binding:"omitempty,max=255"`
+	LocationID *int     `json:"id_location" binding:"omitempty,gt=0"`
+	Price      *float64 `json:"price" binding:"omitempty,gte=0"`
+	Address    *string  `json:"address" binding:"omitempty,max=255"`
+	Latitude   *float64 `json:"latitude" binding:"required_with=Longitude,omitempty,latitude"`
+	Longitude  *float64 `json:"longitude" binding:"required_with=Latitude,omitempty,longitude"`
+	Facilities []string `json:"facilities" binding:"omitempty,max=20,dive,required,max=50"`
+	TimeZone   *string  `json:"time_zone" binding:"omitempty,timezone"`
+}
+
+type CinemaFilter struct {
+	LocationID *int `form:"location" binding:"omitempty,gt=0"`
+}
+
+// format layar studio
+const (
+	Format2D   = "2D"
+	Format3D   = "3D"
+	FormatIMAX = "IMAX"
+	Format4DX  = "4DX"
+)
+
+type Studio struct {
+	Id       int    `json:"id"`
+	CinemaID int    `json:"id_cinema"`
+	Name     string `json:"name"`
+	Capacity *int   `json:"capacity"`
+	Format   string `json:"format"`
+}
+
+// StudioBody, format default 2D
+type StudioBody struct {
+	Name     string `json:"name" binding:"required,max=100"`
+	Capacity *int   `json:"capacity" binding:"omitempty,gt=0"`
+	Format   string `json:"format" binding:"omitempty,oneof=2D 3D IMAX 4DX"`
+}
+
+type StudioUpdateBody struct {
+	Name     *string `json:"name" binding:"omitempty,max=100"`
+	Capacity *int    `json:"capacity" binding:"omitempty,gt=0"`
+	Format   *string `json:"format" binding:"omitempty,oneof=2D 3D IMAX 4DX"`
+}
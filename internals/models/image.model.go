@@ -0,0 +1,33 @@
+package models
+
+import "github.com/federus1105/weekly/pkg/imaging"
+
+// ImageBaseURL adalah prefix route yang menyajikan file dari storage
+const ImageBaseURL = "/img/"
+
+// ImageSet berisi URL semua variant dari satu gambar upload
+type ImageSet struct {
+	Original  string `json:"original"`
+	Thumbnail string `json:"thumbnail"`
+	Medium    string `json:"medium"`
+	Large     string `json:"large"`
+	WebP      string `json:"webp"`
+}
+
+// NewImageSet membentuk URL variant dari key yang tersimpan di database.
+// Mengembalikan nil jika key kosong.
+func NewImageSet(key string) *ImageSet {
+	if key == "" {
+		return nil
+	}
+	url := func(name string) string {
+		return ImageBaseURL + imaging.VariantKey(key, name)
+	}
+	return &ImageSet{
+		Original:  url(imaging.Original),
+		Thumbnail: url(imaging.Thumbnail),
+		Medium:    url(imaging.Medium),
+		Large:     url(imaging.Large),
+		WebP:      url(imaging.WebP),
+	}
+}
@@ -0,0 +1,95 @@
+package models
+
+import (
+	"mime/multipart"
+	"net/url"
+	"strings"
+)
+
+// provider video trailer
+const (
+	ProviderYouTube = "youtube"
+	ProviderVimeo   = "vimeo"
+	ProviderOther   = "other"
+)
+
+// MovieMedia adalah galeri media satu movie
+type MovieMedia struct {
+	Trailers []Trailer `json:"trailers"`
+	Stills   []Still   `json:"stills"`
+}
+
+type Trailer struct {
+	Id       int    `json:"id"`
+	MovieID  int    `json:"id_movie"`
+	Title    string `json:"title"`
+	URL      string `json:"url"`
+	Provider string `json:"provider"`
+	Duration int    `json:"duration"`
+	Position int    `json:"position"`
+}
+
+// Still adalah gambar adegan tambahan di galeri movie
+type Still struct {
+	Id       int       `json:"id"`
+	MovieID  int       `json:"id_movie"`
+	Image    string    `json:"image"`
+	Caption  string    `json:"caption"`
+	Position int       `json:"position"`
+	Images   *ImageSet `json:"images,omitempty"`
+}
+
+func (s *Still) SetImages() {
+	s.Images = NewImageSet(s.Image)
+}
+
+// TrailerBody dipakai untuk menambah trailer. Provider kosong berarti
+// ditentukan dari host URL, position kosong berarti ditaruh paling akhir.
+type TrailerBody struct {
+	Title    string `json:"title" binding:"max=255"`
+	URL      string `json:"url" binding:"required,url,max=500"`
+	Provider string `json:"provider" binding:"omitempty,oneof=youtube vimeo other"`
+	Duration int    `json:"duration" binding:"gte=0"`
+	Position *int   `json:"position" binding:"omitempty,gte=0"`
+}
+
+// TrailerUpdateBody dipakai untuk edit sebagian field trailer
+type TrailerUpdateBody struct {
+	Title    *string `json:"title" binding:"omitempty,max=255"`
+	URL      *string `json:"url" binding:"omitempty,url,max=500"`
+	Provider *string `json:"provider" binding:"omitempty,oneof=youtube vimeo other"`
+	Duration *int    `json:"duration" binding:"omitempty,gte=0"`
+	Position *int    `json:"position" binding:"omitempty,gte=0"`
+}
+
+type StillBody struct {
+	Image    *multipart.FileHeader `form:"image"`
+	Caption  string                `form:"caption" binding:"max=255"`
+	Position *int                  `form:"position" binding:"omitempty,gte=0"`
+}
+
+type StillUpdateBody struct {
+	Caption  *string `json:"caption" binding:"omitempty,max=255"`
+	Position *int    `json:"position" binding:"omitempty,gte=0"`
+}
+
+// StillOrderBody berisi semua id still movie dengan urutan yang baru
+type StillOrderBody struct {
+	IDs []int `json:"ids" binding:"required,min=1,unique,dive,gt=0"`
+}
+
+// DetectProvider menentukan provider video dari host URL
+func DetectProvider(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ProviderOther
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	switch {
+	case host == "youtu.be" || host == "youtube.com" || strings.HasSuffix(host, ".youtube.com"):
+		return ProviderYouTube
+	case host == "vimeo.com" || strings.HasSuffix(host, ".vimeo.com"):
+		return ProviderVimeo
+	}
+	return ProviderOther
+}
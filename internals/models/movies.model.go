@@ -6,17 +6,67 @@ import (
 )
 
 type Movie struct {
-	Id          int       `db:"id" json:"id"`
-	Title       string    `db:"title" json:"title"`
-	Image       string    `db:"image" json:"poster_path,omitempty"`
-	ReleaseDate time.Time `db:"release_date" json:"release_date,omitzero"`
-	Genres      []string  `db:"genres" json:"genres"`
-	Backdrop    string    `db:"backdrop" json:"backdrop_path,omitempty"`
-	Duration    string    `db:"duration" json:"duration,omitempty"`
-	Synopsis    string    `db:"synopsis" json:"synopsis,omitempty"`
-	Director    string    `db:"director" json:"director,omitempty"`
-	Rating      float64   `db:"rating" json:"rating,omitempty"`
-	Actor       []string  `db:"actor" json:"actor,omitempty"`
+	Id          int         `db:"id" json:"id"`
+	Title       string      `db:"title" json:"title"`
+	Image       string      `db:"image" json:"poster_path,omitempty"`
+	ReleaseDate time.Time   `db:"release_date" json:"release_date,omitzero"`
+	Genres      []string    `db:"genres" json:"genres"`
+	Backdrop    string      `db:"backdrop" json:"backdrop_path,omitempty"`
+	Duration    string      `db:"duration" json:"duration,omitempty"`
+	Synopsis    string      `db:"synopsis" json:"synopsis,omitempty"`
+	Director    string      `db:"director" json:"director,omitempty"`
+	Rating      float64     `db:"rating" json:"rating,omitempty"`
+	Actor       []string    `db:"actor" json:"actor,omitempty"`
+	AgeRating   string      `db:"age_rating" json:"age_rating,omitempty"`
+	Posters     *ImageSet   `json:"poster_images,omitempty"`
+	Backdrops   *ImageSet   `json:"backdrop_images,omitempty"`
+	Media       *MovieMedia `json:"media,omitempty"`
+	// rata-rata rating dari review user
+	ReviewAverage float64     `db:"review_average" json:"review_average,omitempty"`
+	ReviewCount   int         `db:"review_count" json:"review_count,omitempty"`
+	Reviews       *ReviewPage `json:"reviews,omitempty"`
+	// jumlah user yang menyimpan movie di watchlist
+	WatchlistCount int `json:"watchlist_count,omitempty"`
+	// skor popularitas dari penjualan tiket 7 hari terakhir
+	PopularityScore float64 `json:"popularity_score,omitempty"`
+	SeatsSold       int     `json:"seats_sold,omitempty"`
+	// skor rekomendasi personal 0..1
+	RecommendationScore float64 `json:"recommendation_score,omitempty"`
+	// jadwal terdekat untuk daftar now showing
+	Showtimes []Showtime `json:"showtimes,omitempty"`
+}
+
+// Showtime adalah ringkasan satu jadwal, jam tayang dengan offset zona waktu cinema
+type Showtime struct {
+	ScheduleID int       `json:"id_schedule"`
+	CinemaID   int       `json:"id_cinema"`
+	Cinema     string    `json:"cinema"`
+	StudioID   int       `json:"id_studio"`
+	Studio     string    `json:"studio"`
+	StartAt    time.Time `json:"start_at"`
+	EndAt      time.Time `json:"end_at"`
+	TimeZone   string    `json:"time_zone"`
+}
+
+// NowShowingShowtimes adalah jumlah jadwal terdekat per movie di daftar now showing
+const NowShowingShowtimes = 5
+
+// NowShowingFilter, date adalah tanggal tayang lokal cinema
+type NowShowingFilter struct {
+	LocationID *int   `form:"location" binding:"omitempty,gt=0"`
+	CinemaID   *int   `form:"cinema" binding:"omitempty,gt=0"`
+	Date       string `form:"date" binding:"omitempty,date"`
+}
+
+// IsDefault true jika tidak ada filter
+func (f NowShowingFilter) IsDefault() bool {
+	return f.LocationID == nil && f.CinemaID == nil && f.Date == ""
+}
+
+// SetImages mengisi URL variant poster & backdrop dari key di database
+func (m *Movie) SetImages() {
+	m.Posters = NewImageSet(m.Image)
+	m.Backdrops = NewImageSet(m.Backdrop)
 }
 
 type MovieAdmin struct {
@@ -26,14 +76,19 @@ type MovieAdmin struct {
 	ReleaseDate time.Time `db:"release_date" json:"release_date,omitzero"`
 	Genres      string    `db:"genres" json:"genres"`
 	Duration    string    `db:"duration" json:"duration,omitempty"`
+	AgeRating   string    `db:"age_rating" json:"age_rating"`
+	Posters     *ImageSet `json:"poster_images,omitempty"`
+}
+
+func (m *MovieAdmin) SetImages() {
+	m.Posters = NewImageSet(m.Image)
 }
 
 type BodySchedules struct {
-	Idmovie    int      `form:"id_movie"`
-	Date       []string `form:"date"`
-	IdCinema   []int    `form:"id_cinema"`
-	IdTime     []int    `form:"id_time"`
-	IdLocation []int    `form:"id_location"`
+	Idmovie   int      `form:"id_movie"`
+	Date      []string `form:"date" binding:"dive,date"`
+	IdStudio  []int    `form:"id_studio" binding:"dive,schedule_id"`
+	StartTime []string `form:"start_time" binding:"dive,clock"`
 }
 type MovieBody struct {
 	Id          int                   `form:"id"`
@@ -42,14 +97,49 @@ type MovieBody struct {
 	Duration    string                `form:"duration"`
 	Synopsis    string                `form:"synopsis"`
 	Director    int                   `form:"id_director,omitempty"`
-	ActorIDs    []int                 `form:"actor_ids"`
-	GenreIDs    []int                 `form:"genre_ids"`
+	ActorIDs    []int                 `form:"actor_ids" binding:"omitempty,dive,gt=0"`
+	GenreIDs    []int                 `form:"genre_ids" binding:"omitempty,dive,gt=0"`
 	Schedules   []BodySchedules       `form:"schedule"`
-	Rating      float64               `form:"rating"`
+	Rating      float64               `form:"rating" binding:"omitempty,gte=0,lte=10"`
+	AgeRating   string                `form:"age_rating" json:"age_rating" binding:"omitempty,oneof=SU 13+ 17+ 21+"`
 	Image       *multipart.FileHeader `form:"poster_path"`
 	Backdrop    *multipart.FileHeader `form:"backdrop_path"`
 	Imagestr    string                `json:"image"`
 	Backdropstr string                `json:"backdrop"`
+	// upload ID dari presigned upload, dipakai jika file tidak dikirim langsung
+	PosterUploadID   string `form:"poster_upload_id" json:"-"`
+	BackdropUploadID string `form:"backdrop_upload_id" json:"-"`
+}
+
+// MovieFilter menampung query filter & sorting untuk katalog movie
+type MovieFilter struct {
+	Title       string    `form:"title"`
+	Genres      []string  `form:"genre"`
+	ReleaseFrom time.Time `form:"release_from" time_format:"2006-01-02"`
+	ReleaseTo   time.Time `form:"release_to" time_format:"2006-01-02"`
+	RatingMin   *float64  `form:"rating_min"`
+	RatingMax   *float64  `form:"rating_max"`
+	DurationMin *int      `form:"duration_min"`
+	DurationMax *int      `form:"duration_max"`
+	Director    *int      `form:"director"`
+	Actors      []int     `form:"actor"`
+	Cinema      *int      `form:"cinema"`
+	Location    *int      `form:"location"`
+	ShowingOn   time.Time `form:"showing_on" time_format:"2006-01-02"`
+	AgeRatings  []string  `form:"age_rating"`
+	Sort        string    `form:"sort"`
+	Order       string    `form:"order"`
+}
+
+// IsDefault true jika tidak ada filter dan memakai sorting bawaan
+func (f MovieFilter) IsDefault() bool {
+	return f.Title == "" && len(f.Genres) == 0 &&
+		f.ReleaseFrom.IsZero() && f.ReleaseTo.IsZero() &&
+		f.RatingMin == nil && f.RatingMax == nil &&
+		f.DurationMin == nil && f.DurationMax == nil &&
+		f.Director == nil && len(f.Actors) == 0 &&
+		f.Cinema == nil && f.Location == nil && f.ShowingOn.IsZero() && len(f.AgeRatings) == 0 &&
+		(f.Sort == "" || f.Sort == "title") && (f.Order == "" || f.Order == "asc")
 }
 
 type Genre struct {
@@ -1,25 +1,27 @@
 package models
 
+import "github.com/shopspring/decimal"
+
 type Order struct {
-	Id       int     `json:"id,omitempty"`
-	Schedule int     `json:"schedule" binding:"required,numeric"`
-	User     int     `json:"user,omitempty"`
-	Payment  int     `json:"payment" binding:"required"`
-	Total    float32 `json:"total,omitempty"`
-	Fullname string  `json:"fullname" binding:"required"`
-	Email    string  `json:"email" binding:"required,email"`
-	Phone    string  `json:"phone" binding:"required,min=12,numeric"`
-	Paid     bool    `json:"paid" binding:"required"`
-	Seats    []int   `json:"seats" binding:"required"`
+	Id       int             `json:"id,omitempty"`
+	Schedule int             `json:"schedule" binding:"required,schedule_id"`
+	User     int             `json:"user,omitempty"`
+	Payment  int             `json:"payment" binding:"required,gt=0"`
+	Total    decimal.Decimal `json:"total,omitzero"`
+	Fullname string          `json:"fullname" binding:"required"`
+	Email    string          `json:"email" binding:"required,email"`
+	Phone    string          `json:"phone" binding:"required,phone"`
+	Paid     bool            `json:"paid" binding:"required"`
+	Seats    []int           `json:"seats" binding:"required,seats"`
+	// kategori penonton untuk aturan harga, default general
+	Category string `json:"category,omitempty" binding:"omitempty,oneof=general student child senior"`
+	// kode promo opsional, divalidasi ulang saat order dibuat
+	PromoCode string `json:"promo_code,omitempty" binding:"omitempty,max=32"`
+	// rincian harga yang disimpan bersama order, sama dengan quote
+	Subtotal decimal.Decimal `json:"subtotal,omitzero"`
+	Fees     decimal.Decimal `json:"fees,omitzero"`
+	Tax      decimal.Decimal `json:"tax,omitzero"`
+	TaxRate  decimal.Decimal `json:"tax_rate,omitzero"`
+	Items    []LineItem      `json:"items,omitempty"`
+	Promo    *AppliedPromo   `json:"promo,omitempty"`
 }
-
-// type Order struct {
-// 	Schedule int     `json:"id_schedule"`
-// 	Payment  int     `json:"id_payment_method"`
-// 	Total    float64 `json:"total"`
-// 	Fullname string  `json:"fullname"`
-// 	Email    string  `json:"email"`
-// 	Phone    string  `json:"phone_number"`
-// 	Paid     bool    `json:"paid"`
-// 	Seats    []int   `json:"seats"`
-// }
@@ -0,0 +1,140 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// tipe kursi
+const (
+	SeatRegular  = "regular"
+	SeatPremium  = "premium"
+	SeatSweetbox = "sweetbox"
+)
+
+// kategori penonton, anak & lansia diverifikasi dari tanggal lahir
+const (
+	CategoryGeneral = "general"
+	CategoryStudent = "student"
+	CategoryChild   = "child"
+	CategorySenior  = "senior"
+)
+
+// batas usia kategori pada tanggal tayang: anak di bawah ChildMaxAge, lansia mulai SeniorMinAge
+const (
+	ChildMaxAge  = 12
+	SeniorMinAge = 60
+)
+
+// PriceRule adalah aturan harga tiket, kondisi null berlaku untuk semua
+type PriceRule struct {
+	Id         int             `json:"id"`
+	Name       string          `json:"name"`
+	CinemaID   *int            `json:"id_cinema"`
+	SeatType   *string         `json:"seat_type"`
+	Weekdays   []int           `json:"weekdays"`
+	StartTime  *string         `json:"start_time"`
+	EndTime    *string         `json:"end_time"`
+	Holiday    *bool           `json:"holiday"`
+	Format     *string         `json:"format"`
+	Category   *string         `json:"customer_category"`
+	Adjustment string          `json:"adjustment"`
+	Amount     decimal.Decimal `json:"amount"`
+	Priority   int             `json:"priority"`
+	Active     bool            `json:"active"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// PriceRuleBody dipakai untuk membuat & mengganti aturan harga. weekdays 0 = Minggu,
+// start_time & end_time adalah jam mulai tayang lokal (end eksklusif, boleh melewati
+// tengah malam). adjustment set mengganti harga, add menambah (negatif = potongan),
+// percent menambah persen (negatif = diskon). Rule dengan priority kecil diterapkan dulu.
+type PriceRuleBody struct {
+	Name       string          `json:"name" binding:"required,max=100"`
+	CinemaID   *int            `json:"id_cinema" binding:"omitempty,gt=0"`
+	SeatType   *string         `json:"seat_type" binding:"omitempty,oneof=regular premium sweetbox"`
+	Weekdays   []int           `json:"weekdays" binding:"omitempty,max=7,unique,dive,gte=0,lte=6"`
+	StartTime  *string         `json:"start_time" binding:"required_with=EndTime,omitempty,clock"`
+	EndTime    *string         `json:"end_time" binding:"required_with=StartTime,omitempty,clock"`
+	Holiday    *bool           `json:"holiday"`
+	Format     *string         `json:"format" binding:"omitempty,oneof=2D 3D IMAX 4DX"`
+	Category   *string         `json:"customer_category" binding:"omitempty,oneof=general student child senior"`
+	Adjustment string          `json:"adjustment" binding:"required,oneof=set add percent"`
+	Amount     decimal.Decimal `json:"amount"`
+	Priority   int             `json:"priority"`
+	// default true
+	Active *bool `json:"active"`
+}
+
+type Holiday struct {
+	Date string `json:"date"`
+	Name string `json:"name"`
+}
+
+type HolidayBody struct {
+	Date string `json:"date" binding:"required,date"`
+	Name string `json:"name" binding:"required,max=100"`
+}
+
+// HolidayFilter, tanpa year berarti semua hari libur
+type HolidayFilter struct {
+	Year *int `form:"year" binding:"omitempty,gte=2000,lte=2100"`
+}
+
+// QuoteBody meminta harga tiket sebelum order, isinya sama dengan order. payment
+// dibutuhkan jika kode promo hanya berlaku untuk metode pembayaran tertentu.
+type QuoteBody struct {
+	Schedule  int    `json:"schedule" binding:"required,schedule_id"`
+	Seats     []int  `json:"seats" binding:"required,seats"`
+	Category  string `json:"category" binding:"omitempty,oneof=general student child senior"`
+	Payment   int    `json:"payment" binding:"omitempty,gt=0"`
+	PromoCode string `json:"promo_code" binding:"omitempty,max=32"`
+}
+
+// Quote adalah harga yang akan ditagih saat order dibuat dengan isi yang sama.
+// Semua nilai uang adalah string decimal supaya tidak ada pembulatan float.
+type Quote struct {
+	ScheduleID int             `json:"id_schedule"`
+	Category   string          `json:"category"`
+	Items      []LineItem      `json:"items"`
+	Subtotal   decimal.Decimal `json:"subtotal"`
+	Fees       decimal.Decimal `json:"fees"`
+	Tax        decimal.Decimal `json:"tax"`
+	TaxRate    decimal.Decimal `json:"tax_rate"`
+	Total      decimal.Decimal `json:"total"`
+	Promo      *AppliedPromo   `json:"promo,omitempty"`
+}
+
+// LineItem adalah rincian harga satu kursi: harga dasar cinema, modifier (aturan harga
+// yang menaikkan/mengganti harga), diskon (aturan harga yang menurunkan harga & bagian
+// potongan promo), biaya & pajak.
+// subtotal = base_price + modifiers + discounts, total = subtotal + fees + tax.
+type LineItem struct {
+	SeatID    int               `json:"id_seat"`
+	Seat      string            `json:"seat"`
+	SeatType  string            `json:"seat_type"`
+	BasePrice decimal.Decimal   `json:"base_price"`
+	Modifiers []PriceAdjustment `json:"modifiers"`
+	Discounts []PriceAdjustment `json:"discounts"`
+	Subtotal  decimal.Decimal   `json:"subtotal"`
+	Fees      []Fee             `json:"fees"`
+	Tax       decimal.Decimal   `json:"tax"`
+	Total     decimal.Decimal   `json:"total"`
+}
+
+// PriceAdjustment adalah perubahan harga dari satu aturan harga atau kode promo, negatif untuk diskon
+type PriceAdjustment struct {
+	RuleID  int             `json:"id_rule,omitempty"`
+	PromoID int             `json:"id_promo,omitempty"`
+	Name    string          `json:"name"`
+	Amount  decimal.Decimal `json:"amount"`
+}
+
+// kode biaya per tiket
+const FeeBooking = "booking_fee"
+
+type Fee struct {
+	Code   string          `json:"code"`
+	Amount decimal.Decimal `json:"amount"`
+}
@@ -1,21 +1,38 @@
 package models
 
-import "mime/multipart"
+import (
+	"mime/multipart"
+	"time"
+)
 
 type Profile struct {
-	UserID    int    `db:"user_id" json:"id"`
-	Email     string `db:"email" json:"email,omitempty"`
-	Password  string `db:"password" json:"password,omitempty"`
-	Image     *string `db:"image" json:"image,omitempty"`
-	FirstName string `db:"firstname" json:"first_name"`
-	LastName  string `db:"lastname" json:"last_name"`
-	Phone     string `db:"phonenumber" json:"phone"`
-	Point     string `db:"point" json:"point"`
+	UserID    int        `db:"user_id" json:"id"`
+	Email     string     `db:"email" json:"email,omitempty"`
+	Password  string     `db:"password" json:"password,omitempty"`
+	Image     *string    `db:"image" json:"image,omitempty"`
+	FirstName string     `db:"firstname" json:"first_name"`
+	LastName  string     `db:"lastname" json:"last_name"`
+	Phone     string     `db:"phonenumber" json:"phone"`
+	Point     string     `db:"point" json:"point"`
+	BirthDate *time.Time `db:"date_of_birth" json:"date_of_birth,omitempty"`
+	// lokasi favorit untuk notifikasi jadwal movie di watchlist
+	LocationID *int      `db:"id_location" json:"id_location,omitempty"`
+	Location   *string   `json:"location,omitempty"`
+	Images     *ImageSet `json:"images,omitempty"`
+}
+
+// SetImages mengisi URL variant avatar dari key di database
+func (p *Profile) SetImages() {
+	if p.Image != nil {
+		p.Images = NewImageSet(*p.Image)
+	}
 }
 
 type ProfileBody struct {
-	FirstName *string               `form:"first_name"`
-	LastName  *string               `form:"last_name"`
-	Phone     *string               `form:"phone"`
+	FirstName *string               `form:"first_name" binding:"omitempty,max=50"`
+	LastName  *string               `form:"last_name" binding:"omitempty,max=50"`
+	Phone     *string               `form:"phone" binding:"omitempty,phone"`
+	BirthDate *string               `form:"date_of_birth" binding:"omitempty,birthdate"`
+	Location  *int                  `form:"id_location" binding:"omitempty,gt=0"`
 	Image     *multipart.FileHeader `form:"image"`
 }
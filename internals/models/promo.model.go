@@ -0,0 +1,88 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Promo adalah kode promo yang dikelola admin. id_movies, id_cinemas & id_payment_methods
+// kosong berarti berlaku untuk semua, limit null berarti tanpa batas.
+type Promo struct {
+	Id               int              `json:"id"`
+	Code             string           `json:"code"`
+	Description      string           `json:"description"`
+	DiscountType     string           `json:"discount_type"`
+	Amount           decimal.Decimal  `json:"amount"`
+	MinSpend         decimal.Decimal  `json:"min_spend"`
+	MaxDiscount      *decimal.Decimal `json:"max_discount"`
+	ValidFrom        time.Time        `json:"valid_from"`
+	ValidUntil       *time.Time       `json:"valid_until"`
+	UsageLimit       *int             `json:"usage_limit"`
+	PerUserLimit     *int             `json:"per_user_limit"`
+	MovieIDs         []int            `json:"id_movies"`
+	CinemaIDs        []int            `json:"id_cinemas"`
+	PaymentMethodIDs []int            `json:"id_payment_methods"`
+	Active           bool             `json:"active"`
+	Redemptions      int              `json:"redemptions"`
+	CreatedAt        time.Time        `json:"created_at"`
+}
+
+// PromoBody dipakai untuk membuat & mengganti kode promo. discount_type percent memotong
+// amount persen dari subtotal tiket (dibatasi max_discount), fixed memotong amount.
+// min_spend dibandingkan dengan subtotal tiket sebelum biaya layanan & pajak.
+type PromoBody struct {
+	Code             string           `json:"code" binding:"required,min=3,max=32,alphanum"`
+	Description      string           `json:"description" binding:"max=255"`
+	DiscountType     string           `json:"discount_type" binding:"required,oneof=percent fixed"`
+	Amount           decimal.Decimal  `json:"amount"`
+	MinSpend         decimal.Decimal  `json:"min_spend"`
+	MaxDiscount      *decimal.Decimal `json:"max_discount"`
+	ValidFrom        time.Time        `json:"valid_from" binding:"required"`
+	ValidUntil       *time.Time       `json:"valid_until"`
+	UsageLimit       *int             `json:"usage_limit" binding:"omitempty,gt=0"`
+	PerUserLimit     *int             `json:"per_user_limit" binding:"omitempty,gt=0"`
+	MovieIDs         []int            `json:"id_movies" binding:"omitempty,unique,dive,gt=0"`
+	CinemaIDs        []int            `json:"id_cinemas" binding:"omitempty,unique,dive,gt=0"`
+	PaymentMethodIDs []int            `json:"id_payment_methods" binding:"omitempty,unique,dive,gt=0"`
+	// default true
+	Active *bool `json:"active"`
+}
+
+// AppliedPromo adalah promo yang dipakai pada quote/order beserta total potongannya
+type AppliedPromo struct {
+	Id       int             `json:"id"`
+	Code     string          `json:"code"`
+	Discount decimal.Decimal `json:"discount"`
+}
+
+// PromoRedemption adalah satu pemakaian kode promo pada order
+type PromoRedemption struct {
+	Id         int             `json:"id"`
+	OrderID    int             `json:"id_order"`
+	UserID     int             `json:"id_user"`
+	Fullname   string          `json:"fullname"`
+	Email      string          `json:"email"`
+	Discount   decimal.Decimal `json:"discount"`
+	OrderTotal decimal.Decimal `json:"order_total"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// PromoReportFilter membatasi laporan ke tanggal pemakaian promo, kosong berarti semua
+type PromoReportFilter struct {
+	From string `form:"from" binding:"omitempty,date"`
+	To   string `form:"to" binding:"omitempty,date"`
+}
+
+// PromoReport adalah ringkasan pemakaian satu kode promo
+type PromoReport struct {
+	PromoID        int             `json:"id_promo"`
+	Code           string          `json:"code"`
+	Active         bool            `json:"active"`
+	UsageLimit     *int            `json:"usage_limit"`
+	Redemptions    int             `json:"redemptions"`
+	Users          int             `json:"users"`
+	Discount       decimal.Decimal `json:"discount"`
+	Revenue        decimal.Decimal `json:"revenue"`
+	LastRedeemedAt *time.Time      `json:"last_redeemed_at"`
+}
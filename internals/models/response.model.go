@@ -1,6 +0,0 @@
-package models
-
-type Response struct {
-	Message string
-	Status  string
-}
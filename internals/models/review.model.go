@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+
+	"github.com/federus1105/weekly/pkg/pagination"
+)
+
+// status review
+const (
+	ReviewVisible = "visible"
+	ReviewHidden  = "hidden"
+)
+
+type Review struct {
+	Id        int       `json:"id"`
+	MovieID   int       `json:"id_movie"`
+	UserID    int       `json:"id_user"`
+	Author    string    `json:"author"`
+	Rating    int       `json:"rating"`
+	Review    string    `json:"review"`
+	Status    string    `json:"status,omitempty"`
+	Reports   int       `json:"reports,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ReviewPage adalah satu halaman review untuk detail movie
+type ReviewPage struct {
+	Items []Review        `json:"items"`
+	Meta  pagination.Meta `json:"meta"`
+}
+
+type ReviewBody struct {
+	Rating int    `json:"rating" binding:"required,gte=1,lte=10"`
+	Review string `json:"review" binding:"max=2000"`
+}
+
+type ReviewUpdateBody struct {
+	Rating *int    `json:"rating" binding:"omitempty,gte=1,lte=10"`
+	Review *string `json:"review" binding:"omitempty,max=2000"`
+}
+
+type ReviewReportBody struct {
+	Reason string `json:"reason" binding:"required,max=255"`
+}
+
+type ReviewModerationBody struct {
+	Status string `json:"status" binding:"required,oneof=visible hidden"`
+}
+
+// ReviewFilter dipakai admin untuk memoderasi review
+type ReviewFilter struct {
+	Status   string `form:"status" binding:"omitempty,oneof=visible hidden"`
+	Reported bool   `form:"reported"`
+	MovieID  *int   `form:"id_movie" binding:"omitempty,gt=0"`
+}
@@ -2,33 +2,222 @@ package models
 
 import (
 	"time"
+
+	"github.com/shopspring/decimal"
 )
 
 type Schedule struct {
-	Id        int    `db:"id" json:"id"`
-	Idmovie   int    `db:"id_movie" json:"id_movie"`
-	Date      string `db:"date" json:"date"`
-	Title     string `db:"title" json:"title"`
-	Image     string `db:"image" json:"image_cinema"`
-	Id_Cinema int    `db:"id" json:"id_cinema"`
-	Cinema    string `db:"cinema" json:"cinema"`
-	Time      string `db:"id_time" json:"time"`
-	Location  string `db:"id_location" json:"tocation"`
+	Id        int       `db:"id" json:"id"`
+	Idmovie   int       `db:"id_movie" json:"id_movie"`
+	Date      string    `db:"date" json:"date"`
+	Title     string    `db:"title" json:"title"`
+	Image     string    `db:"image" json:"image_cinema"`
+	Id_Cinema int       `db:"id" json:"id_cinema"`
+	Cinema    string    `db:"cinema" json:"cinema"`
+	Id_Studio int       `db:"id_studio" json:"id_studio"`
+	Studio    string    `db:"studio" json:"studio"`
+	Time      string    `db:"time" json:"time"`
+	Location  string    `db:"id_location" json:"tocation"`
+	StartAt   time.Time `db:"start_at" json:"start_at"`
+	EndAt     time.Time `db:"end_at" json:"end_at"`
+	TimeZone  string    `db:"time_zone" json:"time_zone"`
+}
+
+// SetTimeZone menampilkan jam tayang dengan offset zona waktu cinema
+func (s *Schedule) SetTimeZone() {
+	loc := TimeZone(s.TimeZone)
+	s.StartAt, s.EndAt = s.StartAt.In(loc), s.EndAt.In(loc)
 }
 
 type BodyScheduleInput struct {
-	Id        int    `db:"id" json:"id"`
-	Date      string `db:"date" json:"date"`
-	Id_movie  int    `json:"id_movie"`
-	Id_Cinema []int  `json:"id_cinema"`
-	Time      []int  `json:"id_time"`
-	Location  []int  `json:"id_location"`
+	Id         int      `db:"id" json:"id"`
+	Date       string   `db:"date" json:"date" binding:"required,date,not_past"`
+	Id_movie   int      `json:"id_movie" binding:"required,gt=0"`
+	Id_Studio  []int    `json:"id_studio" binding:"required,dive,schedule_id"`
+	StartTimes []string `json:"start_times" binding:"required,min=1,max=12,unique,dive,clock"`
 }
 type BodySchedule struct {
 	Id          int       `db:"id" json:"id"`
 	Id_movie    int       `db:"id_movie" json:"id_movie"`
-	Date        time.Time `db:"date" json:"date"`
+	Date        string    `db:"date" json:"date"`
+	Id_Studio   int       `db:"id_studio" json:"id_studio"`
 	Id_Cinema   int       `db:"id_cinema" json:"id_cinema"`
-	Id_Time     int       `db:"id_time" json:"id_time"`
 	Id_Location int       `db:"id_location" json:"id_location"`
+	StartAt     time.Time `db:"start_at" json:"start_at"`
+	EndAt       time.Time `db:"end_at" json:"end_at"`
+	TimeZone    string    `db:"time_zone" json:"time_zone"`
+}
+
+// SetTimeZone menampilkan jam tayang dengan offset zona waktu cinema
+func (s *BodySchedule) SetTimeZone() {
+	loc := TimeZone(s.TimeZone)
+	s.StartAt, s.EndAt = s.StartAt.In(loc), s.EndAt.In(loc)
+}
+
+// ScheduleCleaningBuffer adalah jeda bersih-bersih studio setelah film selesai
+const ScheduleCleaningBuffer = 15 * time.Minute
+
+// ScheduleSlot adalah satu jadwal yang akan dibuat: tanggal, studio & jam mulai ("HH:MM")
+type ScheduleSlot struct {
+	Date      string
+	StudioID  int
+	StartTime string
+}
+
+// alasan jadwal ditolak
+const (
+	ConflictMovieUnavailable = "movie_unavailable"
+	ConflictMissingDuration  = "movie_duration_missing"
+	ConflictStudioNotFound   = "studio_not_found"
+	ConflictPastShowtime     = "past_showtime"
+	ConflictOverlap          = "overlap"
+)
+
+// ScheduleConflict menjelaskan satu jadwal yang ditolak. With diisi untuk
+// overlap, id kosong berarti bentrok dengan jadwal lain di request yang sama.
+type ScheduleConflict struct {
+	Reason    string               `json:"reason"`
+	Date      string               `json:"date,omitempty"`
+	StudioID  int                  `json:"id_studio,omitempty"`
+	StartTime string               `json:"start_time,omitempty"`
+	StartAt   *time.Time           `json:"start_at,omitempty"`
+	EndAt     *time.Time           `json:"end_at,omitempty"`
+	With      *ScheduleOverlapWith `json:"with,omitempty"`
+}
+
+type ScheduleOverlapWith struct {
+	Id      *int      `json:"id_schedule,omitempty"`
+	MovieID int       `json:"id_movie"`
+	Title   string    `json:"title,omitempty"`
+	StartAt time.Time `json:"start_at"`
+	EndAt   time.Time `json:"end_at"`
+}
+
+// MaxTemplateSchedules adalah jumlah jadwal maksimal yang dibuat satu template
+const MaxTemplateSchedules = 500
+
+// ScheduleTemplate adalah jadwal berulang: jam mulai di hari-hari tertentu dalam rentang tanggal
+type ScheduleTemplate struct {
+	Id         int         `json:"id"`
+	MovieID    int         `json:"id_movie"`
+	Title      string      `json:"title"`
+	StudioID   int         `json:"id_studio"`
+	Studio     string      `json:"studio"`
+	CinemaID   int         `json:"id_cinema"`
+	Cinema     string      `json:"cinema"`
+	TimeZone   string      `json:"time_zone"`
+	StartTimes []string    `json:"start_times"`
+	Weekdays   []int       `json:"weekdays"`
+	StartDate  time.Time   `json:"start_date"`
+	EndDate    time.Time   `json:"end_date"`
+	Exceptions []time.Time `json:"exceptions"`
+	CreatedAt  time.Time   `json:"created_at"`
+	// jumlah jadwal hasil template yang belum tayang & tidak dibatalkan
+	Upcoming  int            `json:"upcoming_schedules"`
+	Schedules []BodySchedule `json:"schedules,omitempty"`
+}
+
+type ScheduleTemplateFilter struct {
+	MovieID *int `form:"movie" binding:"omitempty,gt=0"`
+}
+
+// ScheduleTemplateBody, weekdays 0 = minggu ... 6 = sabtu
+type ScheduleTemplateBody struct {
+	MovieID    int      `json:"id_movie" binding:"required,gt=0"`
+	StudioID   int      `json:"id_studio" binding:"required,gt=0"`
+	StartTimes []string `json:"start_times" binding:"required,min=1,max=12,unique,dive,clock"`
+	Weekdays   []int    `json:"weekdays" binding:"required,min=1,max=7,unique,dive,gte=0,lte=6"`
+	StartDate  string   `json:"start_date" binding:"required,date,not_past"`
+	EndDate    string   `json:"end_date" binding:"required,date"`
+	Exceptions []string `json:"exceptions" binding:"omitempty,dive,date"`
+}
+
+// ScheduleTemplatePreview adalah hasil expand template tanpa menyimpan apa pun
+type ScheduleTemplatePreview struct {
+	Schedules []ScheduleTemplateSlot `json:"schedules"`
+	Conflicts []ScheduleConflict     `json:"conflicts"`
+}
+
+type ScheduleTemplateSlot struct {
+	Date      string    `json:"date"`
+	StudioID  int       `json:"id_studio"`
+	StartTime string    `json:"start_time"`
+	StartAt   time.Time `json:"start_at"`
+	EndAt     time.Time `json:"end_at"`
+}
+
+// ScheduleBulkBody membatasi jadwal template yang dibatalkan/digeser ke rentang tanggal,
+// kosong berarti semua jadwal yang belum tayang
+type ScheduleBulkBody struct {
+	From string `json:"from" binding:"omitempty,date"`
+	To   string `json:"to" binding:"omitempty,date"`
+}
+
+// ScheduleShiftBody menggeser jadwal sebanyak Minutes (negatif = lebih awal)
+type ScheduleShiftBody struct {
+	ScheduleBulkBody
+	Minutes int `json:"minutes" binding:"required,ne=0,gte=-720,lte=720"`
+}
+
+// ScheduleBulkResult adalah jadwal yang terkena pembatalan/pergeseran
+type ScheduleBulkResult struct {
+	ScheduleIDs []int `json:"id_schedules"`
+	// order lunas pada jadwal tersebut yang perlu ditindaklanjuti
+	PaidOrders int `json:"paid_orders"`
+}
+
+// ScheduleFilter untuk GetSchedule, date, from & to memakai tanggal & jam lokal cinema
+type ScheduleFilter struct {
+	Date       string `form:"date" binding:"omitempty,date"`
+	From       string `form:"from" binding:"omitempty,clock"`
+	To         string `form:"to" binding:"omitempty,clock"`
+	CinemaID   *int   `form:"cinema" binding:"omitempty,gt=0"`
+	LocationID *int   `form:"location" binding:"omitempty,gt=0"`
+}
+
+// DefaultSearchRadiusKm dipakai jika pencarian jadwal memakai koordinat tanpa radius
+const DefaultSearchRadiusKm = 10.0
+
+// ShowtimeSearchFilter untuk pencarian jadwal lintas cinema. Tanpa date berarti hari ini
+// di zona waktu masing-masing cinema, lat & lng harus diisi berpasangan.
+type ShowtimeSearchFilter struct {
+	MovieID    int      `form:"movie" binding:"required,gt=0"`
+	Date       string   `form:"date" binding:"omitempty,date"`
+	From       string   `form:"from" binding:"omitempty,clock"`
+	To         string   `form:"to" binding:"omitempty,clock"`
+	LocationID *int     `form:"location" binding:"omitempty,gt=0"`
+	Latitude   *float64 `form:"lat" binding:"required_with=Longitude,omitempty,latitude"`
+	Longitude  *float64 `form:"lng" binding:"required_with=Latitude,omitempty,longitude"`
+	RadiusKm   *float64 `form:"radius" binding:"omitempty,gt=0,lte=100"`
+	Formats    []string `form:"format" binding:"omitempty,dive,oneof=2D 3D IMAX 4DX"`
+}
+
+// CinemaShowtimes adalah hasil pencarian jadwal untuk satu cinema
+type CinemaShowtimes struct {
+	CinemaID  int      `json:"id_cinema"`
+	Cinema    string   `json:"cinema"`
+	Image     string   `json:"image_cinema,omitempty"`
+	Address   string   `json:"address"`
+	Location  string   `json:"location"`
+	Latitude  *float64 `json:"latitude"`
+	Longitude *float64 `json:"longitude"`
+	// jarak dari koordinat pencarian, kosong jika tidak mencari dengan koordinat
+	DistanceKm *float64          `json:"distance_km,omitempty"`
+	TimeZone   string            `json:"time_zone"`
+	Showtimes  []ShowtimeSummary `json:"showtimes"`
+}
+
+// ShowtimeSummary adalah satu jadwal beserta sisa kursi & harga tiket
+type ShowtimeSummary struct {
+	ScheduleID int       `json:"id_schedule"`
+	StudioID   int       `json:"id_studio"`
+	Studio     string    `json:"studio"`
+	Format     string    `json:"format"`
+	StartAt    time.Time `json:"start_at"`
+	EndAt      time.Time `json:"end_at"`
+	Time       string    `json:"time"`
+	Capacity   int       `json:"capacity"`
+	SeatsLeft  int       `json:"seats_left"`
+	// harga kursi regular kategori general, harga akhir lihat quote order
+	Price decimal.Decimal `json:"price"`
 }
@@ -8,6 +8,8 @@ type Seat struct {
 	Id     int     `db:"id" json:"id"`
 	Code   string  `db:"codeseat" json:"seat"`
 	Status bool    `db:"isstatus" json:"status"`
+	// regular, premium atau sweetbox
+	SeatType string `db:"seat_type" json:"seat_type"`
 }
 func (s Seat) MarshalJSON() ([]byte, error) {
 	type SeatAlias Seat
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"sync"
+	"time"
+	// zona waktu cinema tetap tersedia di image tanpa tzdata
+	_ "time/tzdata"
+)
+
+// DefaultTimeZone adalah zona waktu cinema yang tidak diisi, juga dipakai untuk
+// tanggal rilis film yang berlaku nasional
+const DefaultTimeZone = "Asia/Jakarta"
+
+var timeZones sync.Map
+
+// TimeZone memuat zona waktu IANA, nama yang tidak dikenal memakai DefaultTimeZone
+func TimeZone(name string) *time.Location {
+	if loc, ok := timeZones.Load(name); ok {
+		return loc.(*time.Location)
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil || name == "" {
+		if name == DefaultTimeZone {
+			panic(err)
+		}
+		return TimeZone(DefaultTimeZone)
+	}
+	timeZones.Store(name, loc)
+	return loc
+}
@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+// Tujuan upload menentukan preset gambar dan tempat gambar dipasang
+const (
+	UploadPoster   = "poster"
+	UploadBackdrop = "backdrop"
+	UploadAvatar   = "avatar"
+)
+
+// Status upload
+const (
+	UploadPending   = "pending"
+	UploadConfirmed = "confirmed"
+	UploadAttached  = "attached"
+)
+
+type Upload struct {
+	Id        string    `db:"id" json:"upload_id"`
+	UserID    int       `db:"id_user" json:"-"`
+	Purpose   string    `db:"purpose" json:"purpose"`
+	ObjectKey string    `db:"object_key" json:"-"`
+	ImageKey  *string   `db:"image_key" json:"image,omitempty"`
+	Status    string    `db:"status" json:"status"`
+	ExpiresAt time.Time `db:"expires_at" json:"expires_at"`
+	Images    *ImageSet `json:"images,omitempty"`
+}
+
+// UploadBody adalah request untuk membuat presigned upload URL
+type UploadBody struct {
+	Purpose string `json:"purpose" binding:"required,oneof=poster backdrop avatar"`
+}
+
+// UploadTicket adalah response berisi URL untuk upload langsung ke storage
+type UploadTicket struct {
+	UploadID  string    `json:"upload_id"`
+	Method    string    `json:"method"`
+	URL       string    `json:"url"`
+	MaxSize   int64     `json:"max_size"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// ConfirmUploadBody menentukan movie yang dipasangi gambar poster/backdrop.
+// Tanpa movie_id upload hanya di-confirm dan dipasang nanti lewat create/edit movie.
+// Untuk avatar gambar langsung dipasang ke profile user yang login.
+type ConfirmUploadBody struct {
+	MovieID *int `json:"movie_id" binding:"omitempty,gt=0"`
+}
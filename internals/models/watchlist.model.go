@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// tipe notifikasi
+const (
+	NotificationFirstSchedule = "first_schedule"
+)
+
+type WatchlistItem struct {
+	MovieID     int       `json:"id_movie"`
+	Title       string    `json:"title"`
+	Image       string    `json:"poster_path,omitempty"`
+	ReleaseDate time.Time `json:"release_date,omitzero"`
+	AgeRating   string    `json:"age_rating,omitempty"`
+	Notify      bool      `json:"notify"`
+	CreatedAt   time.Time `json:"created_at"`
+	Posters     *ImageSet `json:"poster_images,omitempty"`
+}
+
+// SetImages mengisi URL variant poster dari key di database
+func (w *WatchlistItem) SetImages() {
+	w.Posters = NewImageSet(w.Image)
+}
+
+// WatchlistBody menambahkan movie ke watchlist. notify = true berarti user
+// diberi notifikasi saat movie mendapat jadwal pertama di lokasi favoritnya.
+type WatchlistBody struct {
+	MovieID int  `json:"id_movie" binding:"required,gt=0"`
+	Notify  bool `json:"notify"`
+}
+
+type Notification struct {
+	Id         int        `json:"id"`
+	Type       string     `json:"type"`
+	MovieID    *int       `json:"id_movie,omitempty"`
+	Title      *string    `json:"title,omitempty"`
+	LocationID *int       `json:"id_location,omitempty"`
+	Location   *string    `json:"location,omitempty"`
+	ScheduleID *int       `json:"id_schedule,omitempty"`
+	ReadAt     *time.Time `json:"read_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type NotificationFilter struct {
+	Unread bool `form:"unread"`
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/federus1105/weekly/internals/models"
 	"github.com/federus1105/weekly/pkg"
+	"github.com/federus1105/weekly/pkg/apperror"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/redis/go-redis/v9"
@@ -30,7 +31,7 @@ func (a *AuthRepository) GetUserWithPasswordAndRole(rctx context.Context, email
 	var user models.User
 	if err := a.db.QueryRow(rctx, sql, email).Scan(&user.Id, &user.Email, &user.Password, &user.Role); err != nil {
 		if err == pgx.ErrNoRows {
-			return models.User{}, errors.New("user not found")
+			return models.User{}, apperror.NotFound(apperror.CodeUserNotFound, "")
 		}
 		log.Println("Internal Server Error.\nCause: ", err.Error())
 		return models.User{}, err
@@ -52,6 +53,7 @@ func (r *AuthRepository) Register(ctx context.Context, user models.UserRegister)
 	hashedPassword, err := hc.GenHash(user.Password)
 	if err != nil {
 		log.Println("Error hashing password:", err)
+		return models.UserRegister{}, err
 	}
 
 	sql := `INSERT INTO users (email, password) VALUES ($1, $2) RETURNING id, email, password`
@@ -59,6 +61,9 @@ func (r *AuthRepository) Register(ctx context.Context, user models.UserRegister)
 	var newUser models.UserRegister
 	if err := tx.QueryRow(ctx, sql, values...).Scan(&newUser.Id, &newUser.Email, &newUser.Password); err != nil {
 		log.Println("Failed to insert into users: ", err.Error())
+		if errors.Is(apperror.FromDB(err), apperror.ErrConflict) {
+			return models.UserRegister{}, apperror.Conflict(apperror.CodeEmailTaken, "")
+		}
 		return models.UserRegister{}, err
 	}
 	accountSQL := `
@@ -86,7 +91,10 @@ func (r *AuthRepository) ResetPassword(ctx context.Context, userID int, oldPassw
 	err := r.db.QueryRow(ctx, "SELECT password FROM users WHERE id = $1", userID).Scan(&hashedDB)
 	if err != nil {
 		log.Println("Failed to get current password hash:", err)
-		return fmt.Errorf("user tidak ditemukan")
+		if errors.Is(err, pgx.ErrNoRows) {
+			return apperror.NotFound(apperror.CodeUserNotFound, "")
+		}
+		return err
 	}
 
 	// Step 2: Verify password lama cocok
@@ -95,24 +103,24 @@ func (r *AuthRepository) ResetPassword(ctx context.Context, userID int, oldPassw
 	ok, err := hc.CompareHashAndPassword(oldPassword, hashedDB)
 	if err != nil {
 		log.Println("Error comparing password hash:", err)
-		return fmt.Errorf("gagal memverifikasi password lama")
+		return fmt.Errorf("verify old password: %w", err)
 	}
 	if !ok {
-		return fmt.Errorf("password lama tidak cocok")
+		return apperror.Validation(apperror.CodeWrongPassword, "")
 	}
 
 	// Step 3: Hash password baru
 	newHashed, err := hc.GenHash(newPassword)
 	if err != nil {
 		log.Println("Failed to hash new password:", err)
-		return fmt.Errorf("gagal hash password baru")
+		return fmt.Errorf("hash new password: %w", err)
 	}
 
 	// Step 4: Update password di database
 	_, err = r.db.Exec(ctx, "UPDATE users SET password = $1 WHERE id = $2", newHashed, userID)
 	if err != nil {
 		log.Println("Failed to update password:", err)
-		return fmt.Errorf("gagal update password")
+		return fmt.Errorf("update password: %w", err)
 	}
 
 	log.Println("Password updated for user id:", userID)
@@ -0,0 +1,311 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/federus1105/weekly/internals/models"
+	"github.com/federus1105/weekly/pkg/apperror"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type CinemaRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewCinemaRepository(db *pgxpool.Pool) *CinemaRepository {
+	return &CinemaRepository{db: db}
+}
+
+func (cr *CinemaRepository) GetLocations(rctx context.Context) ([]models.Location, error) {
+	rows, err := cr.db.Query(rctx, `SELECT id, name FROM location ORDER BY name ASC, id ASC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	locations := []models.Location{}
+	for rows.Next() {
+		var l models.Location
+		if err := rows.Scan(&l.Id, &l.Name); err != nil {
+			return nil, err
+		}
+		locations = append(locations, l)
+	}
+	return locations, rows.Err()
+}
+
+func (cr *CinemaRepository) CreateLocation(rctx context.Context, name string) (models.Location, error) {
+	var l models.Location
+	err := cr.db.QueryRow(rctx, `INSERT INTO location (name) VALUES ($1) RETURNING id, name`, name).Scan(&l.Id, &l.Name)
+	return l, err
+}
+
+const cinemaColumns = `c.id, c.name, c.image, c.price, c.id_location, l.name, c.address, c.latitude, c.longitude, c.facilities, c.time_zone`
+
+// selectCinema dipakai setelah INSERT/UPDATE ... RETURNING * dengan alias c
+const selectCinema = `
+		SELECT ` + cinemaColumns + `
+		FROM c
+		LEFT JOIN location l ON l.id = c.id_location`
+
+func scanCinema(row pgx.Row, c *models.Cinema) error {
+	if err := row.Scan(&c.Id, &c.Name, &c.Image, &c.Price, &c.LocationID, &c.Location, &c.Address,
+		&c.Latitude, &c.Longitude, &c.Facilities, &c.TimeZone); err != nil {
+		return err
+	}
+	c.SetImages()
+	return nil
+}
+
+// GetCinemas mengambil semua cinema, bisa difilter per location
+func (cr *CinemaRepository) GetCinemas(rctx context.Context, filter models.CinemaFilter) ([]models.Cinema, error) {
+	sql := `
+		SELECT ` + cinemaColumns + `
+		FROM cinema c
+		LEFT JOIN location l ON l.id = c.id_location`
+	args := []any{}
+	if filter.LocationID != nil {
+		sql += ` WHERE c.id_location = $1`
+		args = append(args, *filter.LocationID)
+	}
+	sql += ` ORDER BY l.name ASC NULLS LAST, c.name ASC, c.id ASC`
+
+	rows, err := cr.db.Query(rctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cinemas := []models.Cinema{}
+	for rows.Next() {
+		var c models.Cinema
+		if err := scanCinema(rows, &c); err != nil {
+			return nil, err
+		}
+		cinemas = append(cinemas, c)
+	}
+	return cinemas, rows.Err()
+}
+
+// GetCinema mengambil detail cinema beserta studionya
+func (cr *CinemaRepository) GetCinema(rctx context.Context, cinemaID int) (models.Cinema, error) {
+	var c models.Cinema
+	err := scanCinema(cr.db.QueryRow(rctx, `
+		SELECT `+cinemaColumns+`
+		FROM cinema c
+		LEFT JOIN location l ON l.id = c.id_location
+		WHERE c.id = $1`, cinemaID), &c)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.Cinema{}, apperror.NotFound(apperror.CodeCinemaNotFound, "")
+	}
+	if err != nil {
+		return models.Cinema{}, err
+	}
+
+	rows, err := cr.db.Query(rctx, `
+		SELECT id, id_cinema, name, capacity, format
+		FROM studio
+		WHERE id_cinema = $1
+		ORDER BY name ASC, id ASC`, cinemaID)
+	if err != nil {
+		return models.Cinema{}, err
+	}
+	defer rows.Close()
+
+	c.Studios = []models.Studio{}
+	for rows.Next() {
+		var s models.Studio
+		if err := rows.Scan(&s.Id, &s.CinemaID, &s.Name, &s.Capacity, &s.Format); err != nil {
+			return models.Cinema{}, err
+		}
+		c.Studios = append(c.Studios, s)
+	}
+	return c, rows.Err()
+}
+
+func (cr *CinemaRepository) CreateCinema(rctx context.Context, body models.CinemaBody) (models.Cinema, error) {
+	facilities := body.Facilities
+	if facilities == nil {
+		facilities = []string{}
+	}
+	timeZone := body.TimeZone
+	if timeZone == "" {
+		timeZone = models.DefaultTimeZone
+	}
+	var c models.Cinema
+	err := scanCinema(cr.db.QueryRow(rctx, `
+		WITH c AS (
+			INSERT INTO cinema (name, image, price, id_location, address, latitude, longitude, facilities, time_zone)
+			VALUES ($1, '', $2, $3, $4, $5, $6, $7, $8)
+			RETURNING *
+		)`+selectCinema,
+		body.Name, body.Price, body.LocationID, body.Address, body.Latitude, body.Longitude, facilities, timeZone), &c)
+	if err != nil {
+		return models.Cinema{}, err
+	}
+	return c, nil
+}
+
+func (cr *CinemaRepository) UpdateCinema(rctx context.Context, cinemaID int, body models.CinemaUpdateBody) (models.Cinema, error) {
+	setClauses := []string{}
+	args := []any{}
+	argID := 1
+
+	add := func(column string, value any) {
+		setClauses = append(setClauses, fmt.Sprintf("%s = $%d", column, argID))
+		args = append(args, value)
+		argID++
+	}
+	if body.Name != nil {
+		add("name", *body.Name)
+	}
+	if body.LocationID != nil {
+		add("id_location", *body.LocationID)
+	}
+	if body.Price != nil {
+		add("price", *body.Price)
+	}
+	if body.Address != nil {
+		add("address", *body.Address)
+	}
+	if body.Latitude != nil {
+		add("latitude", *body.Latitude)
+		add("longitude", *body.Longitude)
+	}
+	if body.Facilities != nil {
+		add("facilities", body.Facilities)
+	}
+	// jadwal yang sudah ada tetap di instant yang sama, hanya tampilan jam lokalnya yang berubah
+	if body.TimeZone != nil {
+		add("time_zone", *body.TimeZone)
+	}
+	if len(setClauses) == 0 {
+		return models.Cinema{}, apperror.Validation(apperror.CodeNoFieldsToUpdate, "")
+	}
+
+	sql := fmt.Sprintf(`
+		WITH c AS (
+			UPDATE cinema
+			SET %s
+			WHERE id = $%d
+			RETURNING *
+		)`+selectCinema, strings.Join(setClauses, ", "), argID)
+	args = append(args, cinemaID)
+
+	var c models.Cinema
+	err := scanCinema(cr.db.QueryRow(rctx, sql, args...), &c)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.Cinema{}, apperror.NotFound(apperror.CodeCinemaNotFound, "")
+	}
+	if err != nil {
+		return models.Cinema{}, err
+	}
+	return c, nil
+}
+
+// CreateStudio menambah studio, nama studio unik per cinema
+func (cr *CinemaRepository) CreateStudio(rctx context.Context, cinemaID int, body models.StudioBody) (models.Studio, error) {
+	if body.Format == "" {
+		body.Format = models.Format2D
+	}
+	var s models.Studio
+	err := cr.db.QueryRow(rctx, `
+		INSERT INTO studio (id_cinema, name, capacity, format)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, id_cinema, name, capacity, format`,
+		cinemaID, body.Name, body.Capacity, body.Format).Scan(&s.Id, &s.CinemaID, &s.Name, &s.Capacity, &s.Format)
+	if err != nil {
+		return models.Studio{}, studioError(err)
+	}
+	return s, nil
+}
+
+func (cr *CinemaRepository) UpdateStudio(rctx context.Context, cinemaID, studioID int, body models.StudioUpdateBody) (models.Studio, error) {
+	setClauses := []string{}
+	args := []any{}
+	argID := 1
+
+	if body.Name != nil {
+		setClauses = append(setClauses, fmt.Sprintf("name = $%d", argID))
+		args = append(args, *body.Name)
+		argID++
+	}
+	if body.Capacity != nil {
+		setClauses = append(setClauses, fmt.Sprintf("capacity = $%d", argID))
+		args = append(args, *body.Capacity)
+		argID++
+	}
+	if body.Format != nil {
+		setClauses = append(setClauses, fmt.Sprintf("format = $%d", argID))
+		args = append(args, *body.Format)
+		argID++
+	}
+	if len(setClauses) == 0 {
+		return models.Studio{}, apperror.Validation(apperror.CodeNoFieldsToUpdate, "")
+	}
+
+	sql := fmt.Sprintf(`
+		UPDATE studio
+		SET %s
+		WHERE id = $%d AND id_cinema = $%d
+		RETURNING id, id_cinema, name, capacity, format`,
+		strings.Join(setClauses, ", "), argID, argID+1)
+	args = append(args, studioID, cinemaID)
+
+	var s models.Studio
+	err := cr.db.QueryRow(rctx, sql, args...).Scan(&s.Id, &s.CinemaID, &s.Name, &s.Capacity, &s.Format)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.Studio{}, apperror.NotFound(apperror.CodeStudioNotFound, "")
+	}
+	if err != nil {
+		return models.Studio{}, studioError(err)
+	}
+	return s, nil
+}
+
+// DeleteStudio hanya untuk studio yang belum pernah punya jadwal atau template jadwal
+func (cr *CinemaRepository) DeleteStudio(rctx context.Context, cinemaID, studioID int) error {
+	var exists, scheduled bool
+	err := cr.db.QueryRow(rctx, `
+		SELECT
+			EXISTS (SELECT 1 FROM studio WHERE id = $1 AND id_cinema = $2),
+			EXISTS (SELECT 1 FROM schedule WHERE id_studio = $1)
+				OR EXISTS (SELECT 1 FROM schedule_templates WHERE id_studio = $1)`,
+		studioID, cinemaID).Scan(&exists, &scheduled)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return apperror.NotFound(apperror.CodeStudioNotFound, "")
+	}
+	if scheduled {
+		return apperror.Conflict(apperror.CodeStudioInUse, "")
+	}
+
+	_, err = cr.db.Exec(rctx, `DELETE FROM studio WHERE id = $1 AND id_cinema = $2`, studioID, cinemaID)
+	if err != nil {
+		// jadwal baru dibuat di antara pengecekan & delete
+		if appErr, ok := apperror.As(apperror.FromDB(err)); ok && appErr.Code == apperror.CodeReferenceNotFound {
+			return apperror.Conflict(apperror.CodeStudioInUse, "").Wrap(err)
+		}
+		return err
+	}
+	return nil
+}
+
+// studioError memetakan pelanggaran constraint studio ke kode error yang jelas
+func studioError(err error) error {
+	if appErr, ok := apperror.As(apperror.FromDB(err)); ok {
+		switch appErr.Code {
+		case apperror.CodeConflict:
+			return apperror.Conflict(apperror.CodeStudioExists, "").Wrap(err)
+		case apperror.CodeReferenceNotFound:
+			return apperror.NotFound(apperror.CodeCinemaNotFound, "").Wrap(err)
+		}
+	}
+	return err
+}
@@ -3,9 +3,11 @@ package repositories
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/federus1105/weekly/internals/middlewares"
 	"github.com/federus1105/weekly/internals/models"
+	"github.com/federus1105/weekly/pkg/pagination"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -17,46 +19,60 @@ func NewHistoryRepository(db *pgxpool.Pool) *HistoryRepository {
 	return &HistoryRepository{db: db}
 }
 
-func (hr *HistoryRepository) GetHistory(rctx context.Context, UserID int) ([]models.History, error) {
+func (hr *HistoryRepository) GetHistory(rctx context.Context, UserID int, req pagination.Request) ([]models.History, pagination.Meta, error) {
 	userIDRaw := rctx.Value(middlewares.UserIDKey)
 	userID, ok := userIDRaw.(int)
 	if !ok {
-		return nil, fmt.Errorf("invalid or missing user ID in context")
+		return nil, pagination.Meta{}, fmt.Errorf("invalid or missing user ID in context")
 	}
 
-	sql := `SELECT
+	keyset := pagination.Keyset{Column: "COALESCE(o.created_at, 'epoch'::timestamp)", Cast: "timestamp", IDColumn: "o.id"}
+	conditions := []string{"o.id_user = $1"}
+	args := []any{userID}
+	if where, whereArgs := keyset.Where(req.Cursor, 2); where != "" {
+		conditions = append(conditions, where)
+		args = append(args, whereArgs...)
+	}
+
+	sql := fmt.Sprintf(`SELECT
       o.id AS id_order,
       m.title AS movie_title,
       STRING_AGG(s2.codeseat, ', ') AS seat_codes,
       COUNT(os.id_seats) AS total_seats,
-      t.name AS time_name,
+      to_char(%s, 'HH24:MI') AS time_name,
       o.total,
       c.name AS cinema_name,
-      o.paid
+      o.paid,
+      COALESCE(o.created_at, 'epoch'::timestamp) AS created_at
     FROM orders o
     JOIN schedule s ON o.id_schedule = s.id
     JOIN movies m ON s.id_movie = m.id
-    JOIN cinema c ON s.id_cinema = c.id
-    JOIN time t ON s.id_time = t.id
+    JOIN studio st ON s.id_studio = st.id
+    JOIN cinema c ON st.id_cinema = c.id
     LEFT JOIN order_seat os ON o.id = os.id_order
     LEFT JOIN seats s2 ON os.id_seats = s2.id 
-    WHERE o.id_user = $1
-    GROUP BY o.id, m.title, t.name, o.total, c.name, o.paid
-    ORDER BY o.created_at ASC;`
+    WHERE %s
+    GROUP BY o.id, m.title, s.start_at, o.total, c.name, o.paid, o.created_at
+    %s
+    LIMIT $%d OFFSET $%d;`, localStartSQL, strings.Join(conditions, " AND "), keyset.OrderBy(req.Cursor), len(args)+1, len(args)+2)
+	args = append(args, req.Limit+1, req.Offset())
 
-	rows, err := hr.db.Query(rctx, sql, userID)
+	rows, err := hr.db.Query(rctx, sql, args...)
 	if err != nil {
-		return nil, err
+		return nil, pagination.Meta{}, err
 	}
 	defer rows.Close()
 
 	var histories []models.History
 	for rows.Next() {
 		var history models.History
-		if err := rows.Scan(&history.IDOrder, &history.Movie, &history.Seat, &history.TotalSeat, &history.Time, &history.Total, &history.Cinema, &history.Paid); err != nil {
-			return nil, err
+		if err := rows.Scan(&history.IDOrder, &history.Movie, &history.Seat, &history.TotalSeat, &history.Time, &history.Total, &history.Cinema, &history.Paid, &history.CreatedAt); err != nil {
+			return nil, pagination.Meta{}, err
 		}
 		histories = append(histories, history)
 	}
-	return histories, nil
+	histories, meta := pagination.Slice(histories, req, func(h models.History) (string, int) {
+		return h.CreatedAt.Format("2006-01-02 15:04:05.999999"), h.IDOrder
+	})
+	return histories, meta, nil
 }
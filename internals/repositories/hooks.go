@@ -0,0 +1,14 @@
+package repositories
+
+import "context"
+
+// BeforeCommit dijalankan di akhir transaksi tepat sebelum commit, misal untuk
+// menulis file upload. Jika mengembalikan error, transaksi di-rollback.
+type BeforeCommit func(ctx context.Context) error
+
+func runBeforeCommit(ctx context.Context, hook BeforeCommit) error {
+	if hook == nil {
+		return nil
+	}
+	return hook(ctx)
+}
@@ -0,0 +1,43 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ImageRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewImageRepository(db *pgxpool.Pool) *ImageRepository {
+	return &ImageRepository{db: db}
+}
+
+// GetImageKeys mengambil semua key gambar yang masih dirujuk database.
+// Movie yang di-soft delete tetap dihitung supaya datanya bisa dipulihkan,
+// begitu juga upload yang sudah dikonfirmasi tapi belum dipasang.
+func (ir *ImageRepository) GetImageKeys(rctx context.Context) ([]string, error) {
+	sql := `SELECT image FROM movies WHERE image <> ''
+		UNION SELECT backdrop FROM movies WHERE backdrop <> ''
+		UNION SELECT image FROM account WHERE image <> ''
+		UNION SELECT image FROM cinema WHERE image <> ''
+		UNION SELECT image FROM payment_method WHERE image <> ''
+		UNION SELECT image FROM movie_stills
+		UNION SELECT image_key FROM uploads WHERE status = 'confirmed' AND expires_at > now()`
+	rows, err := ir.db.Query(rctx, sql)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
@@ -0,0 +1,306 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/federus1105/weekly/internals/models"
+	"github.com/federus1105/weekly/pkg/apperror"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// querier dipenuhi oleh *pgxpool.Pool maupun pgx.Tx
+type querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+type MediaRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewMediaRepository(db *pgxpool.Pool) *MediaRepository {
+	return &MediaRepository{db: db}
+}
+
+// GetMovieMedia mengambil galeri trailer & still dari movie yang belum dihapus
+func (mr *MediaRepository) GetMovieMedia(rctx context.Context, movieID int) (models.MovieMedia, error) {
+	var exists bool
+	err := mr.db.QueryRow(rctx, `SELECT EXISTS (SELECT 1 FROM movies WHERE id = $1 AND is_deleted = false)`, movieID).Scan(&exists)
+	if err != nil {
+		return models.MovieMedia{}, err
+	}
+	if !exists {
+		return models.MovieMedia{}, apperror.NotFound(apperror.CodeMovieNotFound, "")
+	}
+	return getMovieMedia(rctx, mr.db, movieID)
+}
+
+// getMovieMedia dipakai juga oleh GetDetailMovie
+func getMovieMedia(rctx context.Context, db querier, movieID int) (models.MovieMedia, error) {
+	media := models.MovieMedia{Trailers: []models.Trailer{}, Stills: []models.Still{}}
+
+	rows, err := db.Query(rctx, `
+		SELECT id, id_movie, title, url, provider, duration, position
+		FROM movie_trailers
+		WHERE id_movie = $1
+		ORDER BY position, id`, movieID)
+	if err != nil {
+		return models.MovieMedia{}, err
+	}
+	for rows.Next() {
+		var t models.Trailer
+		if err := rows.Scan(&t.Id, &t.MovieID, &t.Title, &t.URL, &t.Provider, &t.Duration, &t.Position); err != nil {
+			rows.Close()
+			return models.MovieMedia{}, err
+		}
+		media.Trailers = append(media.Trailers, t)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return models.MovieMedia{}, err
+	}
+
+	stills, err := queryStills(rctx, db, movieID)
+	if err != nil {
+		return models.MovieMedia{}, err
+	}
+	media.Stills = stills
+	return media, nil
+}
+
+func queryStills(rctx context.Context, db querier, movieID int) ([]models.Still, error) {
+	rows, err := db.Query(rctx, `
+		SELECT id, id_movie, image, caption, position
+		FROM movie_stills
+		WHERE id_movie = $1
+		ORDER BY position, id`, movieID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stills := []models.Still{}
+	for rows.Next() {
+		var s models.Still
+		if err := rows.Scan(&s.Id, &s.MovieID, &s.Image, &s.Caption, &s.Position); err != nil {
+			return nil, err
+		}
+		s.SetImages()
+		stills = append(stills, s)
+	}
+	return stills, rows.Err()
+}
+
+// CreateTrailer menambah trailer, position kosong berarti setelah trailer terakhir
+func (mr *MediaRepository) CreateTrailer(rctx context.Context, movieID int, body models.TrailerBody) (models.Trailer, error) {
+	sql := `
+		INSERT INTO movie_trailers (id_movie, title, url, provider, duration, position)
+		SELECT m.id, $2, $3, $4, $5,
+			COALESCE($6, (SELECT COALESCE(MAX(position) + 1, 0) FROM movie_trailers WHERE id_movie = m.id))
+		FROM movies m
+		WHERE m.id = $1 AND m.is_deleted = false
+		RETURNING id, id_movie, title, url, provider, duration, position`
+	var t models.Trailer
+	err := mr.db.QueryRow(rctx, sql, movieID, body.Title, body.URL, body.Provider, body.Duration, body.Position).
+		Scan(&t.Id, &t.MovieID, &t.Title, &t.URL, &t.Provider, &t.Duration, &t.Position)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.Trailer{}, apperror.NotFound(apperror.CodeMovieNotFound, "")
+	}
+	if err != nil {
+		return models.Trailer{}, err
+	}
+	return t, nil
+}
+
+func (mr *MediaRepository) UpdateTrailer(rctx context.Context, movieID, trailerID int, body models.TrailerUpdateBody) (models.Trailer, error) {
+	setClauses := []string{}
+	args := []any{}
+	argID := 1
+
+	add := func(column string, value any) {
+		setClauses = append(setClauses, fmt.Sprintf("%s = $%d", column, argID))
+		args = append(args, value)
+		argID++
+	}
+	if body.Title != nil {
+		add("title", *body.Title)
+	}
+	if body.URL != nil {
+		add("url", *body.URL)
+	}
+	if body.Provider != nil {
+		add("provider", *body.Provider)
+	}
+	if body.Duration != nil {
+		add("duration", *body.Duration)
+	}
+	if body.Position != nil {
+		add("position", *body.Position)
+	}
+	if len(setClauses) == 0 {
+		return models.Trailer{}, apperror.Validation(apperror.CodeNoFieldsToUpdate, "")
+	}
+
+	sql := fmt.Sprintf(`
+		UPDATE movie_trailers
+		SET %s
+		WHERE id = $%d AND id_movie = $%d
+		RETURNING id, id_movie, title, url, provider, duration, position`,
+		strings.Join(setClauses, ", "), argID, argID+1)
+	args = append(args, trailerID, movieID)
+
+	var t models.Trailer
+	err := mr.db.QueryRow(rctx, sql, args...).
+		Scan(&t.Id, &t.MovieID, &t.Title, &t.URL, &t.Provider, &t.Duration, &t.Position)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.Trailer{}, apperror.NotFound(apperror.CodeTrailerNotFound, "")
+	}
+	if err != nil {
+		return models.Trailer{}, err
+	}
+	return t, nil
+}
+
+func (mr *MediaRepository) DeleteTrailer(rctx context.Context, movieID, trailerID int) error {
+	tag, err := mr.db.Exec(rctx, `DELETE FROM movie_trailers WHERE id = $1 AND id_movie = $2`, trailerID, movieID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return apperror.NotFound(apperror.CodeTrailerNotFound, "")
+	}
+	return nil
+}
+
+// CreateStill menyimpan still baru. beforeCommit dipakai untuk menulis file
+// gambar ke storage sebelum transaksi di-commit.
+func (mr *MediaRepository) CreateStill(rctx context.Context, movieID int, image, caption string,
+	position *int, beforeCommit BeforeCommit) (models.Still, error) {
+	tx, err := mr.db.Begin(rctx)
+	if err != nil {
+		return models.Still{}, err
+	}
+	defer tx.Rollback(rctx)
+
+	sql := `
+		INSERT INTO movie_stills (id_movie, image, caption, position)
+		SELECT m.id, $2, $3,
+			COALESCE($4, (SELECT COALESCE(MAX(position) + 1, 0) FROM movie_stills WHERE id_movie = m.id))
+		FROM movies m
+		WHERE m.id = $1 AND m.is_deleted = false
+		RETURNING id, id_movie, image, caption, position`
+	var s models.Still
+	err = tx.QueryRow(rctx, sql, movieID, image, caption, position).
+		Scan(&s.Id, &s.MovieID, &s.Image, &s.Caption, &s.Position)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.Still{}, apperror.NotFound(apperror.CodeMovieNotFound, "")
+	}
+	if err != nil {
+		return models.Still{}, err
+	}
+
+	if err := runBeforeCommit(rctx, beforeCommit); err != nil {
+		return models.Still{}, err
+	}
+	if err := tx.Commit(rctx); err != nil {
+		return models.Still{}, err
+	}
+	s.SetImages()
+	return s, nil
+}
+
+func (mr *MediaRepository) UpdateStill(rctx context.Context, movieID, stillID int, body models.StillUpdateBody) (models.Still, error) {
+	if body.Caption == nil && body.Position == nil {
+		return models.Still{}, apperror.Validation(apperror.CodeNoFieldsToUpdate, "")
+	}
+	sql := `
+		UPDATE movie_stills
+		SET caption = COALESCE($1, caption), position = COALESCE($2, position)
+		WHERE id = $3 AND id_movie = $4
+		RETURNING id, id_movie, image, caption, position`
+	var s models.Still
+	err := mr.db.QueryRow(rctx, sql, body.Caption, body.Position, stillID, movieID).
+		Scan(&s.Id, &s.MovieID, &s.Image, &s.Caption, &s.Position)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.Still{}, apperror.NotFound(apperror.CodeStillNotFound, "")
+	}
+	if err != nil {
+		return models.Still{}, err
+	}
+	s.SetImages()
+	return s, nil
+}
+
+// DeleteStill mengembalikan key gambar still supaya file-nya bisa dihapus
+func (mr *MediaRepository) DeleteStill(rctx context.Context, movieID, stillID int) (string, error) {
+	var image string
+	err := mr.db.QueryRow(rctx, `DELETE FROM movie_stills WHERE id = $1 AND id_movie = $2 RETURNING image`, stillID, movieID).
+		Scan(&image)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", apperror.NotFound(apperror.CodeStillNotFound, "")
+	}
+	if err != nil {
+		return "", err
+	}
+	return image, nil
+}
+
+// ReorderStills mengatur ulang position semua still movie sesuai urutan ids
+func (mr *MediaRepository) ReorderStills(rctx context.Context, movieID int, ids []int) ([]models.Still, error) {
+	tx, err := mr.db.Begin(rctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(rctx)
+
+	// kunci still movie supaya tidak ada yang ditambah/dihapus saat diurutkan
+	rows, err := tx.Query(rctx, `SELECT id FROM movie_stills WHERE id_movie = $1 FOR UPDATE`, movieID)
+	if err != nil {
+		return nil, err
+	}
+	current := map[int]bool{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		current[id] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(ids) != len(current) {
+		return nil, apperror.Validation(apperror.CodeInvalidStillOrder, "").
+			WithDetails(map[string]any{"expected": len(current)})
+	}
+	for _, id := range ids {
+		if !current[id] {
+			return nil, apperror.Validation(apperror.CodeInvalidStillOrder, "").
+				WithDetails(map[string]any{"still_id": id})
+		}
+	}
+
+	_, err = tx.Exec(rctx, `
+		UPDATE movie_stills s
+		SET position = o.idx - 1
+		FROM unnest($1::int4[]) WITH ORDINALITY AS o(id, idx)
+		WHERE s.id = o.id AND s.id_movie = $2`, ids, movieID)
+	if err != nil {
+		return nil, err
+	}
+
+	stills, err := queryStills(rctx, tx, movieID)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(rctx); err != nil {
+		return nil, err
+	}
+	return stills, nil
+}
@@ -6,10 +6,13 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/federus1105/weekly/internals/models"
+	"github.com/federus1105/weekly/pkg/apperror"
+	"github.com/federus1105/weekly/pkg/pagination"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/redis/go-redis/v9"
@@ -24,9 +27,14 @@ func NewMoviesRepository(db *pgxpool.Pool, rdb *redis.Client) *MoviesRepository
 	return &MoviesRepository{db: db, rdb: rdb}
 }
 
-func (mr *MoviesRepository) GetUpcomingMovies(rctx context.Context, limit, offset int) ([]models.Movie, error) {
-	start := time.Now()
-	redisKey := "firdaus:popular-movies"
+// halaman movie yang disimpan di redis
+type cachedMoviePage struct {
+	Movies []models.Movie  `json:"movies"`
+	Meta   pagination.Meta `json:"meta"`
+}
+
+func (mr *MoviesRepository) getCachedMoviePage(rctx context.Context, redisKey string) (cachedMoviePage, bool) {
+	var cached cachedMoviePage
 	cmd := mr.rdb.Get(rctx, redisKey)
 	if cmd.Err() != nil {
 		if cmd.Err() == redis.Nil {
@@ -34,25 +42,54 @@ func (mr *MoviesRepository) GetUpcomingMovies(rctx context.Context, limit, offse
 		} else {
 			log.Println("Redis Error. \nCause: ", cmd.Err().Error())
 		}
-	} else {
-		// cache hit
-		var cachedSchedules []models.Movie
-		cmdByte, err := cmd.Bytes()
-		if err != nil {
-			log.Println("Internal server error.\nCause: ", err.Error())
-		} else {
-			if err := json.Unmarshal(cmdByte, &cachedSchedules); err != nil {
-				log.Println("Internal Server Error. \nCause: ", err.Error())
-			}
-		}
-		if len(cachedSchedules) > 0 {
+		return cached, false
+	}
+	cmdByte, err := cmd.Bytes()
+	if err != nil {
+		log.Println("Internal server error.\nCause: ", err.Error())
+		return cached, false
+	}
+	if err := json.Unmarshal(cmdByte, &cached); err != nil {
+		log.Println("Internal Server Error. \nCause: ", err.Error())
+		return cached, false
+	}
+	return cached, len(cached.Movies) > 0
+}
+
+func (mr *MoviesRepository) setCachedMoviePage(rctx context.Context, redisKey string, page cachedMoviePage) {
+	bt, err := json.Marshal(page)
+	if err != nil {
+		log.Println("Internal Server Error.\n Cause: ", err.Error())
+		return
+	}
+	if err := mr.rdb.Set(rctx, redisKey, string(bt), 1*time.Minute).Err(); err != nil {
+		log.Println("Redis Error. \nCause: ", err.Error())
+	}
+}
+
+// todaySQL adalah tanggal hari ini di zona waktu default, tanggal rilis berlaku nasional
+const todaySQL = `(now() AT TIME ZONE '` + models.DefaultTimeZone + `')::date`
+
+func (mr *MoviesRepository) GetUpcomingMovies(rctx context.Context, req pagination.Request) ([]models.Movie, pagination.Meta, error) {
+	start := time.Now()
+	redisKey := fmt.Sprintf("firdaus:upcoming-movies:%d", req.Limit)
+	if req.IsFirst() {
+		if cached, ok := mr.getCachedMoviePage(rctx, redisKey); ok {
 			log.Printf("Key %s found in cache ✅", redisKey)
 			log.Printf("Served in %s using Redis", time.Since(start))
-			return cachedSchedules, nil
+			return cached.Movies, cached.Meta, nil
 		}
 	}
+
+	keyset := pagination.Keyset{Column: "m.release_date", Cast: "date", IDColumn: "m.id"}
+	conditions := []string{"m.release_date > " + todaySQL, "m.is_deleted = false"}
+	args := []any{}
+	if where, whereArgs := keyset.Where(req.Cursor, 1); where != "" {
+		conditions = append(conditions, where)
+		args = append(args, whereArgs...)
+	}
 	// ambil data movie
-	sql := `SELECT 
+	sql := fmt.Sprintf(`SELECT 
     m.id,
     m.image,
     m.title,
@@ -61,16 +98,17 @@ func (mr *MoviesRepository) GetUpcomingMovies(rctx context.Context, limit, offse
 FROM movies m
 JOIN movies_genre mg ON m.id = mg.id_movies
 JOIN genres g ON mg.id_genre = g.id
-WHERE m.release_date > CURRENT_DATE
+WHERE %s
 GROUP BY m.id, m.image, m.title, m.release_date
-ORDER BY m.release_date ASC
-LIMIT $1 OFFSET $2
-`
+%s
+LIMIT $%d OFFSET $%d
+`, strings.Join(conditions, " AND "), keyset.OrderBy(req.Cursor), len(args)+1, len(args)+2)
+	args = append(args, req.Limit+1, req.Offset())
 
-	rows, err := mr.db.Query(rctx, sql, limit, offset)
+	rows, err := mr.db.Query(rctx, sql, args...)
 	if err != nil {
 		log.Println("Internal Server Error: ", err.Error())
-		return nil, err
+		return nil, pagination.Meta{}, err
 	}
 	defer rows.Close()
 	var movies []models.Movie
@@ -78,95 +116,98 @@ LIMIT $1 OFFSET $2
 		var movie models.Movie
 		if err := rows.Scan(&movie.Id, &movie.Image, &movie.Title, &movie.ReleaseDate, &movie.Genres); err != nil {
 			log.Println("Internal Server Error: ", err.Error())
-			return nil, err
+			return nil, pagination.Meta{}, err
 		}
+		movie.SetImages()
 		movies = append(movies, movie)
 	}
+	movies, meta := pagination.Slice(movies, req, func(m models.Movie) (string, int) {
+		return m.ReleaseDate.Format(time.DateOnly), m.Id
+	})
 	// renew cache
-	if offset == 0 {
-		bt, err := json.Marshal(movies)
-		if err != nil {
-			log.Println("Internal Server Error.\n Cause: ", err.Error())
-		}
-		if err := mr.rdb.Set(rctx, redisKey, string(bt), 1*time.Minute).Err(); err != nil {
-			log.Println("Redis Error. \nCause: ", err.Error())
-		}
+	if req.IsFirst() {
+		mr.setCachedMoviePage(rctx, redisKey, cachedMoviePage{Movies: movies, Meta: meta})
 	}
 	log.Printf("[REDIS TIMING] Served in %s using DB (cache miss)", time.Since(start))
-	return movies, nil
+	return movies, meta, nil
 }
 
-func (mr *MoviesRepository) GetPopularMovies(rctx context.Context, limit, offset int) ([]models.Movie, error) {
+// PopularMoviesCachePrefix adalah prefix key redis halaman pertama movie populer,
+// dihapus setiap kali movie_popularity di-refresh
+const PopularMoviesCachePrefix = "firdaus:popular-movies:"
+
+// GetPopularMovies mengurutkan movie berdasarkan skor penjualan tiket di
+// materialized view movie_popularity, movie tanpa penjualan tidak ditampilkan
+func (mr *MoviesRepository) GetPopularMovies(rctx context.Context, req pagination.Request) ([]models.Movie, pagination.Meta, error) {
 	start := time.Now()
-	redisKey := "firdaus:popular-movies"
-	cmd := mr.rdb.Get(rctx, redisKey)
-	if cmd.Err() != nil {
-		if cmd.Err() == redis.Nil {
-			log.Printf("Key %s does not exist\n", redisKey)
-		} else {
-			log.Println("Redis Error. \nCause: ", cmd.Err().Error())
-		}
-	} else {
-		// cache hit
-		var cachedSchedules []models.Movie
-		cmdByte, err := cmd.Bytes()
-		if err != nil {
-			log.Println("Internal server error.\nCause: ", err.Error())
-		} else {
-			if err := json.Unmarshal(cmdByte, &cachedSchedules); err != nil {
-				log.Println("Internal Server Error. \nCause: ", err.Error())
-			}
-		}
-		if len(cachedSchedules) > 0 {
+	redisKey := fmt.Sprintf("%s%d", PopularMoviesCachePrefix, req.Limit)
+	if req.IsFirst() {
+		if cached, ok := mr.getCachedMoviePage(rctx, redisKey); ok {
 			log.Printf("Key %s found in cache ✅", redisKey)
 			log.Printf("Served in %s using Redis", time.Since(start))
-			return cachedSchedules, nil
+			return cached.Movies, cached.Meta, nil
 		}
 	}
-	sql := `
+
+	keyset := pagination.Keyset{Column: "p.score", Cast: "float8", IDColumn: "m.id", Desc: true}
+	conditions := []string{"p.score > 0", "m.is_deleted = false"}
+	args := []any{}
+	if where, whereArgs := keyset.Where(req.Cursor, 1); where != "" {
+		conditions = append(conditions, where)
+		args = append(args, whereArgs...)
+	}
+	sql := fmt.Sprintf(`
 SELECT 
   m.id,
   m.image,
   m.title,
-  ARRAY_AGG(g.name, ', ') AS genres
-FROM movies m
-JOIN movies_genre mg ON m.id = mg.id_movies
-JOIN genres g ON mg.id_genre = g.id
-WHERE m.rating >= 7.0
-GROUP BY m.id, m.image, m.title
-ORDER BY m.rating ASC
-LIMIT $1 OFFSET $2
-`
-	rows, err := mr.db.Query(rctx, sql, limit, offset)
+  m.rating,
+  COALESCE((
+    SELECT ARRAY_AGG(g.name ORDER BY g.name)
+    FROM movies_genre mg
+    JOIN genres g ON g.id = mg.id_genre
+    WHERE mg.id_movies = m.id
+  ), '{}') AS genres,
+  p.score,
+  p.seats_sold
+FROM movie_popularity p
+JOIN movies m ON m.id = p.id_movie
+WHERE %s
+%s
+LIMIT $%d OFFSET $%d
+`, strings.Join(conditions, " AND "), keyset.OrderBy(req.Cursor), len(args)+1, len(args)+2)
+	args = append(args, req.Limit+1, req.Offset())
+
+	rows, err := mr.db.Query(rctx, sql, args...)
 	if err != nil {
 		log.Println("Internal Server Error: ", err.Error())
-		return nil, err
+		return nil, pagination.Meta{}, err
 	}
 	defer rows.Close()
 	var movies []models.Movie
 	for rows.Next() {
 		var movie models.Movie
-		if err := rows.Scan(&movie.Id, &movie.Image, &movie.Title, &movie.Genres); err != nil {
+		if err := rows.Scan(&movie.Id, &movie.Image, &movie.Title, &movie.Rating, &movie.Genres,
+			&movie.PopularityScore, &movie.SeatsSold); err != nil {
 			log.Println("Internal Server Error: ", err.Error())
-			return nil, err
+			return nil, pagination.Meta{}, err
 		}
+		movie.SetImages()
 		movies = append(movies, movie)
 	}
+	movies, meta := pagination.Slice(movies, req, func(m models.Movie) (string, int) {
+		return strconv.FormatFloat(m.PopularityScore, 'g', -1, 64), m.Id
+	})
 	// renew cache
-	if offset == 0 {
-		bt, err := json.Marshal(movies)
-		if err != nil {
-			log.Println("Internal Server Error.\n Cause: ", err.Error())
-		}
-		if err := mr.rdb.Set(rctx, redisKey, string(bt), 1*time.Minute).Err(); err != nil {
-			log.Println("Redis Error. \nCause: ", err.Error())
-		}
+	if req.IsFirst() {
+		mr.setCachedMoviePage(rctx, redisKey, cachedMoviePage{Movies: movies, Meta: meta})
 	}
 	log.Printf("[REDIS TIMING] Served in %s using DB (cache miss)", time.Since(start))
-	return movies, nil
+	return movies, meta, nil
 }
 
-func (mr *MoviesRepository) GetDetailMovie(rctx context.Context, movieID int) ([]models.Movie, error) {
+// GetDetailMovie ikut mengambil galeri media dan satu halaman review terbaru (reviewReq)
+func (mr *MoviesRepository) GetDetailMovie(rctx context.Context, movieID int, reviewReq pagination.Request) ([]models.Movie, error) {
 	sql := `SELECT 
 	m.id,
 	m.image,
@@ -177,7 +218,11 @@ func (mr *MoviesRepository) GetDetailMovie(rctx context.Context, movieID int) ([
 	STRING_AGG(DISTINCT d.name, ', ') AS director,
 	m.synopsis,
 	ARRAY_AGG(DISTINCT g.name) AS genres,
-	ARRAY_AGG(DISTINCT a.name) AS actor
+	ARRAY_AGG(DISTINCT a.name) AS actor,
+	m.age_rating,
+	m.review_average,
+	m.review_count,
+	(SELECT COUNT(*) FROM watchlist w WHERE w.id_movie = m.id)::int4 AS watchlist_count
 	FROM movies m
 	JOIN movies_genre mg ON m.id = mg.id_movies
 	JOIN genres g ON mg.id_genre = g.id
@@ -193,7 +238,10 @@ func (mr *MoviesRepository) GetDetailMovie(rctx context.Context, movieID int) ([
 	m.title, 
 	m.release_date, 
 	m.duration, 
-	m.synopsis
+	m.synopsis,
+	m.age_rating,
+	m.review_average,
+	m.review_count
 	`
 	rows, err := mr.db.Query(rctx, sql, movieID)
 	if err != nil {
@@ -203,128 +251,251 @@ func (mr *MoviesRepository) GetDetailMovie(rctx context.Context, movieID int) ([
 	var movies []models.Movie
 	for rows.Next() {
 		var movie models.Movie
-		if err := rows.Scan(&movie.Id, &movie.Image, &movie.Backdrop, &movie.Title, &movie.ReleaseDate, &movie.Duration, &movie.Director, &movie.Synopsis, &movie.Genres, &movie.Actor); err != nil {
+		if err := rows.Scan(&movie.Id, &movie.Image, &movie.Backdrop, &movie.Title, &movie.ReleaseDate, &movie.Duration, &movie.Director, &movie.Synopsis, &movie.Genres, &movie.Actor, &movie.AgeRating, &movie.ReviewAverage, &movie.ReviewCount, &movie.WatchlistCount); err != nil {
 			log.Println("Error saat scan rows:", err)
 			return nil, err
 		}
+		movie.SetImages()
 		movies = append(movies, movie)
 	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// galeri trailer & still, lalu review terbaru
+	for i := range movies {
+		media, err := getMovieMedia(rctx, mr.db, movies[i].Id)
+		if err != nil {
+			return nil, err
+		}
+		movies[i].Media = &media
+
+		reviews, err := getMovieReviews(rctx, mr.db, movies[i].Id, reviewReq)
+		if err != nil {
+			return nil, err
+		}
+		movies[i].Reviews = &reviews
+	}
 	return movies, nil
 }
 
-func (mr *MoviesRepository) GetAllOrFilteredMovies(rctx context.Context, title string, genre []string, limit, offset int) ([]models.Movie, error) {
+// durasi movie disimpan sebagai teks ("130 min"), ambil angkanya saja
+const movieDurationSQL = `NULLIF(regexp_replace(m.duration, '[^0-9]', '', 'g'), '')::int`
+
+// kolom yang boleh dipakai untuk sorting katalog
+var movieSortColumns = map[string]struct{ column, cast string }{
+	"title":        {"m.title", "text"},
+	"release_date": {"m.release_date", "date"},
+	"rating":       {"m.rating", "float8"},
+	"popularity":   {"COALESCE(pop.score, 0)", "float8"},
+}
+
+func (mr *MoviesRepository) GetAllOrFilteredMovies(rctx context.Context, filter models.MovieFilter, req pagination.Request) ([]models.Movie, pagination.Meta, error) {
 	start := time.Now()
-	isFiltering := title != "" || len(genre) > 0
+	isCacheable := filter.IsDefault() && req.IsFirst()
 
 	// Redis cache hanya tanpa filter dan pagination
-	redisKey := "firdaus:allmovies"
-	if !isFiltering && offset == 0 {
-		cmd := mr.rdb.Get(rctx, redisKey)
-		if cmd.Err() == nil {
-			// Cache hit
-			var cachedMovies []models.Movie
-			cmdBytes, err := cmd.Bytes()
-			if err == nil {
-				if err := json.Unmarshal(cmdBytes, &cachedMovies); err == nil {
-					log.Printf("Key %s found in cache ✅", redisKey)
-					log.Printf("Served in %s using Redis", time.Since(start))
-					return cachedMovies, nil
-				}
-			}
+	redisKey := fmt.Sprintf("firdaus:allmovies:%d", req.Limit)
+	if isCacheable {
+		if cached, ok := mr.getCachedMoviePage(rctx, redisKey); ok {
+			log.Printf("Key %s found in cache ✅", redisKey)
+			log.Printf("Served in %s using Redis", time.Since(start))
+			return cached.Movies, cached.Meta, nil
 		}
 	}
 
-	baseSQL := `
-	SELECT
-    m.id,
-    m.title,
-    m.image,
-    COALESCE(ARRAY_AGG(DISTINCT g.name) FILTER (WHERE g.name IS NOT NULL)) AS genres
-FROM movies m
-INNER JOIN movies_genre mg ON m.id = mg.id_movies
-INNER JOIN genres g ON mg.id_genre = g.id`
-
 	// Dynamic conditions
-	var conditions []string
-	var args []interface{}
+	conditions := []string{"m.is_deleted = false"}
+	var args []any
 	argIdx := 1
 
 	// filter title
-	if title != "" {
-		conditions = append(conditions, fmt.Sprintf("LOWER(m.title) ILIKE LOWER($%d)", argIdx))
-		args = append(args, "%"+title+"%")
+	if filter.Title != "" {
+		conditions = append(conditions, fmt.Sprintf("m.title ILIKE $%d", argIdx))
+		args = append(args, "%"+filter.Title+"%")
 		argIdx++
 	}
-
-	if len(conditions) > 0 {
-		baseSQL += " AND " + strings.Join(conditions, " AND ")
+	// movie harus punya semua genre yang dipilih
+	if len(filter.Genres) > 0 {
+		conditions = append(conditions, fmt.Sprintf(`(
+		SELECT COUNT(DISTINCT LOWER(g.name))
+		FROM movies_genre mg
+		JOIN genres g ON g.id = mg.id_genre
+		WHERE mg.id_movies = m.id AND LOWER(g.name) = ANY($%d::text[])
+	) = $%d`, argIdx, argIdx+1))
+		args = append(args, filter.Genres, len(filter.Genres))
+		argIdx += 2
+	}
+	if !filter.ReleaseFrom.IsZero() {
+		conditions = append(conditions, fmt.Sprintf("m.release_date >= $%d", argIdx))
+		args = append(args, filter.ReleaseFrom)
+		argIdx++
 	}
-
-	// Tambahkan join dengan unnest genre jika ada
-	if len(genre) > 0 {
-		baseSQL += `
-	LEFT JOIN (
-		SELECT unnest($` + fmt.Sprint(argIdx) + `::text[]) AS genre_name
-	) AS selected_genres ON LOWER(g.name) = LOWER(selected_genres.genre_name)
-	`
-		args = append(args, genre)
+	if !filter.ReleaseTo.IsZero() {
+		conditions = append(conditions, fmt.Sprintf("m.release_date <= $%d", argIdx))
+		args = append(args, filter.ReleaseTo)
+		argIdx++
+	}
+	if filter.RatingMin != nil {
+		conditions = append(conditions, fmt.Sprintf("m.rating >= $%d", argIdx))
+		args = append(args, *filter.RatingMin)
+		argIdx++
+	}
+	if filter.RatingMax != nil {
+		conditions = append(conditions, fmt.Sprintf("m.rating <= $%d", argIdx))
+		args = append(args, *filter.RatingMax)
+		argIdx++
+	}
+	if filter.DurationMin != nil {
+		conditions = append(conditions, fmt.Sprintf("%s >= $%d", movieDurationSQL, argIdx))
+		args = append(args, *filter.DurationMin)
+		argIdx++
+	}
+	if filter.DurationMax != nil {
+		conditions = append(conditions, fmt.Sprintf("%s <= $%d", movieDurationSQL, argIdx))
+		args = append(args, *filter.DurationMax)
+		argIdx++
+	}
+	if filter.Director != nil {
+		conditions = append(conditions, fmt.Sprintf("m.id_director = $%d", argIdx))
+		args = append(args, *filter.Director)
+		argIdx++
+	}
+	if len(filter.AgeRatings) > 0 {
+		conditions = append(conditions, fmt.Sprintf("m.age_rating = ANY($%d::text[])", argIdx))
+		args = append(args, filter.AgeRatings)
+		argIdx++
+	}
+	if len(filter.Actors) > 0 {
+		conditions = append(conditions, fmt.Sprintf(
+			"EXISTS (SELECT 1 FROM movies_actor ma WHERE ma.id_movie = m.id AND ma.id_actor = ANY($%d::int[]))", argIdx))
+		args = append(args, filter.Actors)
 		argIdx++
 	}
-	// Akhir query
-	baseSQL += `
-GROUP BY m.id, m.title, m.image`
 
-	if len(genre) > 0 {
-		baseSQL += fmt.Sprintf(`
-HAVING COUNT(DISTINCT selected_genres.genre_name) = $%d
-`, argIdx)
-		args = append(args, len(genre))
+	// filter cinema, location dan tanggal tayang harus cocok di schedule yang sama
+	// yang belum mulai
+	var scheduleConds []string
+	if filter.Cinema != nil {
+		scheduleConds = append(scheduleConds, fmt.Sprintf("c.id = $%d", argIdx))
+		args = append(args, *filter.Cinema)
+		argIdx++
+	}
+	if filter.Location != nil {
+		scheduleConds = append(scheduleConds, fmt.Sprintf("c.id_location = $%d", argIdx))
+		args = append(args, *filter.Location)
 		argIdx++
 	}
+	if !filter.ShowingOn.IsZero() {
+		scheduleConds = append(scheduleConds, fmt.Sprintf(localStartSQL+"::date = $%d", argIdx))
+		args = append(args, filter.ShowingOn)
+		argIdx++
+	}
+	if len(scheduleConds) > 0 {
+		conditions = append(conditions, fmt.Sprintf(
+			`EXISTS (
+		SELECT 1
+		FROM schedule s
+		JOIN studio st ON st.id = s.id_studio
+		JOIN cinema c ON c.id = st.id_cinema
+		WHERE s.id_movie = m.id AND s.cancelled_at IS NULL AND s.start_at > now() AND %s
+	)`, strings.Join(scheduleConds, " AND ")))
+	}
+
+	sort, ok := movieSortColumns[filter.Sort]
+	if !ok {
+		sort = movieSortColumns["title"]
+	}
+	keyset := pagination.Keyset{Column: sort.column, Cast: sort.cast, IDColumn: "m.id", Desc: filter.Order == "desc"}
+
+	// total dihitung tanpa kondisi cursor
+	var total int
+	countSQL := "SELECT COUNT(*) FROM movies m WHERE " + strings.Join(conditions, " AND ")
+	if err := mr.db.QueryRow(rctx, countSQL, args...).Scan(&total); err != nil {
+		log.Println("Internal Server Error: ", err.Error())
+		return nil, pagination.Meta{}, err
+	}
+
+	if where, whereArgs := keyset.Where(req.Cursor, argIdx); where != "" {
+		conditions = append(conditions, where)
+		args = append(args, whereArgs...)
+		argIdx += len(whereArgs)
+	}
+
+	baseSQL := fmt.Sprintf(`
+SELECT
+    m.id,
+    m.title,
+    m.image,
+    m.release_date,
+    m.rating,
+    m.duration,
+    m.age_rating,
+    COALESCE((
+        SELECT ARRAY_AGG(g.name ORDER BY g.name)
+        FROM movies_genre mg
+        JOIN genres g ON g.id = mg.id_genre
+        WHERE mg.id_movies = m.id
+    ), '{}') AS genres,
+    (%s)::text AS sort_key
+FROM movies m`, sort.column)
+	if filter.Sort == "popularity" {
+		baseSQL += `
+LEFT JOIN movie_popularity pop ON pop.id_movie = m.id`
+	}
+	baseSQL += `
+WHERE ` + strings.Join(conditions, "\n  AND ")
 
 	// Order, limit, offset
 	baseSQL += fmt.Sprintf(`
-ORDER BY m.title ASC
+%s
 LIMIT $%d OFFSET $%d
-`, argIdx, argIdx+1)
-	args = append(args, limit, offset)
+`, keyset.OrderBy(req.Cursor), argIdx, argIdx+1)
+	args = append(args, req.Limit+1, req.Offset())
 
 	// Query
 	rows, err := mr.db.Query(rctx, baseSQL, args...)
 	if err != nil {
 		log.Println("Internal Server Error: ", err.Error())
-		return nil, err
+		return nil, pagination.Meta{}, err
 	}
 	defer rows.Close()
 
 	var movies []models.Movie
+	sortKeys := map[int]string{}
 	for rows.Next() {
 		var movie models.Movie
-		if err := rows.Scan(&movie.Id, &movie.Title, &movie.Image, &movie.Genres); err != nil {
+		var sortKey string
+		if err := rows.Scan(&movie.Id, &movie.Title, &movie.Image, &movie.ReleaseDate, &movie.Rating, &movie.Duration, &movie.AgeRating, &movie.Genres, &sortKey); err != nil {
 			log.Println("Internal Server Error: ", err.Error())
-			return nil, err
+			return nil, pagination.Meta{}, err
 		}
+		sortKeys[movie.Id] = sortKey
+		movie.SetImages()
 		movies = append(movies, movie)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, pagination.Meta{}, err
+	}
+	movies, meta := pagination.Slice(movies, req, func(m models.Movie) (string, int) {
+		return sortKeys[m.Id], m.Id
+	})
+	meta = meta.WithTotal(total)
 
 	// Cache jika tidak filter
-	if !isFiltering && offset == 0 {
-		bt, err := json.Marshal(movies)
-		if err == nil {
-			if err := mr.rdb.Set(rctx, redisKey, string(bt), 1*time.Minute).Err(); err != nil {
-				log.Println("Redis Set Error:", err.Error())
-			}
-		}
+	if isCacheable {
+		mr.setCachedMoviePage(rctx, redisKey, cachedMoviePage{Movies: movies, Meta: meta})
 	}
 
 	log.Printf("[MOVIES] Served in %s using %s", time.Since(start), func() string {
-		if !isFiltering && offset == 0 {
+		if isCacheable {
 			return "Redis or DB (cached)"
 		}
 		return "DB (filtered)"
 	}())
 
-	return movies, nil
+	return movies, meta, nil
 }
 
 func (mr *MoviesRepository) DeleteMovie(ctx context.Context, id int) error {
@@ -346,7 +517,7 @@ func (mr *MoviesRepository) DeleteMovie(ctx context.Context, id int) error {
 	log.Printf("Rows affected: %d", rows)
 
 	if rows == 0 {
-		return fmt.Errorf("movie with id %d not found", id)
+		return apperror.NotFound(apperror.CodeMovieNotFound, "")
 	}
 
 	log.Printf("movie with id %d successfully deleted", id)
@@ -354,7 +525,7 @@ func (mr *MoviesRepository) DeleteMovie(ctx context.Context, id int) error {
 }
 
 func (r *MoviesRepository) EditMovie(ctx context.Context, body models.MovieBody, image *string,
-	backdrop *string) (models.Movie, error) {
+	backdrop *string, beforeCommit BeforeCommit) (models.Movie, error) {
 	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{})
 	if err != nil {
 		return models.Movie{}, err
@@ -366,13 +537,13 @@ func (r *MoviesRepository) EditMovie(ctx context.Context, body models.MovieBody,
 	args := []any{}
 	argID := 1
 
-	if body.Image != nil {
+	if image != nil {
 		// Simpan file dan dapatkan pathnya dulu di layer service/controller
 		setClauses = append(setClauses, fmt.Sprintf("image = $%d", argID))
 		args = append(args, *image)
 		argID++
 	}
-	if body.Backdrop != nil {
+	if backdrop != nil {
 		setClauses = append(setClauses, fmt.Sprintf("backdrop = $%d", argID))
 		args = append(args, *backdrop)
 		argID++
@@ -407,16 +578,21 @@ func (r *MoviesRepository) EditMovie(ctx context.Context, body models.MovieBody,
 		args = append(args, body.Rating)
 		argID++
 	}
+	if body.AgeRating != "" {
+		setClauses = append(setClauses, fmt.Sprintf("age_rating = $%d", argID))
+		args = append(args, body.AgeRating)
+		argID++
+	}
 
 	if len(setClauses) == 0 {
-		return models.Movie{}, fmt.Errorf("no fields to update")
+		return models.Movie{}, apperror.Validation(apperror.CodeNoFieldsToUpdate, "")
 	}
 
 	query := fmt.Sprintf(`
         UPDATE movies
         SET %s
         WHERE id = $%d
-        RETURNING id, image, backdrop, title, release_date, duration, id_director, synopsis, rating
+        RETURNING id, image, backdrop, title, release_date, duration, id_director, synopsis, rating, age_rating
     `, strings.Join(setClauses, ", "), argID)
 
 	args = append(args, body.Id)
@@ -432,9 +608,13 @@ func (r *MoviesRepository) EditMovie(ctx context.Context, body models.MovieBody,
 		&movie.Director,
 		&movie.Synopsis,
 		&movie.Rating,
+		&movie.AgeRating,
 	)
 	if err != nil {
 		tx.Rollback(ctx)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.Movie{}, apperror.NotFound(apperror.CodeMovieNotFound, "")
+		}
 		return models.Movie{}, err
 	}
 
@@ -466,15 +646,20 @@ func (r *MoviesRepository) EditMovie(ctx context.Context, body models.MovieBody,
 		}
 	}
 
+	if err := runBeforeCommit(ctx, beforeCommit); err != nil {
+		return models.Movie{}, err
+	}
+
 	err = tx.Commit(ctx)
 	if err != nil {
 		return models.Movie{}, err
 	}
 
+	movie.SetImages()
 	return movie, nil
 }
 
-func (mr *MoviesRepository) CreateMovie(rctx context.Context, body models.MovieBody) (models.MovieBody, error) {
+func (mr *MoviesRepository) CreateMovie(rctx context.Context, body models.MovieBody, beforeCommit BeforeCommit) (models.MovieBody, error) {
 	tx, err := mr.db.Begin(rctx)
 	if err != nil {
 		log.Println("Failed to begin transaction:", err)
@@ -483,11 +668,11 @@ func (mr *MoviesRepository) CreateMovie(rctx context.Context, body models.MovieB
 
 	defer tx.Rollback(rctx)
 	// Insert ke tabel movies
-	sql := `INSERT INTO movies (title, release_date, duration, synopsis, id_director, rating, image, backdrop)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-		RETURNING id, title, release_date, duration, synopsis, id_director, rating, image, backdrop`
+	sql := `INSERT INTO movies (title, release_date, duration, synopsis, id_director, rating, image, backdrop, age_rating)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, COALESCE(NULLIF($9, ''), 'SU'))
+		RETURNING id, title, release_date, duration, synopsis, id_director, rating, image, backdrop, age_rating`
 	values := []any{body.Title, body.ReleaseDate, body.Duration, body.Synopsis, body.Director, body.Rating,
-		body.Imagestr, body.Backdropstr}
+		body.Imagestr, body.Backdropstr, body.AgeRating}
 	var newMovie models.MovieBody
 	if err := tx.QueryRow(rctx, sql, values...).Scan(
 		&newMovie.Id,
@@ -499,6 +684,7 @@ func (mr *MoviesRepository) CreateMovie(rctx context.Context, body models.MovieB
 		&newMovie.Rating,
 		&newMovie.Imagestr,
 		&newMovie.Backdropstr,
+		&newMovie.AgeRating,
 	); err != nil {
 		log.Println("Failed to insert movie:", err)
 		return models.MovieBody{}, err
@@ -522,28 +708,27 @@ func (mr *MoviesRepository) CreateMovie(rctx context.Context, body models.MovieB
 		}
 
 	}
+	// jadwal awal divalidasi sama seperti CreateSchedule
+	var slots []models.ScheduleSlot
 	for _, bs := range body.Schedules {
-		for i := 0; i < len(bs.Date); i++ {
-			date := bs.Date[i]
-			idCinema := bs.IdCinema[i]
-			idTime := bs.IdTime[i]
-			idLocation := bs.IdLocation[i]
-
-			scheduleSQL := `INSERT INTO schedule (id_movie, date, id_cinema, id_time, id_location)
-            VALUES ($1, $2, $3, $4, $5)`
-			if _, err := tx.Exec(rctx, scheduleSQL,
-				newMovie.Id,
-				date,
-				idCinema,
-				idTime,
-				idLocation,
-			); err != nil {
-				log.Println("Failed to insert schedule:", err)
-				return models.MovieBody{}, err
-			}
+		for i := range bs.Date {
+			slots = append(slots, models.ScheduleSlot{Date: bs.Date[i], StudioID: bs.IdStudio[i], StartTime: bs.StartTime[i]})
+		}
+	}
+	if len(slots) > 0 {
+		planned, err := planSchedules(rctx, tx, newMovie.Id, slots)
+		if err != nil {
+			return models.MovieBody{}, err
+		}
+		if _, err := insertSchedules(rctx, tx, newMovie.Id, nil, planned); err != nil {
+			return models.MovieBody{}, err
 		}
 	}
 
+	if err := runBeforeCommit(rctx, beforeCommit); err != nil {
+		return models.MovieBody{}, err
+	}
+
 	// Commit transaksi
 	if err := tx.Commit(rctx); err != nil {
 		log.Println("Failed to commit transaction:", err)
@@ -553,41 +738,55 @@ func (mr *MoviesRepository) CreateMovie(rctx context.Context, body models.MovieB
 	return newMovie, nil
 }
 
-func (mr *MoviesRepository) GetMovieAdmin(rctx context.Context, limit, offset int) ([]models.MovieAdmin, error) {
-	sql := `SELECT 
+func (mr *MoviesRepository) GetMovieAdmin(rctx context.Context, req pagination.Request) ([]models.MovieAdmin, pagination.Meta, error) {
+	keyset := pagination.Keyset{IDColumn: "m.id"}
+	conditions := []string{"m.is_deleted = false"}
+	args := []any{}
+	if where, whereArgs := keyset.Where(req.Cursor, 1); where != "" {
+		conditions = append(conditions, where)
+		args = append(args, whereArgs...)
+	}
+	sql := fmt.Sprintf(`SELECT 
 		m.id,
 		m.image,
 		m.title,
 		m.release_date,
 		m.duration,
+		m.age_rating,
 		STRING_AGG(DISTINCT g.name, ', ') AS genres
 		FROM movies m
 		JOIN movies_genre mg ON m.id = mg.id_movies
 		JOIN genres g ON mg.id_genre = g.id
-		WHERE m.is_deleted = false
+		WHERE %s
 		GROUP BY 
 			m.id, 
 			m.image, 
 			m.title, 
 			m.release_date, 
-			m.duration
-		ORDER BY m.id
-		LIMIT $1 OFFSET $2;`
-	rows, err := mr.db.Query(rctx, sql, limit, offset)
+			m.duration,
+			m.age_rating
+		%s
+		LIMIT $%d OFFSET $%d;`, strings.Join(conditions, " AND "), keyset.OrderBy(req.Cursor), len(args)+1, len(args)+2)
+	args = append(args, req.Limit+1, req.Offset())
+	rows, err := mr.db.Query(rctx, sql, args...)
 	if err != nil {
-		return nil, err
+		return nil, pagination.Meta{}, err
 	}
 	defer rows.Close()
 	var movies []models.MovieAdmin
 	for rows.Next() {
 		var movie models.MovieAdmin
-		if err := rows.Scan(&movie.Id, &movie.Image, &movie.Title, &movie.ReleaseDate, &movie.Duration, &movie.Genres); err != nil {
+		if err := rows.Scan(&movie.Id, &movie.Image, &movie.Title, &movie.ReleaseDate, &movie.Duration, &movie.AgeRating, &movie.Genres); err != nil {
 			log.Println("Error saat scan rows", err)
-			return nil, err
+			return nil, pagination.Meta{}, err
 		}
+		movie.SetImages()
 		movies = append(movies, movie)
 	}
-	return movies, nil
+	movies, meta := pagination.Slice(movies, req, func(m models.MovieAdmin) (string, int) {
+		return "", m.Id
+	})
+	return movies, meta, nil
 }
 
 func (sr *MoviesRepository) GetMoviesByAllGenres(ctx context.Context, genreIDs []string) ([]models.Movie, error) {
@@ -0,0 +1,174 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/federus1105/weekly/internals/models"
+	"github.com/federus1105/weekly/pkg/pagination"
+)
+
+// nowShowingConds adalah syarat schedule yang masih bisa ditonton beserta filter,
+// memakai alias s, st dan c
+func nowShowingConds(filter models.NowShowingFilter, argIdx int) ([]string, []any) {
+	conds := []string{"s.cancelled_at IS NULL", "s.start_at > now()"}
+	var args []any
+	if filter.CinemaID != nil {
+		conds = append(conds, fmt.Sprintf("c.id = $%d", argIdx))
+		args = append(args, *filter.CinemaID)
+		argIdx++
+	}
+	if filter.LocationID != nil {
+		conds = append(conds, fmt.Sprintf("c.id_location = $%d", argIdx))
+		args = append(args, *filter.LocationID)
+		argIdx++
+	}
+	if filter.Date != "" {
+		conds = append(conds, fmt.Sprintf(localDateSQL+" = $%d", argIdx))
+		args = append(args, filter.Date)
+	}
+	return conds, args
+}
+
+// GetNowShowingMovies mengambil movie yang punya jadwal mendatang sesuai filter,
+// diurutkan berdasarkan skor popularitas, tiap movie membawa beberapa jadwal terdekat
+func (mr *MoviesRepository) GetNowShowingMovies(rctx context.Context, filter models.NowShowingFilter, req pagination.Request) ([]models.Movie, pagination.Meta, error) {
+	start := time.Now()
+	redisKey := fmt.Sprintf("firdaus:now-showing:%d", req.Limit)
+	cacheable := req.IsFirst() && filter.IsDefault()
+	if cacheable {
+		if cached, ok := mr.getCachedMoviePage(rctx, redisKey); ok {
+			log.Printf("Key %s found in cache ✅", redisKey)
+			log.Printf("Served in %s using Redis", time.Since(start))
+			return cached.Movies, cached.Meta, nil
+		}
+	}
+
+	scheduleConds, args := nowShowingConds(filter, 1)
+	conditions := []string{
+		"m.is_deleted = false",
+		fmt.Sprintf(`EXISTS (
+		SELECT 1
+		FROM schedule s
+		JOIN studio st ON st.id = s.id_studio
+		JOIN cinema c ON c.id = st.id_cinema
+		WHERE s.id_movie = m.id AND %s
+	)`, strings.Join(scheduleConds, " AND ")),
+	}
+	keyset := pagination.Keyset{Column: "COALESCE(p.score, 0)", Cast: "float8", IDColumn: "m.id", Desc: true}
+	if where, whereArgs := keyset.Where(req.Cursor, len(args)+1); where != "" {
+		conditions = append(conditions, where)
+		args = append(args, whereArgs...)
+	}
+	sql := fmt.Sprintf(`
+SELECT
+  m.id,
+  m.image,
+  m.title,
+  m.rating,
+  m.age_rating,
+  m.duration,
+  COALESCE((
+    SELECT ARRAY_AGG(g.name ORDER BY g.name)
+    FROM movies_genre mg
+    JOIN genres g ON g.id = mg.id_genre
+    WHERE mg.id_movies = m.id
+  ), '{}') AS genres,
+  COALESCE(p.score, 0)::float8
+FROM movies m
+LEFT JOIN movie_popularity p ON p.id_movie = m.id
+WHERE %s
+%s
+LIMIT $%d OFFSET $%d
+`, strings.Join(conditions, " AND "), keyset.OrderBy(req.Cursor), len(args)+1, len(args)+2)
+	args = append(args, req.Limit+1, req.Offset())
+
+	rows, err := mr.db.Query(rctx, sql, args...)
+	if err != nil {
+		log.Println("Internal Server Error: ", err.Error())
+		return nil, pagination.Meta{}, err
+	}
+	defer rows.Close()
+	var movies []models.Movie
+	for rows.Next() {
+		var movie models.Movie
+		if err := rows.Scan(&movie.Id, &movie.Image, &movie.Title, &movie.Rating, &movie.AgeRating,
+			&movie.Duration, &movie.Genres, &movie.PopularityScore); err != nil {
+			log.Println("Internal Server Error: ", err.Error())
+			return nil, pagination.Meta{}, err
+		}
+		movie.SetImages()
+		movies = append(movies, movie)
+	}
+	if err := rows.Err(); err != nil {
+		log.Println("Internal Server Error: ", err.Error())
+		return nil, pagination.Meta{}, err
+	}
+	movies, meta := pagination.Slice(movies, req, func(m models.Movie) (string, int) {
+		return strconv.FormatFloat(m.PopularityScore, 'g', -1, 64), m.Id
+	})
+
+	if err := mr.attachShowtimes(rctx, movies, filter); err != nil {
+		log.Println("Internal Server Error: ", err.Error())
+		return nil, pagination.Meta{}, err
+	}
+	// renew cache
+	if cacheable {
+		mr.setCachedMoviePage(rctx, redisKey, cachedMoviePage{Movies: movies, Meta: meta})
+	}
+	log.Printf("[REDIS TIMING] Served in %s using DB (cache miss)", time.Since(start))
+	return movies, meta, nil
+}
+
+// attachShowtimes mengisi jadwal terdekat tiap movie dengan filter yang sama
+func (mr *MoviesRepository) attachShowtimes(rctx context.Context, movies []models.Movie, filter models.NowShowingFilter) error {
+	if len(movies) == 0 {
+		return nil
+	}
+	movieIDs := make([]int, len(movies))
+	index := make(map[int]int, len(movies))
+	for i, m := range movies {
+		movieIDs[i] = m.Id
+		index[m.Id] = i
+	}
+
+	scheduleConds, args := nowShowingConds(filter, 3)
+	sql := fmt.Sprintf(`
+SELECT x.id_movie, x.id, x.id_cinema, x.cinema, x.id_studio, x.studio, x.start_at, x.end_at, x.time_zone
+FROM (
+  SELECT s.id_movie, s.id, c.id AS id_cinema, c.name AS cinema, st.id AS id_studio, st.name AS studio,
+    s.start_at, s.end_at, c.time_zone,
+    row_number() OVER (PARTITION BY s.id_movie ORDER BY s.start_at, s.id) AS rn
+  FROM schedule s
+  JOIN studio st ON st.id = s.id_studio
+  JOIN cinema c ON c.id = st.id_cinema
+  WHERE s.id_movie = ANY($1::int[]) AND %s
+) x
+WHERE x.rn <= $2
+ORDER BY x.id_movie, x.start_at, x.id
+`, strings.Join(scheduleConds, " AND "))
+	args = append([]any{movieIDs, models.NowShowingShowtimes}, args...)
+
+	rows, err := mr.db.Query(rctx, sql, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var movieID int
+		var st models.Showtime
+		if err := rows.Scan(&movieID, &st.ScheduleID, &st.CinemaID, &st.Cinema, &st.StudioID, &st.Studio,
+			&st.StartAt, &st.EndAt, &st.TimeZone); err != nil {
+			return err
+		}
+		loc := models.TimeZone(st.TimeZone)
+		st.StartAt, st.EndAt = st.StartAt.In(loc), st.EndAt.In(loc)
+		i := index[movieID]
+		movies[i].Showtimes = append(movies[i].Showtimes, st)
+	}
+	return rows.Err()
+}
@@ -2,32 +2,26 @@ package repositories
 
 import (
 	"context"
-	"fmt"
+	"errors"
 	"log"
+	"time"
 
 	"github.com/federus1105/weekly/internals/models"
+	"github.com/federus1105/weekly/pkg/apperror"
+	"github.com/federus1105/weekly/pkg/pricing"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type OrderRepository struct {
-	db *pgxpool.Pool
+	db      *pgxpool.Pool
+	charges pricing.Charges
 }
 
-func NewOrderRepository(db *pgxpool.Pool) *OrderRepository {
-	return &OrderRepository{db: db}
+func NewOrderRepository(db *pgxpool.Pool, charges pricing.Charges) *OrderRepository {
+	return &OrderRepository{db: db, charges: charges}
 }
 
-//	func (or *OrderRepository) CreateOrder(rctx context.Context, body models.Order) (models.Order, error) {
-//		sql := `INSERT INTO orders (id_schedule, id_user, id_payment_method, total, fullname, email, phone_number, paid)
-//		VALUES ($1,$2,$3,$4,$5,$6,$7,$8)RETURNING id, id_schedule, id_user, id_payment_method, total, fullname, email, phone_number, paid;`
-//		values := []any{body.Schedule, body.User, body.Payment, body.Total, body.Fullname, body.Email, body.Phone, body.Paid}
-//		var newOrder models.Order
-//		if err := or.db.QueryRow(rctx, sql, values...).Scan(&newOrder.Id, &newOrder.Schedule, &newOrder.User, &newOrder.Payment, &newOrder.Total, &newOrder.Fullname, &newOrder.Email, &newOrder.Phone, &newOrder.Paid); err != nil {
-//			log.Println("Internal Server Error: ", err.Error())
-//			return models.Order{}, err
-//		}
-//		return newOrder, nil
-//	}
 func (or *OrderRepository) CreateOrder(
 	rctx context.Context,
 	body models.Order,
@@ -41,43 +35,52 @@ func (or *OrderRepository) CreateOrder(
 	}
 	defer tx.Rollback(rctx)
 
-	// defer func() {
-	// 	if err != nil {
-	// 		_ = tx.Rollback(rctx)
-	// 	}
-	// }()
-
-	// ✅ Ambil harga cinema berdasarkan schedule
-	var price int
-	sqlPrice := `
-		SELECT c.price
-		FROM schedule s
-		JOIN cinema c ON s.id_cinema = c.id
-		WHERE s.id = $1;
-	`
-	err = tx.QueryRow(rctx, sqlPrice, body.Schedule).Scan(&price)
+	// ✅ Tandai kursi terisi lebih dulu, checkout bersamaan untuk kursi yang sama
+	// menunggu row lock lalu gagal karena kursi sudah tidak tersedia
+	for _, seatID := range seatIDs {
+		if err = reserveSeat(rctx, tx, seatID); err != nil {
+			log.Println("Failed to reserve seat:", err)
+			return
+		}
+	}
+
+	// ✅ Validasi pembeli & hitung harga tiap kursi dari aturan harga,
+	// schedule dikunci supaya tidak dibatalkan selama checkout
+	quote, err := prepareOrderPricing(rctx, tx, or.charges, orderPricing{
+		scheduleID: body.Schedule,
+		userID:     body.User,
+		paymentID:  body.Payment,
+		seatIDs:    seatIDs,
+		category:   body.Category,
+		promoCode:  body.PromoCode,
+		redeem:     true,
+	})
 	if err != nil {
-		log.Println("Failed to get cinema price:", err)
+		log.Println("Failed to price order:", err)
 		return
 	}
-	
 
-	// ✅ Hitung total otomatis
-	body.Total = float32(price * len(seatIDs))
+	// ✅ Rincian harga sama dengan quote
+	body.Total = quote.Total
+	body.Category = quote.Category
 
 	// Step 1: Insert ke orders
 	sqlOrder := `INSERT INTO orders 
-	(id_schedule, id_user, id_payment_method, total, fullname, email, phone_number, paid) 
-	VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-	RETURNING id, id_schedule, id_user, id_payment_method, total, fullname, email, phone_number, paid;`
+	(id_schedule, id_user, id_payment_method, total, fullname, email, phone_number, paid, customer_category,
+	 subtotal, fee_total, tax_total, tax_rate) 
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	RETURNING id, id_schedule, id_user, id_payment_method, total, fullname, email, phone_number, paid, customer_category,
+	 subtotal, fee_total, tax_total, tax_rate;`
 
 	err = tx.QueryRow(rctx, sqlOrder,
 		body.Schedule, body.User, body.Payment, body.Total,
-		body.Fullname, body.Email, body.Phone, body.Paid,
+		body.Fullname, body.Email, body.Phone, body.Paid, body.Category,
+		quote.Subtotal, quote.Fees, quote.Tax, quote.TaxRate,
 	).Scan(
 		&newOrder.Id, &newOrder.Schedule, &newOrder.User,
 		&newOrder.Payment, &newOrder.Total, &newOrder.Fullname,
-		&newOrder.Email, &newOrder.Phone, &newOrder.Paid,
+		&newOrder.Email, &newOrder.Phone, &newOrder.Paid, &newOrder.Category,
+		&newOrder.Subtotal, &newOrder.Fees, &newOrder.Tax, &newOrder.TaxRate,
 	)
 
 	if err != nil {
@@ -85,33 +88,24 @@ func (or *OrderRepository) CreateOrder(
 		return
 	}
 
-	for _, seatID := range seatIDs {
-		// Validasi kursi belum diambil
-		var isAvailable bool
-		sqlCheck := `SELECT isstatus FROM seats WHERE id = $1`
-		err = tx.QueryRow(rctx, sqlCheck, seatID).Scan(&isAvailable)
-		if err != nil {
-			log.Println("Failed to check seat status:", err)
-			return
-		}
-
-		if !isAvailable {
-			err = fmt.Errorf("seat with ID %d is already booked", seatID)
-			log.Println(err)
-			return
-		}
-
-		// Insert ke order_seat
-		sqlSeat := `INSERT INTO order_seat (id_order, id_seats) VALUES ($1, $2);`
-		if _, err = tx.Exec(rctx, sqlSeat, newOrder.Id, seatID); err != nil {
+	// urutan item quote sama dengan seatIDs
+	for _, item := range quote.Items {
+		// Insert ke order_seat beserta rincian harganya
+		sqlSeat := `INSERT INTO order_seat
+		(id_order, id_seats, seat_code, seat_type, base_price, adjustments, subtotal, tax, total)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);`
+		adjustments := lineAdjustments{Modifiers: item.Modifiers, Discounts: item.Discounts, Fees: item.Fees}
+		if _, err = tx.Exec(rctx, sqlSeat, newOrder.Id, item.SeatID, item.Seat, item.SeatType,
+			item.BasePrice, adjustments, item.Subtotal, item.Tax, item.Total); err != nil {
 			log.Println("Failed to insert order_seat:", err)
 			return
 		}
+	}
 
-		// Update status kursi
-		sqlUpdate := `UPDATE seats SET isstatus = false WHERE id = $1;`
-		if _, err = tx.Exec(rctx, sqlUpdate, seatID); err != nil {
-			log.Println("Failed to update seat status:", err)
+	// ✅ Catat pemakaian promo di transaksi yang sama dengan pengecekan kuotanya
+	if quote.Promo != nil {
+		if err = redeemPromo(rctx, tx, *quote.Promo, newOrder.Id, body.User); err != nil {
+			log.Println("Failed to redeem promo:", err)
 			return
 		}
 	}
@@ -122,5 +116,129 @@ func (or *OrderRepository) CreateOrder(
 		return
 	}
 
+	newOrder.Items = quote.Items
+	newOrder.Promo = quote.Promo
 	return newOrder, nil
 }
+
+// reserveSeat menandai kursi terisi hanya jika masih tersedia. UPDATE bersyarat
+// mengambil row lock sehingga dua checkout tidak bisa sama-sama mendapat kursi yang sama.
+func reserveSeat(rctx context.Context, tx pgx.Tx, seatID int) error {
+	var id int
+	err := tx.QueryRow(rctx, `UPDATE seats SET isstatus = false WHERE id = $1 AND isstatus RETURNING id`, seatID).Scan(&id)
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return err
+	}
+	var exists bool
+	if err := tx.QueryRow(rctx, `SELECT EXISTS (SELECT 1 FROM seats WHERE id = $1)`, seatID).Scan(&exists); err != nil {
+		return err
+	}
+	details := map[string]any{"seat_id": seatID}
+	if !exists {
+		return apperror.NotFound(apperror.CodeSeatNotFound, "").WithDetails(details)
+	}
+	return apperror.Conflict(apperror.CodeSeatUnavailable, "").WithDetails(details)
+}
+
+// lineAdjustments adalah isi kolom order_seat.adjustments
+type lineAdjustments struct {
+	Modifiers []models.PriceAdjustment `json:"modifiers"`
+	Discounts []models.PriceAdjustment `json:"discounts"`
+	Fees      []models.Fee             `json:"fees"`
+}
+
+// GetOrder mengambil order milik user beserta rincian harga yang disimpan saat order dibuat.
+// Order lama tanpa rincian hanya berisi total.
+func (or *OrderRepository) GetOrder(rctx context.Context, userID, orderID int) (models.Order, error) {
+	var o models.Order
+	var promoID *int
+	var promo models.AppliedPromo
+	err := or.db.QueryRow(rctx, `
+		SELECT o.id, o.id_schedule, o.id_user, COALESCE(o.id_payment_method, 0), COALESCE(o.total, 0), o.fullname,
+			o.email, o.phone_number, o.paid, o.customer_category, COALESCE(o.subtotal, 0), COALESCE(o.fee_total, 0),
+			COALESCE(o.tax_total, 0), COALESCE(o.tax_rate, 0), pr.id_promo, COALESCE(pc.code, ''),
+			COALESCE(pr.discount, 0)
+		FROM orders o
+		LEFT JOIN promo_redemptions pr ON pr.id_order = o.id
+		LEFT JOIN promo_codes pc ON pc.id = pr.id_promo
+		WHERE o.id = $1 AND o.id_user = $2`, orderID, userID).
+		Scan(&o.Id, &o.Schedule, &o.User, &o.Payment, &o.Total, &o.Fullname, &o.Email, &o.Phone, &o.Paid,
+			&o.Category, &o.Subtotal, &o.Fees, &o.Tax, &o.TaxRate, &promoID, &promo.Code, &promo.Discount)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return o, apperror.NotFound(apperror.CodeOrderNotFound, "")
+	}
+	if err != nil {
+		return o, err
+	}
+	if promoID != nil {
+		promo.Id = *promoID
+		o.Promo = &promo
+	}
+
+	rows, err := or.db.Query(rctx, `
+		SELECT os.id_seats, COALESCE(os.seat_code, s.codeseat), COALESCE(os.seat_type, s.seat_type),
+			os.total IS NOT NULL, COALESCE(os.base_price, 0), os.adjustments, COALESCE(os.subtotal, 0),
+			COALESCE(os.tax, 0), COALESCE(os.total, 0)
+		FROM order_seat os
+		JOIN seats s ON s.id = os.id_seats
+		WHERE os.id_order = $1
+		ORDER BY os.id_seats ASC`, orderID)
+	if err != nil {
+		return o, err
+	}
+	defer rows.Close()
+
+	o.Seats = []int{}
+	for rows.Next() {
+		var item models.LineItem
+		var priced bool
+		var adjustments lineAdjustments
+		if err := rows.Scan(&item.SeatID, &item.Seat, &item.SeatType, &priced, &item.BasePrice, &adjustments,
+			&item.Subtotal, &item.Tax, &item.Total); err != nil {
+			return o, err
+		}
+		o.Seats = append(o.Seats, item.SeatID)
+		// order lama belum menyimpan rincian per kursi
+		if !priced {
+			continue
+		}
+		item.Modifiers = append([]models.PriceAdjustment{}, adjustments.Modifiers...)
+		item.Discounts = append([]models.PriceAdjustment{}, adjustments.Discounts...)
+		item.Fees = append([]models.Fee{}, adjustments.Fees...)
+		o.Items = append(o.Items, item)
+	}
+	return o, rows.Err()
+}
+
+// QuoteOrder menghitung harga order tanpa menyimpan apa pun, validasinya sama dengan CreateOrder
+func (or *OrderRepository) QuoteOrder(rctx context.Context, userID int, body models.QuoteBody) (models.Quote, error) {
+	tx, err := or.db.Begin(rctx)
+	if err != nil {
+		return models.Quote{}, err
+	}
+	defer tx.Rollback(rctx)
+	return prepareOrderPricing(rctx, tx, or.charges, orderPricing{
+		scheduleID: body.Schedule,
+		userID:     userID,
+		paymentID:  body.Payment,
+		seatIDs:    body.Seats,
+		category:   body.Category,
+		promoCode:  body.PromoCode,
+	})
+}
+
+// checkBuyerAge memastikan usia pembeli pada tanggal tayang memenuhi klasifikasi film
+func checkBuyerAge(ageRating string, birthDate *time.Time, showDate time.Time) error {
+	minAge := models.AgeRatings[ageRating]
+	if minAge == 0 {
+		return nil
+	}
+	details := map[string]any{"age_rating": ageRating, "min_age": minAge}
+	if birthDate == nil {
+		return apperror.Forbidden(apperror.CodeAgeUnverified, "").WithDetails(details)
+	}
+	if models.AgeOn(*birthDate, showDate) < minAge {
+		return apperror.Forbidden(apperror.CodeAgeRestricted, "").WithDetails(details)
+	}
+	return nil
+}
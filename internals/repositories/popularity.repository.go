@@ -0,0 +1,42 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+)
+
+type PopularityRepository struct {
+	db  *pgxpool.Pool
+	rdb *redis.Client
+}
+
+func NewPopularityRepository(db *pgxpool.Pool, rdb *redis.Client) *PopularityRepository {
+	return &PopularityRepository{db: db, rdb: rdb}
+}
+
+// Refresh menghitung ulang movie_popularity tanpa mengunci pembacaan
+func (pr *PopularityRepository) Refresh(rctx context.Context) error {
+	_, err := pr.db.Exec(rctx, `REFRESH MATERIALIZED VIEW CONCURRENTLY movie_popularity`)
+	return err
+}
+
+// InvalidateCache menghapus cache halaman movie populer, mengembalikan jumlah key yang dihapus
+func (pr *PopularityRepository) InvalidateCache(rctx context.Context) (int, error) {
+	var keys []string
+	iter := pr.rdb.Scan(rctx, 0, PopularMoviesCachePrefix+"*", 100).Iterator()
+	for iter.Next(rctx) {
+		keys = append(keys, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		return 0, err
+	}
+	if len(keys) == 0 {
+		return 0, nil
+	}
+	if err := pr.rdb.Del(rctx, keys...).Err(); err != nil {
+		return 0, err
+	}
+	return len(keys), nil
+}
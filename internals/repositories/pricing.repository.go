@@ -0,0 +1,386 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/federus1105/weekly/internals/models"
+	"github.com/federus1105/weekly/pkg/apperror"
+	"github.com/federus1105/weekly/pkg/pricing"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shopspring/decimal"
+)
+
+type PricingRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPricingRepository(db *pgxpool.Pool) *PricingRepository {
+	return &PricingRepository{db: db}
+}
+
+const priceRuleColumns = `id, name, id_cinema, seat_type, weekdays,
+	to_char(start_time, 'HH24:MI'), to_char(end_time, 'HH24:MI'), holiday, format, customer_category,
+	adjustment, amount, priority, active, created_at`
+
+func scanPriceRule(row pgx.Row) (models.PriceRule, error) {
+	var r models.PriceRule
+	err := row.Scan(&r.Id, &r.Name, &r.CinemaID, &r.SeatType, &r.Weekdays,
+		&r.StartTime, &r.EndTime, &r.Holiday, &r.Format, &r.Category,
+		&r.Adjustment, &r.Amount, &r.Priority, &r.Active, &r.CreatedAt)
+	return r, err
+}
+
+// GetPriceRules mengambil semua aturan harga sesuai urutan penerapannya
+func (pr *PricingRepository) GetPriceRules(rctx context.Context) ([]models.PriceRule, error) {
+	rows, err := pr.db.Query(rctx, `SELECT `+priceRuleColumns+` FROM price_rules ORDER BY priority ASC, id ASC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := []models.PriceRule{}
+	for rows.Next() {
+		r, err := scanPriceRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, r)
+	}
+	return rules, rows.Err()
+}
+
+// diskon persen tidak boleh melebihi harga
+var minPercent = decimal.NewFromInt(-100)
+
+// checkPriceRule menolak amount & rentang jam yang tidak masuk akal
+func checkPriceRule(body models.PriceRuleBody) error {
+	switch {
+	case body.Adjustment == pricing.AdjustSet && body.Amount.IsNegative(),
+		body.Adjustment == pricing.AdjustPercent && body.Amount.LessThan(minPercent):
+		return apperror.Validation(apperror.CodeInvalidPriceRule, "").WithDetails(map[string]any{"field": "amount"})
+	case body.StartTime != nil && body.EndTime != nil && *body.StartTime == *body.EndTime:
+		return apperror.Validation(apperror.CodeInvalidPriceRule, "").WithDetails(map[string]any{"field": "end_time"})
+	}
+	return nil
+}
+
+// priceRuleArgs urutannya sama dengan kolom insert/update price_rules
+func priceRuleArgs(body models.PriceRuleBody) []any {
+	weekdays := body.Weekdays
+	if weekdays == nil {
+		weekdays = []int{}
+	}
+	active := true
+	if body.Active != nil {
+		active = *body.Active
+	}
+	return []any{body.Name, body.CinemaID, body.SeatType, weekdays, body.StartTime, body.EndTime,
+		body.Holiday, body.Format, body.Category, body.Adjustment, body.Amount, body.Priority, active}
+}
+
+func (pr *PricingRepository) CreatePriceRule(rctx context.Context, body models.PriceRuleBody) (models.PriceRule, error) {
+	if err := checkPriceRule(body); err != nil {
+		return models.PriceRule{}, err
+	}
+	r, err := scanPriceRule(pr.db.QueryRow(rctx, `
+		INSERT INTO price_rules (name, id_cinema, seat_type, weekdays, start_time, end_time,
+			holiday, format, customer_category, adjustment, amount, priority, active)
+		VALUES ($1, $2, $3, $4, $5::time, $6::time, $7, $8, $9, $10, $11, $12, $13)
+		RETURNING `+priceRuleColumns, priceRuleArgs(body)...))
+	if err != nil {
+		return models.PriceRule{}, priceRuleError(err)
+	}
+	return r, nil
+}
+
+// UpdatePriceRule mengganti seluruh isi aturan harga
+func (pr *PricingRepository) UpdatePriceRule(rctx context.Context, ruleID int, body models.PriceRuleBody) (models.PriceRule, error) {
+	if err := checkPriceRule(body); err != nil {
+		return models.PriceRule{}, err
+	}
+	args := append(priceRuleArgs(body), ruleID)
+	r, err := scanPriceRule(pr.db.QueryRow(rctx, `
+		UPDATE price_rules
+		SET name = $1, id_cinema = $2, seat_type = $3, weekdays = $4, start_time = $5::time, end_time = $6::time,
+			holiday = $7, format = $8, customer_category = $9, adjustment = $10, amount = $11, priority = $12, active = $13
+		WHERE id = $14
+		RETURNING `+priceRuleColumns, args...))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.PriceRule{}, apperror.NotFound(apperror.CodePriceRuleNotFound, "")
+	}
+	if err != nil {
+		return models.PriceRule{}, priceRuleError(err)
+	}
+	return r, nil
+}
+
+func (pr *PricingRepository) DeletePriceRule(rctx context.Context, ruleID int) error {
+	tag, err := pr.db.Exec(rctx, `DELETE FROM price_rules WHERE id = $1`, ruleID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return apperror.NotFound(apperror.CodePriceRuleNotFound, "")
+	}
+	return nil
+}
+
+// priceRuleError memetakan cinema yang tidak ada ke kode error yang jelas
+func priceRuleError(err error) error {
+	if appErr, ok := apperror.As(apperror.FromDB(err)); ok && appErr.Code == apperror.CodeReferenceNotFound {
+		return apperror.NotFound(apperror.CodeCinemaNotFound, "").Wrap(err)
+	}
+	return err
+}
+
+func (pr *PricingRepository) GetHolidays(rctx context.Context, filter models.HolidayFilter) ([]models.Holiday, error) {
+	sql := `SELECT to_char("date", 'YYYY-MM-DD'), name FROM holidays`
+	args := []any{}
+	if filter.Year != nil {
+		sql += ` WHERE EXTRACT(YEAR FROM "date") = $1`
+		args = append(args, *filter.Year)
+	}
+	rows, err := pr.db.Query(rctx, sql+` ORDER BY "date" ASC`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	holidays := []models.Holiday{}
+	for rows.Next() {
+		var h models.Holiday
+		if err := rows.Scan(&h.Date, &h.Name); err != nil {
+			return nil, err
+		}
+		holidays = append(holidays, h)
+	}
+	return holidays, rows.Err()
+}
+
+// SaveHoliday menambah hari libur, nama diganti jika tanggalnya sudah ada
+func (pr *PricingRepository) SaveHoliday(rctx context.Context, body models.HolidayBody) (models.Holiday, error) {
+	var h models.Holiday
+	err := pr.db.QueryRow(rctx, `
+		INSERT INTO holidays ("date", name)
+		VALUES ($1::date, $2)
+		ON CONFLICT ("date") DO UPDATE SET name = EXCLUDED.name
+		RETURNING to_char("date", 'YYYY-MM-DD'), name`, body.Date, body.Name).Scan(&h.Date, &h.Name)
+	return h, err
+}
+
+func (pr *PricingRepository) DeleteHoliday(rctx context.Context, date string) error {
+	tag, err := pr.db.Exec(rctx, `DELETE FROM holidays WHERE "date" = $1::date`, date)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return apperror.NotFound(apperror.CodeHolidayNotFound, "")
+	}
+	return nil
+}
+
+// showPricing adalah data jadwal yang dibutuhkan untuk menghitung harga & memvalidasi pembeli
+type showPricing struct {
+	movieID   int
+	cinemaID  int
+	basePrice decimal.Decimal
+	ageRating string
+	start     time.Time
+	format    string
+	holiday   bool
+	birthDate *time.Time
+}
+
+// loadShowPricing mengunci schedule (FOR SHARE) supaya tidak dibatalkan selama order dibuat,
+// jadwal yang sudah dibatalkan atau sudah dimulai ditolak
+func loadShowPricing(rctx context.Context, tx pgx.Tx, scheduleID, userID int) (showPricing, error) {
+	var p showPricing
+	var timeZone string
+	var cancelled, started bool
+	err := tx.QueryRow(rctx, `
+		SELECT m.id, c.id, c.price::numeric(12,2), m.age_rating, s.start_at, c.time_zone, st.format, a.date_of_birth,
+			s.cancelled_at IS NOT NULL, s.start_at <= now(),
+			EXISTS (SELECT 1 FROM holidays h WHERE h."date" = `+localStartSQL+`::date)
+		FROM schedule s
+		JOIN studio st ON s.id_studio = st.id
+		JOIN cinema c ON st.id_cinema = c.id
+		JOIN movies m ON s.id_movie = m.id
+		LEFT JOIN account a ON a.user_id = $2
+		WHERE s.id = $1
+		FOR SHARE OF s`, scheduleID, userID).
+		Scan(&p.movieID, &p.cinemaID, &p.basePrice, &p.ageRating, &p.start, &timeZone, &p.format, &p.birthDate, &cancelled, &started, &p.holiday)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return p, apperror.NotFound(apperror.CodeScheduleNotFound, "")
+	}
+	if err != nil {
+		return p, err
+	}
+	if cancelled {
+		return p, apperror.Conflict(apperror.CodeScheduleCancelled, "")
+	}
+	if started {
+		return p, apperror.Conflict(apperror.CodeScheduleStarted, "")
+	}
+	p.start = p.start.In(models.TimeZone(timeZone))
+	return p, nil
+}
+
+// checkCategory memastikan usia pembeli pada tanggal tayang sesuai kategori anak/lansia,
+// kategori pelajar diperiksa di pintu studio
+func checkCategory(category string, birthDate *time.Time, showDate time.Time) error {
+	if category != models.CategoryChild && category != models.CategorySenior {
+		return nil
+	}
+	details := map[string]any{"category": category}
+	if birthDate == nil {
+		return apperror.Forbidden(apperror.CodeCategoryUnverified, "").WithDetails(details)
+	}
+	age := models.AgeOn(*birthDate, showDate)
+	if (category == models.CategoryChild && age >= models.ChildMaxAge) ||
+		(category == models.CategorySenior && age < models.SeniorMinAge) {
+		return apperror.Forbidden(apperror.CodeCategoryDenied, "").WithDetails(details)
+	}
+	return nil
+}
+
+// activePriceRules mengambil aturan harga aktif yang berlaku di cinema-cinema tersebut
+func activePriceRules(rctx context.Context, q querier, cinemaIDs []int) ([]pricing.Rule, error) {
+	rows, err := q.Query(rctx, `
+		SELECT id, name, id_cinema, COALESCE(seat_type, ''), weekdays,
+			COALESCE(to_char(start_time, 'HH24:MI'), ''), COALESCE(to_char(end_time, 'HH24:MI'), ''),
+			holiday, COALESCE(format, ''), COALESCE(customer_category, ''), adjustment, amount, priority
+		FROM price_rules
+		WHERE active AND (id_cinema IS NULL OR id_cinema = ANY($1::int[]))
+		ORDER BY priority ASC, id ASC`, cinemaIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []pricing.Rule
+	for rows.Next() {
+		var r pricing.Rule
+		if err := rows.Scan(&r.ID, &r.Name, &r.CinemaID, &r.SeatType, &r.Weekdays, &r.StartTime, &r.EndTime,
+			&r.Holiday, &r.Format, &r.Category, &r.Adjustment, &r.Amount, &r.Priority); err != nil {
+			return nil, err
+		}
+		rules = append(rules, r)
+	}
+	return rules, rows.Err()
+}
+
+// quoteSeats menghitung rincian harga setiap kursi dengan urutan sesuai seatIDs. Dipakai oleh
+// quote dan CreateOrder supaya rincian yang ditampilkan sama dengan yang ditagih & disimpan.
+func quoteSeats(rctx context.Context, tx pgx.Tx, show showPricing, charges pricing.Charges, scheduleID int, seatIDs []int, category string) (models.Quote, error) {
+	rows, err := tx.Query(rctx, `SELECT id, codeseat, seat_type FROM seats WHERE id = ANY($1::int[])`, seatIDs)
+	if err != nil {
+		return models.Quote{}, err
+	}
+	seats := make(map[int]models.LineItem, len(seatIDs))
+	for rows.Next() {
+		var s models.LineItem
+		if err := rows.Scan(&s.SeatID, &s.Seat, &s.SeatType); err != nil {
+			rows.Close()
+			return models.Quote{}, err
+		}
+		seats[s.SeatID] = s
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return models.Quote{}, err
+	}
+
+	rules, err := activePriceRules(rctx, tx, []int{show.cinemaID})
+	if err != nil {
+		return models.Quote{}, err
+	}
+
+	quote := models.Quote{
+		ScheduleID: scheduleID,
+		Category:   category,
+		Items:      make([]models.LineItem, 0, len(seatIDs)),
+		TaxRate:    charges.TaxRate,
+	}
+	for _, seatID := range seatIDs {
+		item, ok := seats[seatID]
+		if !ok {
+			return models.Quote{}, apperror.NotFound(apperror.CodeSeatNotFound, "").
+				WithDetails(map[string]any{"seat_id": seatID})
+		}
+		line := charges.Line(show.basePrice, rules, pricing.Ticket{
+			CinemaID: show.cinemaID,
+			SeatType: item.SeatType,
+			Start:    show.start,
+			Holiday:  show.holiday,
+			Format:   show.format,
+			Category: category,
+		})
+		item.BasePrice = line.Base
+		item.Modifiers, item.Discounts = []models.PriceAdjustment{}, []models.PriceAdjustment{}
+		for _, a := range line.Adjustments {
+			adj := models.PriceAdjustment{RuleID: a.Rule.ID, Name: a.Rule.Name, Amount: a.Amount}
+			if a.Amount.IsNegative() {
+				item.Discounts = append(item.Discounts, adj)
+			} else {
+				item.Modifiers = append(item.Modifiers, adj)
+			}
+		}
+		item.Subtotal = line.Subtotal
+		item.Fees = []models.Fee{}
+		if line.Fee.IsPositive() {
+			item.Fees = append(item.Fees, models.Fee{Code: models.FeeBooking, Amount: line.Fee})
+		}
+		item.Tax = line.Tax
+		item.Total = line.Total
+
+		quote.Items = append(quote.Items, item)
+		quote.Subtotal = quote.Subtotal.Add(line.Subtotal)
+		quote.Fees = quote.Fees.Add(line.Fee)
+		quote.Tax = quote.Tax.Add(line.Tax)
+		quote.Total = quote.Total.Add(line.Total)
+	}
+	return quote, nil
+}
+
+// orderPricing adalah isi order/quote yang menentukan harga
+type orderPricing struct {
+	scheduleID int
+	userID     int
+	paymentID  int
+	seatIDs    []int
+	category   string
+	promoCode  string
+	// true saat order dibuat, kode promo dikunci sampai transaksi selesai
+	redeem bool
+}
+
+// prepareOrderPricing memvalidasi pembeli lalu menghitung harga kursi & potongan promo di dalam transaksi
+func prepareOrderPricing(rctx context.Context, tx pgx.Tx, charges pricing.Charges, req orderPricing) (models.Quote, error) {
+	category := req.category
+	if category == "" {
+		category = models.CategoryGeneral
+	}
+	show, err := loadShowPricing(rctx, tx, req.scheduleID, req.userID)
+	if err != nil {
+		return models.Quote{}, err
+	}
+	showDate, _ := time.Parse(time.DateOnly, show.start.Format(time.DateOnly))
+	// ✅ Film dengan batas usia butuh tanggal lahir pembeli
+	if err := checkBuyerAge(show.ageRating, show.birthDate, showDate); err != nil {
+		return models.Quote{}, err
+	}
+	if err := checkCategory(category, show.birthDate, showDate); err != nil {
+		return models.Quote{}, err
+	}
+	quote, err := quoteSeats(rctx, tx, show, charges, req.scheduleID, req.seatIDs, category)
+	if err != nil || req.promoCode == "" {
+		return quote, err
+	}
+	if err := applyPromo(rctx, tx, charges, show, req, &quote); err != nil {
+		return models.Quote{}, err
+	}
+	return quote, nil
+}
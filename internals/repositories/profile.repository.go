@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"time"
 
 	"github.com/federus1105/weekly/internals/middlewares"
 	"github.com/federus1105/weekly/internals/models"
+	"github.com/federus1105/weekly/pkg/apperror"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -33,9 +35,13 @@ func (pr *ProfileRepository) GetProfile(rctx context.Context, UserID int) ([]mod
   COALESCE(a.firstname, ''),
   COALESCE(a.lastname, ''),
   COALESCE(a.phonenumber, ''),
-  a.point
+  a.point,
+  a.date_of_birth,
+  a.id_location,
+  l.name
 	FROM users u
 	JOIN account a ON a.user_id = u.id
+	LEFT JOIN location l ON l.id = a.id_location
 	WHERE u.id = $1;
 	`
 	rows, err := pr.db.Query(rctx, sql, userID)
@@ -52,9 +58,13 @@ func (pr *ProfileRepository) GetProfile(rctx context.Context, UserID int) ([]mod
 			&profiles.FirstName,
 			&profiles.LastName,
 			&profiles.Phone,
-			&profiles.Point); err != nil {
+			&profiles.Point,
+			&profiles.BirthDate,
+			&profiles.LocationID,
+			&profiles.Location); err != nil {
 			return nil, err
 		}
+		profiles.SetImages()
 		profile = append(profile, profiles)
 	}
 	return profile, nil
@@ -66,6 +76,9 @@ func (s *ProfileRepository) EditProfile(
 	firstname *string,
 	lastname *string,
 	phonenumber *string,
+	birthDate *time.Time,
+	locationID *int,
+	beforeCommit BeforeCommit,
 ) (models.Profile, error) {
 	// Ambil user_id dari context
 	userIDRaw := rctx.Value(middlewares.UserIDKey)
@@ -99,10 +112,20 @@ func (s *ProfileRepository) EditProfile(
 		args = append(args, *phonenumber)
 		argID++
 	}
+	if birthDate != nil {
+		setClauses = append(setClauses, fmt.Sprintf("date_of_birth = $%d", argID))
+		args = append(args, *birthDate)
+		argID++
+	}
+	if locationID != nil {
+		setClauses = append(setClauses, fmt.Sprintf("id_location = $%d", argID))
+		args = append(args, *locationID)
+		argID++
+	}
 
 	// Kalau tidak ada field yang ingin diupdate
 	if len(setClauses) == 0 {
-		return models.Profile{}, fmt.Errorf("no fields to update")
+		return models.Profile{}, apperror.Validation(apperror.CodeNoFieldsToUpdate, "")
 	}
 
 	// Tambahkan kondisi WHERE
@@ -110,23 +133,39 @@ func (s *ProfileRepository) EditProfile(
 		UPDATE account 
 		SET %s 
 		WHERE user_id = $%d 
-		RETURNING user_id, image, firstname, lastname, phonenumber;
+		RETURNING user_id, image, firstname, lastname, phonenumber, date_of_birth, id_location;
 	`, strings.Join(setClauses, ", "), argID)
 
 	args = append(args, userID)
 
+	tx, err := s.db.Begin(rctx)
+	if err != nil {
+		return models.Profile{}, err
+	}
+	defer tx.Rollback(rctx)
+
 	var profile models.Profile
-	err := s.db.QueryRow(rctx, query, args...).Scan(
+	err = tx.QueryRow(rctx, query, args...).Scan(
 		&profile.UserID,
 		&profile.Image,
 		&profile.FirstName,
 		&profile.LastName,
 		&profile.Phone,
+		&profile.BirthDate,
+		&profile.LocationID,
 	)
 	if err != nil {
 		log.Println("Internal server error.\nCause:", err.Error())
 		return models.Profile{}, err
 	}
 
+	if err := runBeforeCommit(rctx, beforeCommit); err != nil {
+		return models.Profile{}, err
+	}
+	if err := tx.Commit(rctx); err != nil {
+		return models.Profile{}, err
+	}
+
+	profile.SetImages()
 	return profile, nil
 }
@@ -0,0 +1,365 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/federus1105/weekly/internals/models"
+	"github.com/federus1105/weekly/pkg/apperror"
+	"github.com/federus1105/weekly/pkg/pagination"
+	"github.com/federus1105/weekly/pkg/pricing"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shopspring/decimal"
+)
+
+type PromoRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPromoRepository(db *pgxpool.Pool) *PromoRepository {
+	return &PromoRepository{db: db}
+}
+
+var redemptionKeyset = pagination.Keyset{IDColumn: "pr.id", Desc: true}
+
+const promoColumns = `p.id, p.code, p.description, p.discount_type, p.amount, p.min_spend, p.max_discount,
+	p.valid_from, p.valid_until, p.usage_limit, p.per_user_limit, p.id_movies, p.id_cinemas, p.id_payment_methods,
+	p.active, (SELECT COUNT(*) FROM promo_redemptions r WHERE r.id_promo = p.id)::int4, p.created_at`
+
+func scanPromo(row pgx.Row) (models.Promo, error) {
+	var p models.Promo
+	err := row.Scan(&p.Id, &p.Code, &p.Description, &p.DiscountType, &p.Amount, &p.MinSpend, &p.MaxDiscount,
+		&p.ValidFrom, &p.ValidUntil, &p.UsageLimit, &p.PerUserLimit, &p.MovieIDs, &p.CinemaIDs, &p.PaymentMethodIDs,
+		&p.Active, &p.Redemptions, &p.CreatedAt)
+	return p, err
+}
+
+// GetPromos mengambil semua kode promo, terbaru lebih dulu
+func (pr *PromoRepository) GetPromos(rctx context.Context) ([]models.Promo, error) {
+	rows, err := pr.db.Query(rctx, `SELECT `+promoColumns+` FROM promo_codes p ORDER BY p.id DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	promos := []models.Promo{}
+	for rows.Next() {
+		p, err := scanPromo(rows)
+		if err != nil {
+			return nil, err
+		}
+		promos = append(promos, p)
+	}
+	return promos, rows.Err()
+}
+
+func (pr *PromoRepository) GetPromo(rctx context.Context, promoID int) (models.Promo, error) {
+	p, err := scanPromo(pr.db.QueryRow(rctx, `SELECT `+promoColumns+` FROM promo_codes p WHERE p.id = $1`, promoID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return p, apperror.NotFound(apperror.CodePromoNotFound, "")
+	}
+	return p, err
+}
+
+// checkPromo menolak nilai potongan & rentang berlaku yang tidak masuk akal
+func checkPromo(body models.PromoBody) error {
+	invalid := func(field string) error {
+		return apperror.Validation(apperror.CodeInvalidPromo, "").WithDetails(map[string]any{"field": field})
+	}
+	switch {
+	case !body.Amount.IsPositive(),
+		body.DiscountType == pricing.PromoPercent && body.Amount.GreaterThan(decimal.NewFromInt(100)):
+		return invalid("amount")
+	case body.MinSpend.IsNegative():
+		return invalid("min_spend")
+	case body.MaxDiscount != nil && !body.MaxDiscount.IsPositive():
+		return invalid("max_discount")
+	case body.ValidUntil != nil && !body.ValidUntil.After(body.ValidFrom):
+		return apperror.Validation(apperror.CodeInvalidDateRange, "")
+	}
+	return nil
+}
+
+// promoArgs urutannya sama dengan kolom insert/update promo_codes
+func promoArgs(body models.PromoBody) []any {
+	ids := func(v []int) []int {
+		if v == nil {
+			return []int{}
+		}
+		return v
+	}
+	active := true
+	if body.Active != nil {
+		active = *body.Active
+	}
+	return []any{strings.ToUpper(body.Code), body.Description, body.DiscountType, body.Amount, body.MinSpend,
+		body.MaxDiscount, body.ValidFrom, body.ValidUntil, body.UsageLimit, body.PerUserLimit,
+		ids(body.MovieIDs), ids(body.CinemaIDs), ids(body.PaymentMethodIDs), active}
+}
+
+// promoError memetakan kode promo yang sudah ada ke kode error yang jelas
+func promoError(err error) error {
+	if appErr, ok := apperror.As(apperror.FromDB(err)); ok && appErr.Code == apperror.CodeConflict {
+		return apperror.Conflict(apperror.CodePromoCodeTaken, "").Wrap(err)
+	}
+	return err
+}
+
+// CreatePromo menyimpan kode promo dalam huruf besar
+func (pr *PromoRepository) CreatePromo(rctx context.Context, body models.PromoBody) (models.Promo, error) {
+	if err := checkPromo(body); err != nil {
+		return models.Promo{}, err
+	}
+	p, err := scanPromo(pr.db.QueryRow(rctx, `
+		WITH p AS (
+			INSERT INTO promo_codes (code, description, discount_type, amount, min_spend, max_discount,
+				valid_from, valid_until, usage_limit, per_user_limit, id_movies, id_cinemas, id_payment_methods, active)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+			RETURNING *
+		)
+		SELECT `+promoColumns+` FROM p`, promoArgs(body)...))
+	if err != nil {
+		return models.Promo{}, promoError(err)
+	}
+	return p, nil
+}
+
+// UpdatePromo mengganti seluruh isi kode promo, pemakaian yang sudah tercatat tetap dihitung
+func (pr *PromoRepository) UpdatePromo(rctx context.Context, promoID int, body models.PromoBody) (models.Promo, error) {
+	if err := checkPromo(body); err != nil {
+		return models.Promo{}, err
+	}
+	args := append(promoArgs(body), promoID)
+	p, err := scanPromo(pr.db.QueryRow(rctx, `
+		WITH p AS (
+			UPDATE promo_codes
+			SET code = $1, description = $2, discount_type = $3, amount = $4, min_spend = $5, max_discount = $6,
+				valid_from = $7, valid_until = $8, usage_limit = $9, per_user_limit = $10, id_movies = $11,
+				id_cinemas = $12, id_payment_methods = $13, active = $14
+			WHERE id = $15
+			RETURNING *
+		)
+		SELECT `+promoColumns+` FROM p`, args...))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.Promo{}, apperror.NotFound(apperror.CodePromoNotFound, "")
+	}
+	if err != nil {
+		return models.Promo{}, promoError(err)
+	}
+	return p, nil
+}
+
+// DeletePromo hanya untuk promo yang belum pernah dipakai, selain itu nonaktifkan lewat update
+func (pr *PromoRepository) DeletePromo(rctx context.Context, promoID int) error {
+	tag, err := pr.db.Exec(rctx, `DELETE FROM promo_codes WHERE id = $1`, promoID)
+	if err != nil {
+		if appErr, ok := apperror.As(apperror.FromDB(err)); ok && appErr.Code == apperror.CodeReferenceNotFound {
+			return apperror.Conflict(apperror.CodePromoInUse, "").Wrap(err)
+		}
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return apperror.NotFound(apperror.CodePromoNotFound, "")
+	}
+	return nil
+}
+
+// GetPromoRedemptions menampilkan pemakaian satu kode promo, terbaru lebih dulu
+func (pr *PromoRepository) GetPromoRedemptions(rctx context.Context, promoID int, req pagination.Request) ([]models.PromoRedemption, pagination.Meta, error) {
+	var exists bool
+	if err := pr.db.QueryRow(rctx, `SELECT EXISTS (SELECT 1 FROM promo_codes WHERE id = $1)`, promoID).Scan(&exists); err != nil {
+		return nil, pagination.Meta{}, err
+	}
+	if !exists {
+		return nil, pagination.Meta{}, apperror.NotFound(apperror.CodePromoNotFound, "")
+	}
+
+	conditions := []string{"pr.id_promo = $1"}
+	args := []any{promoID}
+	if where, whereArgs := redemptionKeyset.Where(req.Cursor, len(args)+1); where != "" {
+		conditions = append(conditions, where)
+		args = append(args, whereArgs...)
+	}
+	sql := fmt.Sprintf(`
+		SELECT pr.id, pr.id_order, pr.id_user, o.fullname, o.email, pr.discount, COALESCE(o.total, 0), pr.created_at
+		FROM promo_redemptions pr
+		JOIN orders o ON o.id = pr.id_order
+		WHERE %s
+		%s
+		LIMIT $%d OFFSET $%d`,
+		strings.Join(conditions, " AND "), redemptionKeyset.OrderBy(req.Cursor), len(args)+1, len(args)+2)
+	args = append(args, req.Limit+1, req.Offset())
+
+	rows, err := pr.db.Query(rctx, sql, args...)
+	if err != nil {
+		return nil, pagination.Meta{}, err
+	}
+	defer rows.Close()
+
+	redemptions := []models.PromoRedemption{}
+	for rows.Next() {
+		var r models.PromoRedemption
+		if err := rows.Scan(&r.Id, &r.OrderID, &r.UserID, &r.Fullname, &r.Email, &r.Discount, &r.OrderTotal,
+			&r.CreatedAt); err != nil {
+			return nil, pagination.Meta{}, err
+		}
+		redemptions = append(redemptions, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, pagination.Meta{}, err
+	}
+	redemptions, meta := pagination.Slice(redemptions, req, func(r models.PromoRedemption) (string, int) {
+		return "", r.Id
+	})
+	return redemptions, meta, nil
+}
+
+// GetPromoReport merangkum pemakaian setiap kode promo pada rentang tanggal, revenue adalah
+// total order yang memakai promo tersebut
+func (pr *PromoRepository) GetPromoReport(rctx context.Context, filter models.PromoReportFilter) ([]models.PromoReport, error) {
+	if filter.From != "" && filter.To != "" && filter.From > filter.To {
+		return nil, apperror.Validation(apperror.CodeInvalidDateRange, "")
+	}
+	conditions := []string{"r.id_promo = p.id"}
+	args := []any{}
+	if filter.From != "" {
+		args = append(args, filter.From)
+		conditions = append(conditions, fmt.Sprintf("r.created_at >= $%d::date", len(args)))
+	}
+	if filter.To != "" {
+		args = append(args, filter.To)
+		conditions = append(conditions, fmt.Sprintf("r.created_at < $%d::date + 1", len(args)))
+	}
+	rows, err := pr.db.Query(rctx, `
+		SELECT p.id, p.code, p.active, p.usage_limit, u.redemptions, u.users, u.discount, u.revenue, u.last_redeemed_at
+		FROM promo_codes p
+		CROSS JOIN LATERAL (
+			SELECT COUNT(*)::int4 AS redemptions,
+				COUNT(DISTINCT r.id_user)::int4 AS users,
+				COALESCE(SUM(r.discount), 0) AS discount,
+				COALESCE(SUM(o.total), 0) AS revenue,
+				MAX(r.created_at) AS last_redeemed_at
+			FROM promo_redemptions r
+			JOIN orders o ON o.id = r.id_order
+			WHERE `+strings.Join(conditions, " AND ")+`
+		) u
+		ORDER BY u.redemptions DESC, p.id DESC`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	report := []models.PromoReport{}
+	for rows.Next() {
+		var r models.PromoReport
+		if err := rows.Scan(&r.PromoID, &r.Code, &r.Active, &r.UsageLimit, &r.Redemptions, &r.Users, &r.Discount,
+			&r.Revenue, &r.LastRedeemedAt); err != nil {
+			return nil, err
+		}
+		report = append(report, r)
+	}
+	return report, rows.Err()
+}
+
+// applyPromo memvalidasi kode promo untuk order lalu membagi potongannya ke setiap kursi
+// sebanding dengan subtotalnya, pajak kursi dihitung ulang setelah potongan. Saat redeem,
+// baris promo dikunci FOR UPDATE sehingga pengecekan kuota & pencatatan pemakaian di
+// order lain dengan kode yang sama menunggu transaksi ini selesai.
+func applyPromo(rctx context.Context, tx pgx.Tx, charges pricing.Charges, show showPricing, req orderPricing, quote *models.Quote) error {
+	lock := ""
+	if req.redeem {
+		lock = " FOR UPDATE"
+	}
+	var p models.Promo
+	var valid bool
+	err := tx.QueryRow(rctx, `
+		SELECT id, code, discount_type, amount, min_spend, max_discount, usage_limit, per_user_limit,
+			id_movies, id_cinemas, id_payment_methods,
+			valid_from <= now() AND (valid_until IS NULL OR valid_until > now())
+		FROM promo_codes
+		WHERE code = $1 AND active`+lock, strings.ToUpper(req.promoCode)).
+		Scan(&p.Id, &p.Code, &p.DiscountType, &p.Amount, &p.MinSpend, &p.MaxDiscount, &p.UsageLimit, &p.PerUserLimit,
+			&p.MovieIDs, &p.CinemaIDs, &p.PaymentMethodIDs, &valid)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return apperror.NotFound(apperror.CodePromoNotFound, "").WithDetails(map[string]any{"promo_code": req.promoCode})
+	}
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return apperror.Validation(apperror.CodePromoExpired, "")
+	}
+
+	notApplicable := func(field string) error {
+		return apperror.Validation(apperror.CodePromoNotApplicable, "").WithDetails(map[string]any{"field": field})
+	}
+	switch {
+	case len(p.MovieIDs) > 0 && !slices.Contains(p.MovieIDs, show.movieID):
+		return notApplicable("movie")
+	case len(p.CinemaIDs) > 0 && !slices.Contains(p.CinemaIDs, show.cinemaID):
+		return notApplicable("cinema")
+	case len(p.PaymentMethodIDs) > 0 && !slices.Contains(p.PaymentMethodIDs, req.paymentID):
+		return notApplicable("payment")
+	}
+	if quote.Subtotal.LessThan(p.MinSpend) {
+		return apperror.Validation(apperror.CodePromoMinSpend, "").WithDetails(map[string]any{"min_spend": p.MinSpend})
+	}
+
+	// dihitung setelah baris promo terkunci supaya pemakaian yang baru di-commit ikut terhitung
+	if p.UsageLimit != nil || p.PerUserLimit != nil {
+		var used, usedByUser int
+		err := tx.QueryRow(rctx, `
+			SELECT COUNT(*), COUNT(*) FILTER (WHERE id_user = $2)
+			FROM promo_redemptions
+			WHERE id_promo = $1`, p.Id, req.userID).Scan(&used, &usedByUser)
+		if err != nil {
+			return err
+		}
+		if p.UsageLimit != nil && used >= *p.UsageLimit {
+			return apperror.Conflict(apperror.CodePromoExhausted, "")
+		}
+		if p.PerUserLimit != nil && usedByUser >= *p.PerUserLimit {
+			return apperror.Conflict(apperror.CodePromoUserLimit, "").
+				WithDetails(map[string]any{"per_user_limit": *p.PerUserLimit})
+		}
+	}
+
+	discount := pricing.Promo{Type: p.DiscountType, Amount: p.Amount, MaxDiscount: p.MaxDiscount}.Discount(quote.Subtotal)
+	weights := make([]decimal.Decimal, len(quote.Items))
+	for i, item := range quote.Items {
+		weights[i] = item.Subtotal
+	}
+	shares := pricing.Allocate(discount, weights)
+
+	quote.Subtotal, quote.Tax, quote.Total = decimal.Zero, decimal.Zero, decimal.Zero
+	for i := range quote.Items {
+		item := &quote.Items[i]
+		if shares[i].IsPositive() {
+			item.Discounts = append(item.Discounts, models.PriceAdjustment{PromoID: p.Id, Name: p.Code, Amount: shares[i].Neg()})
+			item.Subtotal = item.Subtotal.Sub(shares[i])
+			fee := decimal.Zero
+			for _, f := range item.Fees {
+				fee = fee.Add(f.Amount)
+			}
+			item.Tax = charges.Tax(item.Subtotal, fee)
+			item.Total = item.Subtotal.Add(fee).Add(item.Tax)
+		}
+		quote.Subtotal = quote.Subtotal.Add(item.Subtotal)
+		quote.Tax = quote.Tax.Add(item.Tax)
+		quote.Total = quote.Total.Add(item.Total)
+	}
+	quote.Promo = &models.AppliedPromo{Id: p.Id, Code: p.Code, Discount: discount}
+	return nil
+}
+
+// redeemPromo mencatat pemakaian promo, dipanggil di transaksi yang sama dengan applyPromo
+func redeemPromo(rctx context.Context, tx pgx.Tx, promo models.AppliedPromo, orderID, userID int) error {
+	_, err := tx.Exec(rctx, `
+		INSERT INTO promo_redemptions (id_promo, id_order, id_user, discount)
+		VALUES ($1, $2, $3, $4)`, promo.Id, orderID, userID, promo.Discount)
+	return err
+}
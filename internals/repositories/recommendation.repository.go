@@ -0,0 +1,133 @@
+package repositories
+
+import (
+	"context"
+	"log"
+	"strconv"
+
+	"github.com/federus1105/weekly/internals/models"
+	"github.com/federus1105/weekly/pkg/pagination"
+	"github.com/federus1105/weekly/pkg/recommend"
+)
+
+// movie sedang tayang: belum dihapus dan masih punya jadwal yang belum mulai
+const nowShowingSQL = `m.is_deleted = false AND EXISTS (
+	SELECT 1 FROM schedule s WHERE s.id_movie = m.id AND s.start_at > now() AND s.cancelled_at IS NULL
+)`
+
+// GetRecommendedMovies mengurutkan movie yang sedang tayang berdasarkan kemiripan
+// dengan movie yang pernah ditonton user (order lunas) dan popularitas.
+// User tanpa histori mendapat daftar movie populer.
+func (mr *MoviesRepository) GetRecommendedMovies(rctx context.Context, userID int, req pagination.Request) ([]models.Movie, pagination.Meta, error) {
+	rows, err := mr.db.Query(rctx, `
+		WITH watched AS (
+			SELECT DISTINCT s.id_movie
+			FROM orders o
+			JOIN schedule s ON s.id = o.id_schedule
+			WHERE o.id_user = $1 AND o.paid = true
+		)
+		SELECT
+			m.id,
+			m.image,
+			m.title,
+			m.rating,
+			m.id_director,
+			ARRAY(SELECT mg.id_genre FROM movies_genre mg WHERE mg.id_movies = m.id),
+			ARRAY(SELECT ma.id_actor FROM movies_actor ma WHERE ma.id_movie = m.id),
+			ARRAY(
+				SELECT g.name
+				FROM movies_genre mg
+				JOIN genres g ON g.id = mg.id_genre
+				WHERE mg.id_movies = m.id
+				ORDER BY g.name
+			),
+			COALESCE(p.score, 0)::float8,
+			m.id IN (SELECT id_movie FROM watched) AS watched
+		FROM movies m
+		LEFT JOIN movie_popularity p ON p.id_movie = m.id
+		WHERE m.id IN (SELECT id_movie FROM watched)
+		OR (`+nowShowingSQL+`)`, userID)
+	if err != nil {
+		log.Println("Internal Server Error: ", err.Error())
+		return nil, pagination.Meta{}, err
+	}
+	defer rows.Close()
+
+	profile := recommend.NewProfile()
+	var candidates []recommend.Candidate
+	movies := map[int]models.Movie{}
+	for rows.Next() {
+		var movie models.Movie
+		var features recommend.Features
+		var watched bool
+		if err := rows.Scan(&movie.Id, &movie.Image, &movie.Title, &movie.Rating, &features.Director,
+			&features.Genres, &features.Actors, &movie.Genres, &movie.PopularityScore, &watched); err != nil {
+			log.Println("Internal Server Error: ", err.Error())
+			return nil, pagination.Meta{}, err
+		}
+		// movie yang sudah ditonton membentuk profile, bukan untuk direkomendasikan lagi
+		if watched {
+			profile.Add(features)
+			continue
+		}
+		movie.SetImages()
+		movies[movie.Id] = movie
+		candidates = append(candidates, recommend.Candidate{ID: movie.Id, Features: features, Popularity: movie.PopularityScore})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, pagination.Meta{}, err
+	}
+
+	if profile.Empty() {
+		return mr.GetPopularMovies(rctx, req)
+	}
+
+	ranked := make([]models.Movie, 0, len(candidates))
+	for _, r := range recommend.Rank(profile, candidates) {
+		movie := movies[r.ID]
+		movie.RecommendationScore = r.Score
+		ranked = append(ranked, movie)
+	}
+	page, meta := pagination.Slice(rankedWindow(ranked, req), req, recommendationKey)
+	return page, meta, nil
+}
+
+func recommendationKey(m models.Movie) (string, int) {
+	return strconv.FormatFloat(m.RecommendationScore, 'g', -1, 64), m.Id
+}
+
+// rankedWindow memilih limit+1 movie dari hasil ranking dengan aturan cursor yang
+// sama seperti keyset (skor, id) menurun, supaya bisa diteruskan ke pagination.Slice
+func rankedWindow(ranked []models.Movie, req pagination.Request) []models.Movie {
+	if req.Cursor == nil {
+		start := min(req.Offset(), len(ranked))
+		return ranked[start:min(start+req.Limit+1, len(ranked))]
+	}
+	score, err := strconv.ParseFloat(req.Cursor.Key, 64)
+	if err != nil {
+		return nil
+	}
+	after := func(m models.Movie) bool {
+		return m.RecommendationScore < score || (m.RecommendationScore == score && m.Id < req.Cursor.ID)
+	}
+
+	window := []models.Movie{}
+	if req.Cursor.Prev {
+		// mundur: movie sebelum cursor, yang terdekat lebih dulu
+		for i := len(ranked) - 1; i >= 0 && len(window) <= req.Limit; i-- {
+			if !after(ranked[i]) && ranked[i].Id != req.Cursor.ID {
+				window = append(window, ranked[i])
+			}
+		}
+		return window
+	}
+	for _, m := range ranked {
+		if len(window) > req.Limit {
+			break
+		}
+		if after(m) {
+			window = append(window, m)
+		}
+	}
+	return window
+}
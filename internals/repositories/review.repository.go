@@ -0,0 +1,333 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/federus1105/weekly/internals/models"
+	"github.com/federus1105/weekly/pkg/apperror"
+	"github.com/federus1105/weekly/pkg/pagination"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ReviewRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewReviewRepository(db *pgxpool.Pool) *ReviewRepository {
+	return &ReviewRepository{db: db}
+}
+
+// review terbaru lebih dulu
+var reviewKeyset = pagination.Keyset{IDColumn: "r.id", Desc: true}
+
+const reviewColumns = `r.id, r.id_movie, r.id_user,
+	TRIM(CONCAT(COALESCE(a.firstname, ''), ' ', COALESCE(a.lastname, ''))) AS author,
+	r.rating, r.review, r.created_at, r.updated_at`
+
+func scanReview(row pgx.Row, r *models.Review, extra ...any) error {
+	dest := append([]any{&r.Id, &r.MovieID, &r.UserID, &r.Author, &r.Rating, &r.Review, &r.CreatedAt, &r.UpdatedAt}, extra...)
+	return row.Scan(dest...)
+}
+
+func reviewID(r models.Review) (string, int) {
+	return "", r.Id
+}
+
+// GetReviews mengambil review yang tampil untuk satu movie
+func (rr *ReviewRepository) GetReviews(rctx context.Context, movieID int, req pagination.Request) (models.ReviewPage, error) {
+	return getMovieReviews(rctx, rr.db, movieID, req)
+}
+
+// getMovieReviews dipakai juga oleh GetDetailMovie
+func getMovieReviews(rctx context.Context, db querier, movieID int, req pagination.Request) (models.ReviewPage, error) {
+	conditions := []string{"r.id_movie = $1", "r.status = 'visible'"}
+	args := []any{movieID}
+	if where, whereArgs := reviewKeyset.Where(req.Cursor, len(args)+1); where != "" {
+		conditions = append(conditions, where)
+		args = append(args, whereArgs...)
+	}
+	sql := fmt.Sprintf(`
+		SELECT %s
+		FROM reviews r
+		LEFT JOIN account a ON a.user_id = r.id_user
+		WHERE %s
+		%s
+		LIMIT $%d OFFSET $%d`,
+		reviewColumns, strings.Join(conditions, " AND "), reviewKeyset.OrderBy(req.Cursor), len(args)+1, len(args)+2)
+	args = append(args, req.Limit+1, req.Offset())
+
+	rows, err := db.Query(rctx, sql, args...)
+	if err != nil {
+		return models.ReviewPage{}, err
+	}
+	defer rows.Close()
+
+	reviews := []models.Review{}
+	for rows.Next() {
+		var r models.Review
+		if err := scanReview(rows, &r); err != nil {
+			return models.ReviewPage{}, err
+		}
+		reviews = append(reviews, r)
+	}
+	if err := rows.Err(); err != nil {
+		return models.ReviewPage{}, err
+	}
+	reviews, meta := pagination.Slice(reviews, req, reviewID)
+	return models.ReviewPage{Items: reviews, Meta: meta}, nil
+}
+
+// refreshReviewStats menghitung ulang rata-rata & jumlah review yang tampil di movie
+func refreshReviewStats(rctx context.Context, tx pgx.Tx, movieID int) error {
+	_, err := tx.Exec(rctx, `
+		UPDATE movies m
+		SET review_count = s.total, review_average = s.average
+		FROM (
+			SELECT COUNT(*) AS total, COALESCE(ROUND(AVG(rating)::numeric, 2), 0)::float8 AS average
+			FROM reviews
+			WHERE id_movie = $1 AND status = 'visible'
+		) s
+		WHERE m.id = $1`, movieID)
+	return err
+}
+
+// CreateReview hanya boleh untuk user yang punya order lunas untuk movie tersebut
+func (rr *ReviewRepository) CreateReview(rctx context.Context, movieID, userID int, body models.ReviewBody) (models.Review, error) {
+	tx, err := rr.db.Begin(rctx)
+	if err != nil {
+		return models.Review{}, err
+	}
+	defer tx.Rollback(rctx)
+
+	var exists, purchased bool
+	err = tx.QueryRow(rctx, `
+		SELECT
+			EXISTS (SELECT 1 FROM movies WHERE id = $1 AND is_deleted = false),
+			EXISTS (
+				SELECT 1
+				FROM orders o
+				JOIN schedule s ON s.id = o.id_schedule
+				WHERE s.id_movie = $1 AND o.id_user = $2 AND o.paid = true
+			)`, movieID, userID).Scan(&exists, &purchased)
+	if err != nil {
+		return models.Review{}, err
+	}
+	if !exists {
+		return models.Review{}, apperror.NotFound(apperror.CodeMovieNotFound, "")
+	}
+	if !purchased {
+		return models.Review{}, apperror.Forbidden(apperror.CodeReviewNotAllowed, "")
+	}
+
+	var review models.Review
+	err = scanReview(tx.QueryRow(rctx, `
+		WITH r AS (
+			INSERT INTO reviews (id_movie, id_user, rating, review)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (id_movie, id_user) DO NOTHING
+			RETURNING *
+		)
+		SELECT `+reviewColumns+`
+		FROM r
+		LEFT JOIN account a ON a.user_id = r.id_user`,
+		movieID, userID, body.Rating, body.Review), &review)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.Review{}, apperror.Conflict(apperror.CodeReviewExists, "")
+	}
+	if err != nil {
+		return models.Review{}, err
+	}
+
+	if err := refreshReviewStats(rctx, tx, movieID); err != nil {
+		return models.Review{}, err
+	}
+	if err := tx.Commit(rctx); err != nil {
+		return models.Review{}, err
+	}
+	return review, nil
+}
+
+// UpdateReview hanya bisa dilakukan pemilik review
+func (rr *ReviewRepository) UpdateReview(rctx context.Context, movieID, reviewID, userID int, body models.ReviewUpdateBody) (models.Review, error) {
+	if body.Rating == nil && body.Review == nil {
+		return models.Review{}, apperror.Validation(apperror.CodeNoFieldsToUpdate, "")
+	}
+	tx, err := rr.db.Begin(rctx)
+	if err != nil {
+		return models.Review{}, err
+	}
+	defer tx.Rollback(rctx)
+
+	var review models.Review
+	err = scanReview(tx.QueryRow(rctx, `
+		WITH r AS (
+			UPDATE reviews
+			SET rating = COALESCE($1, rating), review = COALESCE($2, review), updated_at = now()
+			WHERE id = $3 AND id_movie = $4 AND id_user = $5
+			RETURNING *
+		)
+		SELECT `+reviewColumns+`
+		FROM r
+		LEFT JOIN account a ON a.user_id = r.id_user`,
+		body.Rating, body.Review, reviewID, movieID, userID), &review)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.Review{}, apperror.NotFound(apperror.CodeReviewNotFound, "")
+	}
+	if err != nil {
+		return models.Review{}, err
+	}
+
+	if err := refreshReviewStats(rctx, tx, movieID); err != nil {
+		return models.Review{}, err
+	}
+	if err := tx.Commit(rctx); err != nil {
+		return models.Review{}, err
+	}
+	return review, nil
+}
+
+// DeleteReview menghapus review milik user, admin boleh menghapus review siapa saja
+func (rr *ReviewRepository) DeleteReview(rctx context.Context, movieID, reviewID, userID int, isAdmin bool) error {
+	tx, err := rr.db.Begin(rctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(rctx)
+
+	tag, err := tx.Exec(rctx, `DELETE FROM reviews WHERE id = $1 AND id_movie = $2 AND ($3 OR id_user = $4)`,
+		reviewID, movieID, isAdmin, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return apperror.NotFound(apperror.CodeReviewNotFound, "")
+	}
+	if err := refreshReviewStats(rctx, tx, movieID); err != nil {
+		return err
+	}
+	return tx.Commit(rctx)
+}
+
+// ReportReview mencatat laporan user terhadap review yang tampil, satu kali per user
+func (rr *ReviewRepository) ReportReview(rctx context.Context, movieID, reviewID, userID int, reason string) error {
+	var ownerID int
+	err := rr.db.QueryRow(rctx, `SELECT id_user FROM reviews WHERE id = $1 AND id_movie = $2 AND status = 'visible'`,
+		reviewID, movieID).Scan(&ownerID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return apperror.NotFound(apperror.CodeReviewNotFound, "")
+	}
+	if err != nil {
+		return err
+	}
+	if ownerID == userID {
+		return apperror.Forbidden(apperror.CodeForbidden, "")
+	}
+
+	tag, err := rr.db.Exec(rctx, `
+		INSERT INTO review_reports (id_review, id_user, reason)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (id_review, id_user) DO NOTHING`, reviewID, userID, reason)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return apperror.Conflict(apperror.CodeReviewReported, "")
+	}
+	return nil
+}
+
+// GetReviewsAdmin menampilkan semua review beserta jumlah laporan untuk moderasi
+func (rr *ReviewRepository) GetReviewsAdmin(rctx context.Context, filter models.ReviewFilter, req pagination.Request) ([]models.Review, pagination.Meta, error) {
+	conditions := []string{"true"}
+	args := []any{}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, "r.status = $"+strconv.Itoa(len(args)))
+	}
+	if filter.MovieID != nil {
+		args = append(args, *filter.MovieID)
+		conditions = append(conditions, "r.id_movie = $"+strconv.Itoa(len(args)))
+	}
+	if filter.Reported {
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM review_reports rp WHERE rp.id_review = r.id)")
+	}
+	if where, whereArgs := reviewKeyset.Where(req.Cursor, len(args)+1); where != "" {
+		conditions = append(conditions, where)
+		args = append(args, whereArgs...)
+	}
+	sql := fmt.Sprintf(`
+		SELECT %s, r.status,
+			(SELECT COUNT(*) FROM review_reports rp WHERE rp.id_review = r.id) AS reports
+		FROM reviews r
+		LEFT JOIN account a ON a.user_id = r.id_user
+		WHERE %s
+		%s
+		LIMIT $%d OFFSET $%d`,
+		reviewColumns, strings.Join(conditions, " AND "), reviewKeyset.OrderBy(req.Cursor), len(args)+1, len(args)+2)
+	args = append(args, req.Limit+1, req.Offset())
+
+	rows, err := rr.db.Query(rctx, sql, args...)
+	if err != nil {
+		return nil, pagination.Meta{}, err
+	}
+	defer rows.Close()
+
+	reviews := []models.Review{}
+	for rows.Next() {
+		var r models.Review
+		if err := scanReview(rows, &r, &r.Status, &r.Reports); err != nil {
+			return nil, pagination.Meta{}, err
+		}
+		reviews = append(reviews, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, pagination.Meta{}, err
+	}
+	reviews, meta := pagination.Slice(reviews, req, reviewID)
+	return reviews, meta, nil
+}
+
+// ModerateReview mengubah status review. Laporan yang ada dianggap sudah
+// ditangani sehingga dihapus.
+func (rr *ReviewRepository) ModerateReview(rctx context.Context, reviewID int, status string) (models.Review, error) {
+	tx, err := rr.db.Begin(rctx)
+	if err != nil {
+		return models.Review{}, err
+	}
+	defer tx.Rollback(rctx)
+
+	var review models.Review
+	err = scanReview(tx.QueryRow(rctx, `
+		WITH r AS (
+			UPDATE reviews
+			SET status = $1
+			WHERE id = $2
+			RETURNING *
+		)
+		SELECT `+reviewColumns+`, r.status
+		FROM r
+		LEFT JOIN account a ON a.user_id = r.id_user`,
+		status, reviewID), &review, &review.Status)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.Review{}, apperror.NotFound(apperror.CodeReviewNotFound, "")
+	}
+	if err != nil {
+		return models.Review{}, err
+	}
+
+	if _, err := tx.Exec(rctx, `DELETE FROM review_reports WHERE id_review = $1`, reviewID); err != nil {
+		return models.Review{}, err
+	}
+	if err := refreshReviewStats(rctx, tx, review.MovieID); err != nil {
+		return models.Review{}, err
+	}
+	if err := tx.Commit(rctx); err != nil {
+		return models.Review{}, err
+	}
+	return review, nil
+}
@@ -2,9 +2,16 @@ package repositories
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
+	"strings"
+	"time"
 
 	"github.com/federus1105/weekly/internals/models"
+	"github.com/federus1105/weekly/pkg/apperror"
+	"github.com/federus1105/weekly/pkg/validation"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/redis/go-redis/v9"
 )
@@ -18,75 +25,390 @@ func NewScheduleRepository(db *pgxpool.Pool, rdb *redis.Client) *ScheduleReposit
 	return &ScheduleRepository{db: db, rdb: rdb}
 }
 
-func (sr *ScheduleRepository) GetSchedule(rctx context.Context, id_movie int) ([]models.Schedule, error) {
+// localStartSQL adalah jam mulai jadwal s dalam zona waktu cinema c
+const localStartSQL = `(s.start_at AT TIME ZONE c.time_zone)`
+
+// localDateSQL adalah tanggal tayang lokal jadwal s di cinema c
+const localDateSQL = `to_char(` + localStartSQL + `, 'YYYY-MM-DD')`
+
+// GetSchedule mengambil jadwal movie yang belum mulai tayang. Filter date, from & to
+// memakai tanggal & jam lokal masing-masing cinema, from/to inklusif.
+func (sr *ScheduleRepository) GetSchedule(rctx context.Context, id_movie int, filter models.ScheduleFilter) ([]models.Schedule, error) {
+	conditions := []string{"m.id = $1", "s.cancelled_at IS NULL", "s.start_at > now()"}
+	args := []any{id_movie}
+	if filter.Date != "" {
+		args = append(args, filter.Date)
+		conditions = append(conditions, fmt.Sprintf(localStartSQL+"::date = $%d::date", len(args)))
+	}
+	if filter.From != "" {
+		args = append(args, filter.From)
+		conditions = append(conditions, fmt.Sprintf(localStartSQL+"::time >= $%d::time", len(args)))
+	}
+	if filter.To != "" {
+		args = append(args, filter.To)
+		conditions = append(conditions, fmt.Sprintf(localStartSQL+"::time <= $%d::time", len(args)))
+	}
+	if filter.CinemaID != nil {
+		args = append(args, *filter.CinemaID)
+		conditions = append(conditions, fmt.Sprintf("c.id = $%d", len(args)))
+	}
+	if filter.LocationID != nil {
+		args = append(args, *filter.LocationID)
+		conditions = append(conditions, fmt.Sprintf("c.id_location = $%d", len(args)))
+	}
+
 	sql := `SELECT
 s.id,
 s.id_movie,
-s.date,
+` + localDateSQL + ` AS date,
 		m.title AS title,
 c.id AS idcinema,
 c.name AS cinema,
-t.name AS time,
-l.name AS location,
-c.image as icon
+st.id AS idstudio,
+st.name AS studio,
+to_char(` + localStartSQL + `, 'HH24:MI') AS time,
+COALESCE(l.name, '') AS location,
+c.image as icon,
+s.start_at,
+s.end_at,
+c.time_zone
 	FROM schedule s
 	JOIN movies m ON s.id_movie = m.id
-	LEFT JOIN cinema c ON s.id_cinema = c.id
-	LEFT JOIN time t ON s.id_time = t.id
-	LEFT JOIN location l ON s.id_location = l.id
-	WHERE m.id = $1
-ORDER BY s.date ASC`
+	JOIN studio st ON s.id_studio = st.id
+	JOIN cinema c ON st.id_cinema = c.id
+	LEFT JOIN location l ON c.id_location = l.id
+	WHERE ` + strings.Join(conditions, " AND ") + `
+ORDER BY s.start_at ASC, s.id ASC`
 
-	rows, err := sr.db.Query(rctx, sql, id_movie)
+	rows, err := sr.db.Query(rctx, sql, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var schedules []models.Schedule
+	schedules := []models.Schedule{}
 	for rows.Next() {
 		var schedule models.Schedule
-		if err := rows.Scan(&schedule.Id, &schedule.Idmovie, &schedule.Date, &schedule.Title, &schedule.Id_Cinema, &schedule.Cinema, &schedule.Time, &schedule.Location, &schedule.Image); err != nil {
+		if err := rows.Scan(&schedule.Id, &schedule.Idmovie, &schedule.Date, &schedule.Title, &schedule.Id_Cinema, &schedule.Cinema, &schedule.Id_Studio, &schedule.Studio, &schedule.Time, &schedule.Location, &schedule.Image, &schedule.StartAt, &schedule.EndAt, &schedule.TimeZone); err != nil {
 			return nil, err
 		}
+		schedule.SetTimeZone()
 		schedules = append(schedules, schedule)
 	}
-	return schedules, nil
+	return schedules, rows.Err()
 }
 
+// CreateSchedule membuat jadwal untuk setiap kombinasi studio & jam mulai di satu tanggal.
+// Semua jadwal ditolak jika ada yang bentrok, lihat planSchedules.
 func (sr *ScheduleRepository) CreateSchedule(
 	rctx context.Context,
 	input models.BodyScheduleInput,
 ) ([]models.BodySchedule, error) {
-	sql := `INSERT INTO schedule (id_movie, date, id_cinema, id_time, id_location)
-            VALUES ($1, $2, $3, $4, $5)
-            RETURNING id, id_movie, date, id_cinema, id_time, id_location`
-
-	var createdSchedules []models.BodySchedule
-
-	for _, cinemaID := range input.Id_Cinema {
-		for _, timeID := range input.Time {
-			for _, locationID := range input.Location {
-				values := []any{input.Id_movie, input.Date, cinemaID, timeID, locationID}
-				var newSchedule models.BodySchedule
-
-				err := sr.db.QueryRow(rctx, sql, values...).Scan(
-					&newSchedule.Id,
-					&newSchedule.Id_movie,
-					&newSchedule.Date,
-					&newSchedule.Id_Cinema,
-					&newSchedule.Id_Time,
-					&newSchedule.Id_Location,
-				)
-				if err != nil {
-					log.Println("Failed to insert schedule:", err)
-					return nil, err
-				}
-
-				createdSchedules = append(createdSchedules, newSchedule)
+	tx, err := sr.db.Begin(rctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(rctx)
+
+	var slots []models.ScheduleSlot
+	for _, studioID := range input.Id_Studio {
+		for _, startTime := range input.StartTimes {
+			slots = append(slots, models.ScheduleSlot{Date: input.Date, StudioID: studioID, StartTime: startTime})
+		}
+	}
+	planned, err := planSchedules(rctx, tx, input.Id_movie, slots)
+	if err != nil {
+		return nil, err
+	}
+	createdSchedules, err := insertSchedules(rctx, tx, input.Id_movie, nil, planned)
+	if err != nil {
+		return nil, err
+	}
+
+	createdIDs := make([]int, 0, len(createdSchedules))
+	for _, schedule := range createdSchedules {
+		createdIDs = append(createdIDs, schedule.Id)
+	}
+	// kabari user yang menunggu movie ini tayang di lokasinya
+	if err := notifyFirstSchedule(rctx, tx, createdIDs); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(rctx); err != nil {
+		return nil, err
+	}
+	return createdSchedules, nil
+}
+
+// plannedSchedule adalah slot yang sudah lolos validasi beserta waktu tayangnya,
+// StartAt & EndAt dalam zona waktu cinema studio
+type plannedSchedule struct {
+	models.ScheduleSlot
+	StartAt time.Time
+	EndAt   time.Time
+}
+
+// planSchedules menghitung start_at & end_at (durasi movie + jeda bersih-bersih)
+// lalu mengumpulkan semua masalah sekaligus: movie dihapus, studio tidak ada,
+// jadwal di masa lalu, dan bentrok dengan jadwal lain di studio yang sama.
+// Studio dikunci sampai transaksi selesai supaya pembuatan jadwal paralel tidak lolos bentrok.
+func planSchedules(rctx context.Context, tx pgx.Tx, movieID int, slots []models.ScheduleSlot) ([]plannedSchedule, error) {
+	planned, conflicts, err := previewSchedules(rctx, tx, movieID, slots)
+	if err != nil {
+		return nil, err
+	}
+	if len(conflicts) > 0 {
+		return nil, scheduleConflictError(conflicts)
+	}
+	return planned, nil
+}
+
+// previewSchedules sama seperti planSchedules tapi mengembalikan jadwal yang lolos
+// dan daftar masalahnya secara terpisah, dipakai untuk preview template
+func previewSchedules(rctx context.Context, tx pgx.Tx, movieID int, slots []models.ScheduleSlot) ([]plannedSchedule, []models.ScheduleConflict, error) {
+	movie := scheduleMovie{id: movieID}
+	var now time.Time
+	err := tx.QueryRow(rctx, `
+		SELECT m.is_deleted, `+movieDurationSQL+`, now()
+		FROM movies m
+		WHERE m.id = $1
+		FOR SHARE`, movieID).Scan(&movie.deleted, &movie.minutes, &now)
+	if errors.Is(err, pgx.ErrNoRows) {
+		movie.deleted = true
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	var studios map[int]*time.Location
+	if !movie.deleted {
+		studioIDs := []int{}
+		for _, slot := range slots {
+			studioIDs = append(studioIDs, slot.StudioID)
+		}
+		if studios, err = lockStudios(rctx, tx, studioIDs); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	planned, conflicts, err := planSlots(movie, now, studios, slots)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	overlaps, err := existingOverlaps(rctx, tx, planned, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	conflicts = append(conflicts, overlaps...)
+
+	// jadwal yang bentrok dengan jadwal lama tidak ikut di-preview,
+	// satu studio tidak punya dua planned dengan start_at yang sama
+	if len(overlaps) > 0 {
+		type studioStart struct {
+			studioID int
+			start    int64
+		}
+		clashed := map[studioStart]bool{}
+		for _, c := range overlaps {
+			clashed[studioStart{c.StudioID, c.StartAt.Unix()}] = true
+		}
+		kept := []plannedSchedule{}
+		for _, p := range planned {
+			if !clashed[studioStart{p.StudioID, p.StartAt.Unix()}] {
+				kept = append(kept, p)
 			}
 		}
+		planned = kept
 	}
+	return planned, conflicts, nil
+}
 
+// scheduleMovie adalah data movie yang dibutuhkan untuk menghitung jadwal,
+// deleted juga true jika movie tidak ditemukan
+type scheduleMovie struct {
+	id      int
+	deleted bool
+	minutes *int
+}
+
+// planSlots menghitung start_at & end_at setiap slot lalu memeriksa aturan yang tidak
+// butuh database: movie dihapus, durasi kosong, studio tidak ada, jadwal di masa lalu
+// dan bentrok dengan slot lain di request yang sama. studios berisi zona waktu studio yang ada.
+func planSlots(movie scheduleMovie, now time.Time, studios map[int]*time.Location, slots []models.ScheduleSlot) ([]plannedSchedule, []models.ScheduleConflict, error) {
+	if movie.deleted {
+		return nil, []models.ScheduleConflict{{Reason: models.ConflictMovieUnavailable}}, nil
+	}
+
+	var conflicts []models.ScheduleConflict
+	hasDuration := movie.minutes != nil && *movie.minutes > 0
+	if !hasDuration {
+		conflicts = append(conflicts, models.ScheduleConflict{Reason: models.ConflictMissingDuration})
+	}
+
+	planned := []plannedSchedule{}
+	for _, slot := range slots {
+		conflict := models.ScheduleConflict{Date: slot.Date, StudioID: slot.StudioID, StartTime: slot.StartTime}
+		loc, ok := studios[slot.StudioID]
+		if !ok {
+			conflict.Reason = models.ConflictStudioNotFound
+			conflicts = append(conflicts, conflict)
+			continue
+		}
+		startAt, err := showtime(slot.Date, slot.StartTime, loc)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !startAt.After(now) {
+			conflict.Reason = models.ConflictPastShowtime
+			conflict.StartAt = &startAt
+			conflicts = append(conflicts, conflict)
+			continue
+		}
+		if !hasDuration {
+			continue
+		}
+		endAt := startAt.Add(time.Duration(*movie.minutes)*time.Minute + models.ScheduleCleaningBuffer)
+
+		// bentrok dengan jadwal lain di request yang sama
+		overlapped := false
+		for _, p := range planned {
+			if p.StudioID == slot.StudioID && p.StartAt.Before(endAt) && startAt.Before(p.EndAt) {
+				conflict.Reason = models.ConflictOverlap
+				conflict.StartAt, conflict.EndAt = &startAt, &endAt
+				conflict.With = &models.ScheduleOverlapWith{MovieID: movie.id, StartAt: p.StartAt, EndAt: p.EndAt}
+				conflicts = append(conflicts, conflict)
+				overlapped = true
+				break
+			}
+		}
+		if !overlapped {
+			planned = append(planned, plannedSchedule{ScheduleSlot: slot, StartAt: startAt, EndAt: endAt})
+		}
+	}
+	return planned, conflicts, nil
+}
+
+// lockStudios mengunci studio sampai transaksi selesai dan mengembalikan zona
+// waktu cinema untuk setiap studio yang ada
+func lockStudios(rctx context.Context, tx pgx.Tx, studioIDs []int) (map[int]*time.Location, error) {
+	studios := map[int]*time.Location{}
+	rows, err := tx.Query(rctx, `
+		SELECT st.id, c.time_zone
+		FROM studio st
+		JOIN cinema c ON c.id = st.id_cinema
+		WHERE st.id = ANY($1)
+		ORDER BY st.id
+		FOR UPDATE OF st`, studioIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int
+		var timeZone string
+		if err := rows.Scan(&id, &timeZone); err != nil {
+			return nil, err
+		}
+		studios[id] = models.TimeZone(timeZone)
+	}
+	return studios, rows.Err()
+}
+
+// existingOverlaps mencari jadwal yang sudah ada dan bentrok dengan planned. Jadwal
+// movie yang dihapus, jadwal yang dibatalkan dan jadwal di excludeIDs diabaikan.
+func existingOverlaps(rctx context.Context, tx pgx.Tx, planned []plannedSchedule, excludeIDs []int) ([]models.ScheduleConflict, error) {
+	if len(planned) == 0 {
+		return nil, nil
+	}
+	plannedStudios, starts, ends := []int{}, []time.Time{}, []time.Time{}
+	for _, p := range planned {
+		plannedStudios = append(plannedStudios, p.StudioID)
+		starts = append(starts, p.StartAt)
+		ends = append(ends, p.EndAt)
+	}
+	if excludeIDs == nil {
+		excludeIDs = []int{}
+	}
+	rows, err := tx.Query(rctx, `
+		SELECT p.idx, s.id, s.id_movie, m.title, s.start_at, s.end_at
+		FROM unnest($1::int4[], $2::timestamptz[], $3::timestamptz[]) WITH ORDINALITY AS p(id_studio, start_at, end_at, idx)
+		JOIN schedule s ON s.id_studio = p.id_studio AND s.start_at < p.end_at AND s.end_at > p.start_at
+		JOIN movies m ON m.id = s.id_movie AND m.is_deleted = false
+		WHERE s.cancelled_at IS NULL AND s.id <> ALL($4)
+		ORDER BY p.idx, s.start_at`, plannedStudios, starts, ends, excludeIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var conflicts []models.ScheduleConflict
+	for rows.Next() {
+		var idx int
+		var with models.ScheduleOverlapWith
+		if err := rows.Scan(&idx, &with.Id, &with.MovieID, &with.Title, &with.StartAt, &with.EndAt); err != nil {
+			return nil, err
+		}
+		p := planned[idx-1]
+		loc := p.StartAt.Location()
+		with.StartAt, with.EndAt = with.StartAt.In(loc), with.EndAt.In(loc)
+		conflicts = append(conflicts, models.ScheduleConflict{
+			Reason:    models.ConflictOverlap,
+			Date:      p.Date,
+			StudioID:  p.StudioID,
+			StartTime: p.StartTime,
+			StartAt:   &p.StartAt,
+			EndAt:     &p.EndAt,
+			With:      &with,
+		})
+	}
+	return conflicts, rows.Err()
+}
+
+func scheduleConflictError(conflicts []models.ScheduleConflict) error {
+	return apperror.Conflict(apperror.CodeScheduleConflict, "").WithDetails(map[string]any{"conflicts": conflicts})
+}
+
+// showtime mengubah tanggal & jam mulai lokal cinema ("HH:MM") menjadi waktu tayang
+func showtime(date, startTime string, loc *time.Location) (time.Time, error) {
+	t, err := time.ParseInLocation(validation.DateLayout+" "+validation.ClockLayout, date+" "+startTime, loc)
+	if err != nil {
+		return time.Time{}, apperror.Validation(apperror.CodeValidation, "").Wrap(err)
+	}
+	return t, nil
+}
+
+// insertSchedules menyimpan jadwal hasil planSchedules, cinema & location mengikuti studio.
+// templateID diisi untuk jadwal yang dibuat dari template.
+func insertSchedules(rctx context.Context, tx pgx.Tx, movieID int, templateID *int, planned []plannedSchedule) ([]models.BodySchedule, error) {
+	sql := `WITH s AS (
+            INSERT INTO schedule (id_movie, id_studio, start_at, end_at, id_template)
+            VALUES ($1, $2, $3, $4, $5)
+            RETURNING id, id_movie, id_studio, start_at, end_at
+        )
+        SELECT s.id, s.id_movie, ` + localDateSQL + `, s.id_studio, c.id, COALESCE(c.id_location, 0), s.start_at, s.end_at, c.time_zone
+        FROM s
+        JOIN studio st ON st.id = s.id_studio
+        JOIN cinema c ON c.id = st.id_cinema`
+
+	createdSchedules := []models.BodySchedule{}
+	for _, p := range planned {
+		var newSchedule models.BodySchedule
+		err := tx.QueryRow(rctx, sql, movieID, p.StudioID, p.StartAt, p.EndAt, templateID).Scan(
+			&newSchedule.Id,
+			&newSchedule.Id_movie,
+			&newSchedule.Date,
+			&newSchedule.Id_Studio,
+			&newSchedule.Id_Cinema,
+			&newSchedule.Id_Location,
+			&newSchedule.StartAt,
+			&newSchedule.EndAt,
+			&newSchedule.TimeZone,
+		)
+		if err != nil {
+			log.Println("Failed to insert schedule:", err)
+			return nil, err
+		}
+		newSchedule.SetTimeZone()
+		createdSchedules = append(createdSchedules, newSchedule)
+	}
 	return createdSchedules, nil
 }
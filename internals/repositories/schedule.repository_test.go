@@ -0,0 +1,190 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/federus1105/weekly/internals/models"
+	"github.com/federus1105/weekly/pkg/apperror"
+)
+
+func TestPlanSlots(t *testing.T) {
+	jakarta := models.TimeZone("Asia/Jakarta")
+	jayapura := models.TimeZone("Asia/Jayapura")
+	// studio 1 & 2 di WIB, studio 3 di WIT
+	studios := map[int]*time.Location{1: jakarta, 2: jakarta, 3: jayapura}
+	now := time.Date(2026, 10, 19, 10, 0, 0, 0, jakarta)
+	minutes := func(m int) *int { return &m }
+	movie := scheduleMovie{id: 7, minutes: minutes(120)}
+	slot := func(studio int, start string) models.ScheduleSlot {
+		return models.ScheduleSlot{Date: "2026-10-19", StudioID: studio, StartTime: start}
+	}
+
+	tests := []struct {
+		name  string
+		movie scheduleMovie
+		slots []models.ScheduleSlot
+		// slot yang lolos dalam format "studio@HH:MM"
+		planned []string
+		reasons []string
+	}{
+		{
+			name:    "deleted movie",
+			movie:   scheduleMovie{id: 7, deleted: true, minutes: minutes(120)},
+			slots:   []models.ScheduleSlot{slot(1, "13:00")},
+			reasons: []string{models.ConflictMovieUnavailable},
+		},
+		{
+			name:    "missing duration",
+			movie:   scheduleMovie{id: 7},
+			slots:   []models.ScheduleSlot{slot(1, "13:00"), slot(1, "13:30")},
+			reasons: []string{models.ConflictMissingDuration},
+		},
+		{
+			name:    "zero duration",
+			movie:   scheduleMovie{id: 7, minutes: minutes(0)},
+			slots:   []models.ScheduleSlot{slot(1, "13:00")},
+			reasons: []string{models.ConflictMissingDuration},
+		},
+		{
+			name:    "studio not found",
+			movie:   movie,
+			slots:   []models.ScheduleSlot{slot(9, "13:00"), slot(1, "13:00")},
+			planned: []string{"1@13:00"},
+			reasons: []string{models.ConflictStudioNotFound},
+		},
+		{
+			name:    "past and current showtime",
+			movie:   movie,
+			slots:   []models.ScheduleSlot{slot(1, "09:00"), slot(1, "10:00"), slot(1, "10:01")},
+			planned: []string{"1@10:01"},
+			reasons: []string{models.ConflictPastShowtime, models.ConflictPastShowtime},
+		},
+		{
+			// 10:30 WIT = 08:30 WIB
+			name:    "past in cinema time zone",
+			movie:   movie,
+			slots:   []models.ScheduleSlot{slot(3, "10:30"), slot(3, "12:30")},
+			planned: []string{"3@12:30"},
+			reasons: []string{models.ConflictPastShowtime},
+		},
+		{
+			// 13:00 + 120 menit + 15 menit jeda selesai 15:15
+			name:    "inside cleaning buffer",
+			movie:   movie,
+			slots:   []models.ScheduleSlot{slot(1, "13:00"), slot(1, "15:10")},
+			planned: []string{"1@13:00"},
+			reasons: []string{models.ConflictOverlap},
+		},
+		{
+			name:    "one minute before buffer ends",
+			movie:   movie,
+			slots:   []models.ScheduleSlot{slot(1, "15:14"), slot(1, "13:00")},
+			planned: []string{"1@15:14"},
+			reasons: []string{models.ConflictOverlap},
+		},
+		{
+			name:    "adjacent after buffer",
+			movie:   movie,
+			slots:   []models.ScheduleSlot{slot(1, "13:00"), slot(1, "15:15"), slot(1, "17:30")},
+			planned: []string{"1@13:00", "1@15:15", "1@17:30"},
+		},
+		{
+			name:    "same time in other studio",
+			movie:   movie,
+			slots:   []models.ScheduleSlot{slot(1, "13:00"), slot(2, "13:00")},
+			planned: []string{"1@13:00", "2@13:00"},
+		},
+		{
+			name:    "duplicate slot",
+			movie:   movie,
+			slots:   []models.ScheduleSlot{slot(1, "13:00"), slot(1, "13:00")},
+			planned: []string{"1@13:00"},
+			reasons: []string{models.ConflictOverlap},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			planned, conflicts, err := planSlots(tt.movie, now, studios, tt.slots)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, p := range planned {
+				got = append(got, fmt.Sprintf("%d@%s", p.StudioID, p.StartAt.Format("15:04")))
+				if want := p.StartAt.Add(120*time.Minute + models.ScheduleCleaningBuffer); !p.EndAt.Equal(want) {
+					t.Errorf("%d@%s end = %s, want %s", p.StudioID, p.StartTime, p.EndAt, want)
+				}
+			}
+			if !slices.Equal(got, tt.planned) {
+				t.Errorf("planned = %v, want %v", got, tt.planned)
+			}
+			var reasons []string
+			for _, c := range conflicts {
+				reasons = append(reasons, c.Reason)
+			}
+			if !slices.Equal(reasons, tt.reasons) {
+				t.Errorf("reasons = %v, want %v", reasons, tt.reasons)
+			}
+		})
+	}
+}
+
+func TestPlanSlotsOverlapDetails(t *testing.T) {
+	jakarta := models.TimeZone("Asia/Jakarta")
+	now := time.Date(2026, 10, 19, 10, 0, 0, 0, jakarta)
+	minutes := 90
+	slots := []models.ScheduleSlot{
+		{Date: "2026-10-20", StudioID: 1, StartTime: "19:00"},
+		{Date: "2026-10-20", StudioID: 1, StartTime: "20:30"},
+	}
+
+	_, conflicts, err := planSlots(scheduleMovie{id: 7, minutes: &minutes}, now, map[int]*time.Location{1: jakarta}, slots)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(conflicts) != 1 {
+		t.Fatalf("conflicts = %+v, want 1", conflicts)
+	}
+	c := conflicts[0]
+	wantStart := time.Date(2026, 10, 20, 20, 30, 0, 0, jakarta)
+	if c.StartTime != "20:30" || !c.StartAt.Equal(wantStart) || !c.EndAt.Equal(wantStart.Add(105*time.Minute)) {
+		t.Errorf("conflict slot = %s %v-%v", c.StartTime, c.StartAt, c.EndAt)
+	}
+	// bentrok di request yang sama tidak punya id jadwal
+	if c.With == nil || c.With.Id != nil || c.With.MovieID != 7 ||
+		!c.With.StartAt.Equal(time.Date(2026, 10, 20, 19, 0, 0, 0, jakarta)) {
+		t.Errorf("conflict with = %+v", c.With)
+	}
+}
+
+func TestShowtime(t *testing.T) {
+	jakarta := models.TimeZone("Asia/Jakarta")
+	tests := []struct {
+		date, start string
+		loc         *time.Location
+		want        time.Time
+		wantErr     bool
+	}{
+		{"2026-10-19", "13:05", jakarta, time.Date(2026, 10, 19, 6, 5, 0, 0, time.UTC), false},
+		{"2026-10-19", "00:00", models.TimeZone("Asia/Makassar"), time.Date(2026, 10, 18, 16, 0, 0, 0, time.UTC), false},
+		{"2026-10-19", "25:00", jakarta, time.Time{}, true},
+		{"19-10-2026", "13:00", jakarta, time.Time{}, true},
+		{"2026-10-19", "1:00 PM", jakarta, time.Time{}, true},
+	}
+	for _, tt := range tests {
+		got, err := showtime(tt.date, tt.start, tt.loc)
+		if tt.wantErr {
+			if !errors.Is(err, apperror.ErrValidation) {
+				t.Errorf("showtime(%s %s) err = %v, want validation error", tt.date, tt.start, err)
+			}
+			continue
+		}
+		if err != nil || !got.Equal(tt.want) || got.Location() != tt.loc {
+			t.Errorf("showtime(%s %s) = %v, %v, want %v", tt.date, tt.start, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/federus1105/weekly/internals/models"
+	"github.com/federus1105/weekly/pkg/pricing"
+)
+
+// kmPerDegree adalah panjang satu derajat lintang dalam km
+const kmPerDegree = 111.045
+
+// distanceSQL adalah jarak haversine (km) dari cinema c ke koordinat $lat, $lng
+func distanceSQL(latArg, lngArg int) string {
+	return fmt.Sprintf(`(6371 * 2 * asin(sqrt(LEAST(1,
+		power(sin(radians(c.latitude - $%[1]d) / 2), 2)
+		+ cos(radians($%[1]d)) * cos(radians(c.latitude)) * power(sin(radians(c.longitude - $%[2]d) / 2), 2)))))`,
+		latArg, lngArg)
+}
+
+// boundingBox adalah kotak lintang/bujur di sekitar titik pencarian supaya index koordinat
+// cinema terpakai sebelum jarak haversine dihitung. ok false untuk bujur jika kotak
+// melewati kutub atau garis batas tanggal, cukup filter lintang saja.
+func boundingBox(lat, lng, radiusKm float64) (minLat, maxLat, minLng, maxLng float64, ok bool) {
+	latDelta := radiusKm / kmPerDegree
+	minLat, maxLat = lat-latDelta, lat+latDelta
+	cosLat := math.Cos(lat * math.Pi / 180)
+	if cosLat < 0.01 {
+		return minLat, maxLat, 0, 0, false
+	}
+	lngDelta := radiusKm / (kmPerDegree * cosLat)
+	minLng, maxLng = lng-lngDelta, lng+lngDelta
+	if minLng < -180 || maxLng > 180 {
+		return minLat, maxLat, 0, 0, false
+	}
+	return minLat, maxLat, minLng, maxLng, true
+}
+
+// SearchShowtimes mencari jadwal movie yang belum mulai di semua cinema, dikelompokkan
+// per cinema. Urutan cinema dari yang terdekat jika mencari dengan koordinat, selain
+// itu berdasarkan nama. Sisa kursi dihitung dari kursi yang sudah dipesan di jadwal itu,
+// harga adalah harga kursi regular kategori general setelah aturan harga.
+func (sr *ScheduleRepository) SearchShowtimes(rctx context.Context, filter models.ShowtimeSearchFilter) ([]models.CinemaShowtimes, error) {
+	conditions := []string{"s.id_movie = $1", "m.is_deleted = false", "s.cancelled_at IS NULL", "s.start_at > now()"}
+	args := []any{filter.MovieID}
+
+	// rentang start_at dilebarkan sehari ke tiap sisi supaya index (id_movie, start_at)
+	// terpakai, tanggal lokal persisnya dicek per zona waktu cinema
+	if filter.Date != "" {
+		args = append(args, filter.Date)
+		conditions = append(conditions,
+			fmt.Sprintf("s.start_at >= $%d::date - interval '1 day'", len(args)),
+			fmt.Sprintf("s.start_at < $%d::date + interval '2 day'", len(args)),
+			fmt.Sprintf(localStartSQL+"::date = $%d::date", len(args)))
+	} else {
+		conditions = append(conditions,
+			"s.start_at < now() + interval '2 day'",
+			localStartSQL+"::date = (now() AT TIME ZONE c.time_zone)::date")
+	}
+	if filter.From != "" {
+		args = append(args, filter.From)
+		conditions = append(conditions, fmt.Sprintf(localStartSQL+"::time >= $%d::time", len(args)))
+	}
+	if filter.To != "" {
+		args = append(args, filter.To)
+		conditions = append(conditions, fmt.Sprintf(localStartSQL+"::time <= $%d::time", len(args)))
+	}
+	if filter.LocationID != nil {
+		args = append(args, *filter.LocationID)
+		conditions = append(conditions, fmt.Sprintf("c.id_location = $%d", len(args)))
+	}
+	if len(filter.Formats) > 0 {
+		args = append(args, filter.Formats)
+		conditions = append(conditions, fmt.Sprintf("st.format = ANY($%d::text[])", len(args)))
+	}
+
+	distance := "NULL::float8"
+	if filter.Latitude != nil && filter.Longitude != nil {
+		radius := models.DefaultSearchRadiusKm
+		if filter.RadiusKm != nil {
+			radius = *filter.RadiusKm
+		}
+		lat, lng := *filter.Latitude, *filter.Longitude
+		args = append(args, lat, lng, radius)
+		distance = distanceSQL(len(args)-2, len(args)-1)
+		conditions = append(conditions, fmt.Sprintf(distance+" <= $%d", len(args)))
+
+		minLat, maxLat, minLng, maxLng, ok := boundingBox(lat, lng, radius)
+		args = append(args, minLat, maxLat)
+		conditions = append(conditions, fmt.Sprintf("c.latitude BETWEEN $%d AND $%d", len(args)-1, len(args)))
+		if ok {
+			args = append(args, minLng, maxLng)
+			conditions = append(conditions, fmt.Sprintf("c.longitude BETWEEN $%d AND $%d", len(args)-1, len(args)))
+		}
+	}
+
+	sql := `
+SELECT
+  c.id,
+  c.name,
+  COALESCE(c.image, ''),
+  c.address,
+  COALESCE(l.name, ''),
+  c.latitude,
+  c.longitude,
+  ` + distance + ` AS distance_km,
+  c.time_zone,
+  s.id,
+  st.id,
+  st.name,
+  st.format,
+  s.start_at,
+  s.end_at,
+  to_char(` + localStartSQL + `, 'HH24:MI'),
+  seat.capacity,
+  GREATEST(seat.capacity - sold.seats, 0),
+  c.price::numeric(12,2),
+  EXISTS (SELECT 1 FROM holidays h WHERE h."date" = ` + localStartSQL + `::date)
+FROM schedule s
+JOIN movies m ON m.id = s.id_movie
+JOIN studio st ON st.id = s.id_studio
+JOIN cinema c ON c.id = st.id_cinema
+LEFT JOIN location l ON l.id = c.id_location
+CROSS JOIN LATERAL (
+  SELECT COALESCE(st.capacity, (SELECT COUNT(*) FROM seats))::int4 AS capacity
+) seat
+CROSS JOIN LATERAL (
+  SELECT COUNT(*)::int4 AS seats
+  FROM orders o
+  JOIN order_seat os ON os.id_order = o.id
+  WHERE o.id_schedule = s.id
+) sold
+WHERE ` + strings.Join(conditions, " AND ") + `
+ORDER BY distance_km ASC NULLS LAST, c.name ASC, c.id ASC, s.start_at ASC, s.id ASC`
+
+	rows, err := sr.db.Query(rctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := []models.CinemaShowtimes{}
+	index := map[int]int{}
+	holidays := map[int]bool{}
+	for rows.Next() {
+		var c models.CinemaShowtimes
+		var st models.ShowtimeSummary
+		var holiday bool
+		if err := rows.Scan(&c.CinemaID, &c.Cinema, &c.Image, &c.Address, &c.Location, &c.Latitude, &c.Longitude,
+			&c.DistanceKm, &c.TimeZone, &st.ScheduleID, &st.StudioID, &st.Studio, &st.Format, &st.StartAt, &st.EndAt,
+			&st.Time, &st.Capacity, &st.SeatsLeft, &st.Price, &holiday); err != nil {
+			return nil, err
+		}
+		holidays[st.ScheduleID] = holiday
+		loc := models.TimeZone(c.TimeZone)
+		st.StartAt, st.EndAt = st.StartAt.In(loc), st.EndAt.In(loc)
+
+		i, ok := index[c.CinemaID]
+		if !ok {
+			i = len(results)
+			index[c.CinemaID] = i
+			results = append(results, c)
+		}
+		results[i].Showtimes = append(results[i].Showtimes, st)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	if len(results) == 0 {
+		return results, nil
+	}
+	cinemaIDs := make([]int, len(results))
+	for i, c := range results {
+		cinemaIDs[i] = c.CinemaID
+	}
+	rules, err := activePriceRules(rctx, sr.db, cinemaIDs)
+	if err != nil {
+		return nil, err
+	}
+	// harga kursi reguler kategori umum dengan aturan & input yang sama seperti quoteSeats.
+	// pricing.Price tidak pernah gagal, nilai kedua hanya rincian aturan yang tidak ditampilkan.
+	for i := range results {
+		for j := range results[i].Showtimes {
+			st := &results[i].Showtimes[j]
+			price, _ := pricing.Price(st.Price, rules, pricing.Ticket{
+				CinemaID: results[i].CinemaID,
+				SeatType: models.SeatRegular,
+				Start:    st.StartAt,
+				Holiday:  holidays[st.ScheduleID],
+				Format:   st.Format,
+				Category: models.CategoryGeneral,
+			})
+			st.Price = price
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,419 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/federus1105/weekly/internals/models"
+	"github.com/federus1105/weekly/pkg/apperror"
+	"github.com/federus1105/weekly/pkg/validation"
+	"github.com/jackc/pgx/v5"
+)
+
+const scheduleTemplateColumns = `t.id, t.id_movie, m.title, t.id_studio, st.name, c.id, c.name, c.time_zone,
+	t.start_times, t.weekdays, t.start_date, t.end_date, t.exceptions, t.created_at,
+	(SELECT COUNT(*) FROM schedule s
+		WHERE s.id_template = t.id AND s.cancelled_at IS NULL AND s.start_at > now())`
+
+const scheduleTemplateFrom = `
+	FROM schedule_templates t
+	JOIN movies m ON m.id = t.id_movie
+	JOIN studio st ON st.id = t.id_studio
+	JOIN cinema c ON c.id = st.id_cinema`
+
+func scanScheduleTemplate(row pgx.Row, t *models.ScheduleTemplate) error {
+	return row.Scan(&t.Id, &t.MovieID, &t.Title, &t.StudioID, &t.Studio, &t.CinemaID, &t.Cinema, &t.TimeZone,
+		&t.StartTimes, &t.Weekdays, &t.StartDate, &t.EndDate, &t.Exceptions, &t.CreatedAt, &t.Upcoming)
+}
+
+// GetScheduleTemplates mengambil semua template, bisa difilter per movie
+func (sr *ScheduleRepository) GetScheduleTemplates(rctx context.Context, filter models.ScheduleTemplateFilter) ([]models.ScheduleTemplate, error) {
+	sql := `SELECT ` + scheduleTemplateColumns + scheduleTemplateFrom
+	args := []any{}
+	if filter.MovieID != nil {
+		sql += ` WHERE t.id_movie = $1`
+		args = append(args, *filter.MovieID)
+	}
+	sql += ` ORDER BY t.id DESC`
+
+	rows, err := sr.db.Query(rctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	templates := []models.ScheduleTemplate{}
+	for rows.Next() {
+		var t models.ScheduleTemplate
+		if err := scanScheduleTemplate(rows, &t); err != nil {
+			return nil, err
+		}
+		templates = append(templates, t)
+	}
+	return templates, rows.Err()
+}
+
+// GetScheduleTemplate mengambil template beserta jadwal hasil template yang belum tayang
+func (sr *ScheduleRepository) GetScheduleTemplate(rctx context.Context, templateID int) (models.ScheduleTemplate, error) {
+	var t models.ScheduleTemplate
+	err := scanScheduleTemplate(sr.db.QueryRow(rctx,
+		`SELECT `+scheduleTemplateColumns+scheduleTemplateFrom+` WHERE t.id = $1`, templateID), &t)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.ScheduleTemplate{}, apperror.NotFound(apperror.CodeTemplateNotFound, "")
+	}
+	if err != nil {
+		return models.ScheduleTemplate{}, err
+	}
+
+	rows, err := sr.db.Query(rctx, `
+		SELECT s.id, s.id_movie, `+localDateSQL+`, s.id_studio, c.id, COALESCE(c.id_location, 0), s.start_at, s.end_at, c.time_zone
+		FROM schedule s
+		JOIN studio st ON st.id = s.id_studio
+		JOIN cinema c ON c.id = st.id_cinema
+		WHERE s.id_template = $1 AND s.cancelled_at IS NULL AND s.start_at > now()
+		ORDER BY s.start_at ASC, s.id ASC`, templateID)
+	if err != nil {
+		return models.ScheduleTemplate{}, err
+	}
+	defer rows.Close()
+
+	t.Schedules = []models.BodySchedule{}
+	for rows.Next() {
+		var s models.BodySchedule
+		if err := rows.Scan(&s.Id, &s.Id_movie, &s.Date, &s.Id_Studio, &s.Id_Cinema, &s.Id_Location, &s.StartAt, &s.EndAt, &s.TimeZone); err != nil {
+			return models.ScheduleTemplate{}, err
+		}
+		s.SetTimeZone()
+		t.Schedules = append(t.Schedules, s)
+	}
+	return t, rows.Err()
+}
+
+// PreviewScheduleTemplate menghitung jadwal yang akan dibuat template beserta
+// masalahnya tanpa menyimpan apa pun
+func (sr *ScheduleRepository) PreviewScheduleTemplate(rctx context.Context, body models.ScheduleTemplateBody) (models.ScheduleTemplatePreview, error) {
+	slots, err := expandTemplate(body)
+	if err != nil {
+		return models.ScheduleTemplatePreview{}, err
+	}
+
+	tx, err := sr.db.Begin(rctx)
+	if err != nil {
+		return models.ScheduleTemplatePreview{}, err
+	}
+	defer tx.Rollback(rctx)
+
+	planned, conflicts, err := previewSchedules(rctx, tx, body.MovieID, slots)
+	if err != nil {
+		return models.ScheduleTemplatePreview{}, err
+	}
+
+	preview := models.ScheduleTemplatePreview{
+		Schedules: make([]models.ScheduleTemplateSlot, 0, len(planned)),
+		Conflicts: conflicts,
+	}
+	if preview.Conflicts == nil {
+		preview.Conflicts = []models.ScheduleConflict{}
+	}
+	for _, p := range planned {
+		preview.Schedules = append(preview.Schedules, models.ScheduleTemplateSlot{
+			Date:      p.Date,
+			StudioID:  p.StudioID,
+			StartTime: p.StartTime,
+			StartAt:   p.StartAt,
+			EndAt:     p.EndAt,
+		})
+	}
+	return preview, nil
+}
+
+// CreateScheduleTemplate menyimpan template dan semua jadwal hasil expand-nya dalam
+// satu transaksi. Jika ada satu jadwal yang bermasalah tidak ada yang dibuat.
+func (sr *ScheduleRepository) CreateScheduleTemplate(rctx context.Context, body models.ScheduleTemplateBody) (models.ScheduleTemplate, error) {
+	slots, err := expandTemplate(body)
+	if err != nil {
+		return models.ScheduleTemplate{}, err
+	}
+	exceptions := []time.Time{}
+	for _, d := range body.Exceptions {
+		day, err := time.Parse(validation.DateLayout, d)
+		if err != nil {
+			return models.ScheduleTemplate{}, apperror.Validation(apperror.CodeValidation, "").Wrap(err)
+		}
+		exceptions = append(exceptions, day)
+	}
+
+	tx, err := sr.db.Begin(rctx)
+	if err != nil {
+		return models.ScheduleTemplate{}, err
+	}
+	defer tx.Rollback(rctx)
+
+	planned, err := planSchedules(rctx, tx, body.MovieID, slots)
+	if err != nil {
+		return models.ScheduleTemplate{}, err
+	}
+
+	var templateID int
+	err = tx.QueryRow(rctx, `
+		INSERT INTO schedule_templates (id_movie, id_studio, start_times, weekdays, start_date, end_date, exceptions)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id`,
+		body.MovieID, body.StudioID, body.StartTimes, body.Weekdays, body.StartDate, body.EndDate, exceptions,
+	).Scan(&templateID)
+	if err != nil {
+		return models.ScheduleTemplate{}, apperror.FromDB(err)
+	}
+
+	createdSchedules, err := insertSchedules(rctx, tx, body.MovieID, &templateID, planned)
+	if err != nil {
+		return models.ScheduleTemplate{}, err
+	}
+	createdIDs := make([]int, 0, len(createdSchedules))
+	for _, schedule := range createdSchedules {
+		createdIDs = append(createdIDs, schedule.Id)
+	}
+	if err := notifyFirstSchedule(rctx, tx, createdIDs); err != nil {
+		return models.ScheduleTemplate{}, err
+	}
+	if err := tx.Commit(rctx); err != nil {
+		return models.ScheduleTemplate{}, err
+	}
+	return sr.GetScheduleTemplate(rctx, templateID)
+}
+
+// CancelTemplateSchedules membatalkan jadwal template yang belum tayang di rentang
+// tanggal lokal body. Jadwal tidak dihapus karena bisa sudah punya order.
+func (sr *ScheduleRepository) CancelTemplateSchedules(rctx context.Context, templateID int, body models.ScheduleBulkBody) (models.ScheduleBulkResult, error) {
+	from, to, err := bulkRange(body)
+	if err != nil {
+		return models.ScheduleBulkResult{}, err
+	}
+
+	tx, err := sr.db.Begin(rctx)
+	if err != nil {
+		return models.ScheduleBulkResult{}, err
+	}
+	defer tx.Rollback(rctx)
+
+	if err := lockTemplate(rctx, tx, templateID); err != nil {
+		return models.ScheduleBulkResult{}, err
+	}
+
+	rows, err := tx.Query(rctx, `
+		UPDATE schedule s
+		SET cancelled_at = now()
+		FROM studio st
+		JOIN cinema c ON c.id = st.id_cinema
+		WHERE st.id = s.id_studio
+		AND s.id_template = $1
+		AND s.cancelled_at IS NULL
+		AND s.start_at > now()
+		AND ($2::date IS NULL OR `+localStartSQL+`::date >= $2::date)
+		AND ($3::date IS NULL OR `+localStartSQL+`::date <= $3::date)
+		RETURNING s.id`, templateID, from, to)
+	if err != nil {
+		return models.ScheduleBulkResult{}, err
+	}
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return models.ScheduleBulkResult{}, err
+	}
+
+	result, err := bulkResult(rctx, tx, ids)
+	if err != nil {
+		return models.ScheduleBulkResult{}, err
+	}
+	if err := tx.Commit(rctx); err != nil {
+		return models.ScheduleBulkResult{}, err
+	}
+	return result, nil
+}
+
+// ShiftTemplateSchedules menggeser jam tayang jadwal template yang belum tayang.
+// Semua jadwal ditolak jika hasil geserannya sudah lewat atau bentrok dengan jadwal lain.
+func (sr *ScheduleRepository) ShiftTemplateSchedules(rctx context.Context, templateID int, body models.ScheduleShiftBody) (models.ScheduleBulkResult, error) {
+	from, to, err := bulkRange(body.ScheduleBulkBody)
+	if err != nil {
+		return models.ScheduleBulkResult{}, err
+	}
+	shift := time.Duration(body.Minutes) * time.Minute
+
+	tx, err := sr.db.Begin(rctx)
+	if err != nil {
+		return models.ScheduleBulkResult{}, err
+	}
+	defer tx.Rollback(rctx)
+
+	if err := lockTemplate(rctx, tx, templateID); err != nil {
+		return models.ScheduleBulkResult{}, err
+	}
+
+	var now time.Time
+	if err := tx.QueryRow(rctx, `SELECT now()`).Scan(&now); err != nil {
+		return models.ScheduleBulkResult{}, err
+	}
+	rows, err := tx.Query(rctx, `
+		SELECT s.id, s.id_studio, s.start_at, s.end_at, c.time_zone
+		FROM schedule s
+		JOIN studio st ON st.id = s.id_studio
+		JOIN cinema c ON c.id = st.id_cinema
+		WHERE s.id_template = $1
+		AND s.cancelled_at IS NULL
+		AND s.start_at > $2
+		AND ($3::date IS NULL OR `+localStartSQL+`::date >= $3::date)
+		AND ($4::date IS NULL OR `+localStartSQL+`::date <= $4::date)
+		ORDER BY s.start_at ASC, s.id ASC
+		FOR UPDATE OF s`, templateID, now, from, to)
+	if err != nil {
+		return models.ScheduleBulkResult{}, err
+	}
+	ids := []int{}
+	studioIDs := []int{}
+	planned := []plannedSchedule{}
+	for rows.Next() {
+		var id int
+		var timeZone string
+		var p plannedSchedule
+		if err := rows.Scan(&id, &p.StudioID, &p.StartAt, &p.EndAt, &timeZone); err != nil {
+			rows.Close()
+			return models.ScheduleBulkResult{}, err
+		}
+		loc := models.TimeZone(timeZone)
+		p.StartAt, p.EndAt = p.StartAt.Add(shift).In(loc), p.EndAt.Add(shift).In(loc)
+		p.Date = p.StartAt.Format(validation.DateLayout)
+		p.StartTime = p.StartAt.Format(validation.ClockLayout)
+		ids = append(ids, id)
+		studioIDs = append(studioIDs, p.StudioID)
+		planned = append(planned, p)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return models.ScheduleBulkResult{}, err
+	}
+
+	if _, err := lockStudios(rctx, tx, studioIDs); err != nil {
+		return models.ScheduleBulkResult{}, err
+	}
+	var conflicts []models.ScheduleConflict
+	for _, p := range planned {
+		if !p.StartAt.After(now) {
+			startAt := p.StartAt
+			conflicts = append(conflicts, models.ScheduleConflict{
+				Reason:    models.ConflictPastShowtime,
+				Date:      p.Date,
+				StudioID:  p.StudioID,
+				StartTime: p.StartTime,
+				StartAt:   &startAt,
+			})
+		}
+	}
+	// jadwal yang digeser tidak dianggap bentrok dengan posisi lamanya sendiri
+	overlaps, err := existingOverlaps(rctx, tx, planned, ids)
+	if err != nil {
+		return models.ScheduleBulkResult{}, err
+	}
+	conflicts = append(conflicts, overlaps...)
+	if len(conflicts) > 0 {
+		return models.ScheduleBulkResult{}, scheduleConflictError(conflicts)
+	}
+
+	_, err = tx.Exec(rctx, `
+		UPDATE schedule
+		SET start_at = start_at + make_interval(mins => $2),
+			end_at = end_at + make_interval(mins => $2)
+		WHERE id = ANY($1)`, ids, body.Minutes)
+	if err != nil {
+		return models.ScheduleBulkResult{}, err
+	}
+
+	result, err := bulkResult(rctx, tx, ids)
+	if err != nil {
+		return models.ScheduleBulkResult{}, err
+	}
+	if err := tx.Commit(rctx); err != nil {
+		return models.ScheduleBulkResult{}, err
+	}
+	return result, nil
+}
+
+// expandTemplate mengubah template menjadi daftar slot: setiap jam mulai di setiap
+// tanggal pada rentang yang harinya cocok dan bukan tanggal pengecualian
+func expandTemplate(body models.ScheduleTemplateBody) ([]models.ScheduleSlot, error) {
+	start, err := time.Parse(validation.DateLayout, body.StartDate)
+	if err != nil {
+		return nil, apperror.Validation(apperror.CodeValidation, "").Wrap(err)
+	}
+	end, err := time.Parse(validation.DateLayout, body.EndDate)
+	if err != nil {
+		return nil, apperror.Validation(apperror.CodeValidation, "").Wrap(err)
+	}
+	if end.Before(start) {
+		return nil, apperror.Validation(apperror.CodeInvalidDateRange, "")
+	}
+
+	weekdays := map[time.Weekday]bool{}
+	for _, d := range body.Weekdays {
+		weekdays[time.Weekday(d)] = true
+	}
+	exceptions := map[string]bool{}
+	for _, d := range body.Exceptions {
+		exceptions[d] = true
+	}
+
+	slots := []models.ScheduleSlot{}
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		date := day.Format(validation.DateLayout)
+		if !weekdays[day.Weekday()] || exceptions[date] {
+			continue
+		}
+		for _, startTime := range body.StartTimes {
+			slots = append(slots, models.ScheduleSlot{Date: date, StudioID: body.StudioID, StartTime: startTime})
+		}
+		if len(slots) > models.MaxTemplateSchedules {
+			return nil, apperror.Validation(apperror.CodeTemplateTooLarge, "").
+				WithDetails(map[string]any{"max": models.MaxTemplateSchedules})
+		}
+	}
+	if len(slots) == 0 {
+		return nil, apperror.Validation(apperror.CodeTemplateEmpty, "")
+	}
+	return slots, nil
+}
+
+// bulkRange mengubah from/to kosong menjadi nil (tanpa batas)
+func bulkRange(body models.ScheduleBulkBody) (*string, *string, error) {
+	var from, to *string
+	if body.From != "" {
+		from = &body.From
+	}
+	if body.To != "" {
+		to = &body.To
+	}
+	if from != nil && to != nil && *to < *from {
+		return nil, nil, apperror.Validation(apperror.CodeInvalidDateRange, "")
+	}
+	return from, to, nil
+}
+
+// lockTemplate mengunci template supaya pembatalan & pergeseran tidak berjalan bersamaan
+func lockTemplate(rctx context.Context, tx pgx.Tx, templateID int) error {
+	var id int
+	err := tx.QueryRow(rctx, `SELECT id FROM schedule_templates WHERE id = $1 FOR UPDATE`, templateID).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return apperror.NotFound(apperror.CodeTemplateNotFound, "")
+	}
+	return err
+}
+
+// bulkResult menghitung order lunas pada jadwal yang terkena pembatalan/pergeseran
+func bulkResult(rctx context.Context, tx pgx.Tx, ids []int) (models.ScheduleBulkResult, error) {
+	result := models.ScheduleBulkResult{ScheduleIDs: ids}
+	if result.ScheduleIDs == nil {
+		result.ScheduleIDs = []int{}
+	}
+	err := tx.QueryRow(rctx, `SELECT COUNT(*) FROM orders WHERE id_schedule = ANY($1) AND paid = true`,
+		result.ScheduleIDs).Scan(&result.PaidOrders)
+	return result, err
+}
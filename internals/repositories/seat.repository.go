@@ -25,8 +25,10 @@ func (sr *SeatRepository) GetSeats(rctx context.Context, cinemaId int) ([]models
         FROM order_seat os
         JOIN orders o ON o.id = os.id_order
         JOIN schedule sc ON sc.id = o.id_schedule
-        WHERE os.id_seats = s.id AND sc.id_cinema = $1
-    ) AS is_sold
+        JOIN studio st ON st.id = sc.id_studio
+        WHERE os.id_seats = s.id AND st.id_cinema = $1
+    ) AS is_sold,
+    s.seat_type
 FROM seats s
 ORDER BY s.codeseat ASC;
 `
@@ -40,7 +42,7 @@ ORDER BY s.codeseat ASC;
 	var seats []models.Seat
 	for rows.Next() {
 		var Seat models.Seat
-		if err := rows.Scan(&Seat.Id, &Seat.Code, &Seat.Status); err != nil {
+		if err := rows.Scan(&Seat.Id, &Seat.Code, &Seat.Status, &Seat.SeatType); err != nil {
 			return nil, err
 		}
 		seats = append(seats, Seat)
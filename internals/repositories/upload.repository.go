@@ -0,0 +1,97 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/federus1105/weekly/internals/models"
+	"github.com/federus1105/weekly/pkg/apperror"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type UploadRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewUploadRepository(db *pgxpool.Pool) *UploadRepository {
+	return &UploadRepository{db: db}
+}
+
+const uploadColumns = `id::text, id_user, purpose, object_key, image_key, status, expires_at`
+
+func scanUpload(row pgx.Row) (models.Upload, error) {
+	var upload models.Upload
+	err := row.Scan(&upload.Id, &upload.UserID, &upload.Purpose, &upload.ObjectKey,
+		&upload.ImageKey, &upload.Status, &upload.ExpiresAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.Upload{}, apperror.NotFound(apperror.CodeUploadNotFound, "")
+	}
+	return upload, err
+}
+
+// CreateUpload mencatat upload baru. Key di storage adalah objectPrefix + id upload
+// supaya file bisa ditelusuri ke barisnya.
+func (ur *UploadRepository) CreateUpload(rctx context.Context, userID int, purpose, objectPrefix string, expiresAt time.Time) (models.Upload, error) {
+	sql := `INSERT INTO uploads (id, id_user, purpose, object_key, expires_at)
+		SELECT id, $1, $2, $3 || id::text, $4 FROM (SELECT gen_random_uuid() AS id) AS u
+		RETURNING ` + uploadColumns
+	return scanUpload(ur.db.QueryRow(rctx, sql, userID, purpose, objectPrefix, expiresAt))
+}
+
+// GetUpload mengambil upload milik user
+func (ur *UploadRepository) GetUpload(rctx context.Context, id string, userID int) (models.Upload, error) {
+	sql := `SELECT ` + uploadColumns + ` FROM uploads WHERE id::text = $1 AND id_user = $2`
+	return scanUpload(ur.db.QueryRow(rctx, sql, id, userID))
+}
+
+// ConfirmUpload menandai upload pending sebagai confirmed dengan key gambar
+// hasil proses. beforeCommit dipakai untuk menulis variant ke storage.
+func (ur *UploadRepository) ConfirmUpload(rctx context.Context, id string, userID int, imageKey string, beforeCommit BeforeCommit) (models.Upload, error) {
+	tx, err := ur.db.Begin(rctx)
+	if err != nil {
+		return models.Upload{}, err
+	}
+	defer tx.Rollback(rctx)
+
+	sql := `UPDATE uploads SET status = 'confirmed', image_key = $3, expires_at = now() + interval '1 day'
+		WHERE id::text = $1 AND id_user = $2 AND status = 'pending' AND expires_at > now()
+		RETURNING ` + uploadColumns
+	upload, err := scanUpload(tx.QueryRow(rctx, sql, id, userID, imageKey))
+	if errors.Is(err, apperror.ErrNotFound) {
+		return models.Upload{}, apperror.Conflict(apperror.CodeUploadNotPending, "")
+	}
+	if err != nil {
+		return models.Upload{}, err
+	}
+
+	if err := runBeforeCommit(rctx, beforeCommit); err != nil {
+		return models.Upload{}, err
+	}
+	if err := tx.Commit(rctx); err != nil {
+		return models.Upload{}, err
+	}
+	return upload, nil
+}
+
+// ClaimUpload mengambil key gambar dari upload confirmed dan menandainya attached,
+// sehingga satu upload hanya bisa dipasang sekali
+func (ur *UploadRepository) ClaimUpload(rctx context.Context, id string, userID int, purpose string) (string, error) {
+	sql := `UPDATE uploads SET status = 'attached'
+		WHERE id::text = $1 AND id_user = $2 AND purpose = $3 AND status = 'confirmed' AND expires_at > now()
+		RETURNING image_key`
+	var imageKey string
+	err := ur.db.QueryRow(rctx, sql, id, userID, purpose).Scan(&imageKey)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", apperror.Validation(apperror.CodeUploadNotConfirmed, "").
+			WithDetails(map[string]any{"upload_id": id, "purpose": purpose})
+	}
+	return imageKey, err
+}
+
+// ReleaseUpload mengembalikan upload ke confirmed jika pemasangan gagal
+func (ur *UploadRepository) ReleaseUpload(rctx context.Context, id string) error {
+	_, err := ur.db.Exec(rctx, `UPDATE uploads SET status = 'confirmed' WHERE id::text = $1 AND status = 'attached'`, id)
+	return err
+}
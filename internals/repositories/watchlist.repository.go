@@ -0,0 +1,219 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/federus1105/weekly/internals/models"
+	"github.com/federus1105/weekly/pkg/apperror"
+	"github.com/federus1105/weekly/pkg/pagination"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type WatchlistRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewWatchlistRepository(db *pgxpool.Pool) *WatchlistRepository {
+	return &WatchlistRepository{db: db}
+}
+
+// movie yang terakhir disimpan tampil lebih dulu
+var watchlistKeyset = pagination.Keyset{Column: "w.created_at", Cast: "timestamp", IDColumn: "w.id_movie", Desc: true}
+
+const watchlistColumns = `w.id_movie, m.title, m.image, m.release_date, m.age_rating, w.notify, w.created_at`
+
+func scanWatchlistItem(row pgx.Row, w *models.WatchlistItem) error {
+	if err := row.Scan(&w.MovieID, &w.Title, &w.Image, &w.ReleaseDate, &w.AgeRating, &w.Notify, &w.CreatedAt); err != nil {
+		return err
+	}
+	w.SetImages()
+	return nil
+}
+
+func (wr *WatchlistRepository) GetWatchlist(rctx context.Context, userID int, req pagination.Request) ([]models.WatchlistItem, pagination.Meta, error) {
+	conditions := []string{"w.id_user = $1", "m.is_deleted = false"}
+	args := []any{userID}
+	if where, whereArgs := watchlistKeyset.Where(req.Cursor, len(args)+1); where != "" {
+		conditions = append(conditions, where)
+		args = append(args, whereArgs...)
+	}
+	sql := fmt.Sprintf(`
+		SELECT %s
+		FROM watchlist w
+		JOIN movies m ON m.id = w.id_movie
+		WHERE %s
+		%s
+		LIMIT $%d OFFSET $%d`,
+		watchlistColumns, strings.Join(conditions, " AND "), watchlistKeyset.OrderBy(req.Cursor), len(args)+1, len(args)+2)
+	args = append(args, req.Limit+1, req.Offset())
+
+	rows, err := wr.db.Query(rctx, sql, args...)
+	if err != nil {
+		return nil, pagination.Meta{}, err
+	}
+	defer rows.Close()
+
+	items := []models.WatchlistItem{}
+	for rows.Next() {
+		var item models.WatchlistItem
+		if err := scanWatchlistItem(rows, &item); err != nil {
+			return nil, pagination.Meta{}, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, pagination.Meta{}, err
+	}
+	items, meta := pagination.Slice(items, req, func(w models.WatchlistItem) (string, int) {
+		return w.CreatedAt.Format("2006-01-02 15:04:05.999999"), w.MovieID
+	})
+	return items, meta, nil
+}
+
+// AddToWatchlist menyimpan movie ke watchlist, jika sudah ada hanya notify yang diperbarui
+func (wr *WatchlistRepository) AddToWatchlist(rctx context.Context, userID int, body models.WatchlistBody) (models.WatchlistItem, error) {
+	var item models.WatchlistItem
+	err := scanWatchlistItem(wr.db.QueryRow(rctx, `
+		WITH w AS (
+			INSERT INTO watchlist (id_user, id_movie, notify)
+			SELECT $1, m.id, $3
+			FROM movies m
+			WHERE m.id = $2 AND m.is_deleted = false
+			ON CONFLICT (id_user, id_movie) DO UPDATE SET notify = EXCLUDED.notify
+			RETURNING *
+		)
+		SELECT `+watchlistColumns+`
+		FROM w
+		JOIN movies m ON m.id = w.id_movie`,
+		userID, body.MovieID, body.Notify), &item)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.WatchlistItem{}, apperror.NotFound(apperror.CodeMovieNotFound, "")
+	}
+	if err != nil {
+		return models.WatchlistItem{}, err
+	}
+	return item, nil
+}
+
+func (wr *WatchlistRepository) RemoveFromWatchlist(rctx context.Context, userID, movieID int) error {
+	tag, err := wr.db.Exec(rctx, `DELETE FROM watchlist WHERE id_user = $1 AND id_movie = $2`, userID, movieID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return apperror.NotFound(apperror.CodeWatchlistNotFound, "")
+	}
+	return nil
+}
+
+// notifikasi terbaru lebih dulu
+var notificationKeyset = pagination.Keyset{IDColumn: "n.id", Desc: true}
+
+const notificationColumns = `n.id, n.type, n.id_movie, m.title, n.id_location, l.name, n.id_schedule, n.read_at, n.created_at`
+
+const notificationJoins = `
+	LEFT JOIN movies m ON m.id = n.id_movie
+	LEFT JOIN location l ON l.id = n.id_location`
+
+func scanNotification(row pgx.Row, n *models.Notification) error {
+	return row.Scan(&n.Id, &n.Type, &n.MovieID, &n.Title, &n.LocationID, &n.Location, &n.ScheduleID, &n.ReadAt, &n.CreatedAt)
+}
+
+func (wr *WatchlistRepository) GetNotifications(rctx context.Context, userID int, filter models.NotificationFilter, req pagination.Request) ([]models.Notification, pagination.Meta, error) {
+	conditions := []string{"n.id_user = $1"}
+	args := []any{userID}
+	if filter.Unread {
+		conditions = append(conditions, "n.read_at IS NULL")
+	}
+	if where, whereArgs := notificationKeyset.Where(req.Cursor, len(args)+1); where != "" {
+		conditions = append(conditions, where)
+		args = append(args, whereArgs...)
+	}
+	sql := fmt.Sprintf(`
+		SELECT %s
+		FROM notifications n
+		%s
+		WHERE %s
+		%s
+		LIMIT $%d OFFSET $%d`,
+		notificationColumns, notificationJoins, strings.Join(conditions, " AND "), notificationKeyset.OrderBy(req.Cursor), len(args)+1, len(args)+2)
+	args = append(args, req.Limit+1, req.Offset())
+
+	rows, err := wr.db.Query(rctx, sql, args...)
+	if err != nil {
+		return nil, pagination.Meta{}, err
+	}
+	defer rows.Close()
+
+	notifications := []models.Notification{}
+	for rows.Next() {
+		var n models.Notification
+		if err := scanNotification(rows, &n); err != nil {
+			return nil, pagination.Meta{}, err
+		}
+		notifications = append(notifications, n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, pagination.Meta{}, err
+	}
+	notifications, meta := pagination.Slice(notifications, req, func(n models.Notification) (string, int) {
+		return "", n.Id
+	})
+	return notifications, meta, nil
+}
+
+// MarkNotificationRead menandai notifikasi milik user sebagai sudah dibaca
+func (wr *WatchlistRepository) MarkNotificationRead(rctx context.Context, userID, notificationID int) (models.Notification, error) {
+	var n models.Notification
+	err := scanNotification(wr.db.QueryRow(rctx, `
+		WITH n AS (
+			UPDATE notifications
+			SET read_at = COALESCE(read_at, now())
+			WHERE id = $1 AND id_user = $2
+			RETURNING *
+		)
+		SELECT `+notificationColumns+`
+		FROM n`+notificationJoins,
+		notificationID, userID), &n)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.Notification{}, apperror.NotFound(apperror.CodeNotifNotFound, "")
+	}
+	if err != nil {
+		return models.Notification{}, err
+	}
+	return n, nil
+}
+
+// notifyFirstSchedule membuat notifikasi untuk user yang menyimpan movie dengan
+// notify = true, jika jadwal baru adalah jadwal pertama movie di lokasi favorit user.
+// Dipanggil di dalam transaksi pembuatan jadwal.
+func notifyFirstSchedule(rctx context.Context, tx pgx.Tx, scheduleIDs []int) error {
+	_, err := tx.Exec(rctx, `
+		INSERT INTO notifications (id_user, type, id_movie, id_location, id_schedule)
+		SELECT DISTINCT ON (w.id_user, s.id_movie)
+			w.id_user, $2, s.id_movie, c.id_location, s.id
+		FROM schedule s
+		JOIN studio st ON st.id = s.id_studio
+		JOIN cinema c ON c.id = st.id_cinema
+		JOIN watchlist w ON w.id_movie = s.id_movie AND w.notify = true
+		JOIN account a ON a.user_id = w.id_user AND a.id_location = c.id_location
+		WHERE s.id = ANY($1)
+		AND NOT EXISTS (
+			SELECT 1
+			FROM schedule p
+			JOIN studio pst ON pst.id = p.id_studio
+			JOIN cinema pc ON pc.id = pst.id_cinema
+			WHERE p.id_movie = s.id_movie
+			AND pc.id_location = c.id_location
+			AND p.cancelled_at IS NULL
+			AND p.id <> ALL($1)
+		)
+		ORDER BY w.id_user, s.id_movie, s.start_at, s.id
+		ON CONFLICT (id_user, id_movie, id_location) WHERE type = 'first_schedule' DO NOTHING`,
+		scheduleIDs, models.NotificationFirstSchedule)
+	return err
+}
@@ -0,0 +1,107 @@
+package response
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/federus1105/weekly/pkg/apperror"
+	"github.com/federus1105/weekly/pkg/i18n"
+	"github.com/federus1105/weekly/pkg/validation"
+	"github.com/gin-gonic/gin"
+)
+
+type ErrorBody struct {
+	Code    apperror.Code `json:"code"`
+	Message string        `json:"message"`
+	Details any           `json:"details,omitempty"`
+}
+
+// Envelope adalah bentuk response yang sama untuk semua endpoint
+type Envelope struct {
+	Success bool       `json:"success"`
+	Message string     `json:"message,omitempty"`
+	Data    any        `json:"data,omitempty"`
+	Meta    any        `json:"meta,omitempty"`
+	Error   *ErrorBody `json:"error,omitempty"`
+}
+
+func OK(ctx *gin.Context, data any) {
+	ctx.JSON(http.StatusOK, Envelope{Success: true, Data: data})
+}
+
+func Created(ctx *gin.Context, data any) {
+	ctx.JSON(http.StatusCreated, Envelope{Success: true, Data: data})
+}
+
+// OKMessage untuk aksi yang hanya perlu konfirmasi (logout, delete, dll).
+// key adalah key katalog i18n.
+func OKMessage(ctx *gin.Context, key string, data any) {
+	ctx.JSON(http.StatusOK, Envelope{Success: true, Message: i18n.T(ctx, key), Data: data})
+}
+
+// List selalu mengirim array (bukan null) walaupun kosong
+func List[T any](ctx *gin.Context, items []T, meta any) {
+	if items == nil {
+		items = []T{}
+	}
+	ctx.JSON(http.StatusOK, Envelope{Success: true, Data: items, Meta: meta})
+}
+
+// Error menulis response error dari err. Error yang tidak dikenal dicatat
+// di log dan dikirim sebagai INTERNAL_ERROR tanpa membocorkan detailnya.
+func Error(ctx *gin.Context, err error) {
+	status, body := build(i18n.FromContext(ctx), err)
+	ctx.JSON(status, Envelope{Success: false, Error: body})
+}
+
+// Abort sama seperti Error tetapi menghentikan middleware chain
+func Abort(ctx *gin.Context, err error) {
+	status, body := build(i18n.FromContext(ctx), err)
+	ctx.AbortWithStatusJSON(status, Envelope{Success: false, Error: body})
+}
+
+func build(loc i18n.Locale, err error) (int, *ErrorBody) {
+	err = apperror.FromDB(err)
+	appErr, ok := apperror.As(err)
+	if !ok {
+		log.Println("Internal Server Error.\nCause: ", err)
+		appErr = apperror.New(nil, apperror.CodeInternal, "")
+	} else if appErr.Err != nil {
+		log.Printf("[%s] %v", appErr.Code, appErr.Err)
+	}
+	message := appErr.Message
+	if message == "" {
+		message = i18n.Message(loc, string(appErr.Code))
+	}
+	details := appErr.Details
+	if details == nil {
+		if fields := validation.Fields(appErr.Err, loc); fields != nil {
+			details = fields
+		}
+	}
+	return Status(appErr.Kind), &ErrorBody{
+		Code:    appErr.Code,
+		Message: message,
+		Details: details,
+	}
+}
+
+// Status memetakan jenis error ke HTTP status
+func Status(kind error) int {
+	switch {
+	case kind == nil:
+		return http.StatusInternalServerError
+	case errors.Is(kind, apperror.ErrBadRequest), errors.Is(kind, apperror.ErrValidation):
+		return http.StatusBadRequest
+	case errors.Is(kind, apperror.ErrUnauthorized):
+		return http.StatusUnauthorized
+	case errors.Is(kind, apperror.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(kind, apperror.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(kind, apperror.ErrConflict):
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
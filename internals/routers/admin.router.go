@@ -0,0 +1,42 @@
+package routers
+
+import (
+	"github.com/federus1105/weekly/internals/handlers"
+	"github.com/federus1105/weekly/internals/jobs"
+	"github.com/federus1105/weekly/internals/middlewares"
+	"github.com/federus1105/weekly/internals/repositories"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func InitAdminRouter(router *gin.Engine, db *pgxpool.Pool, gc *jobs.ImageGC, popularity *jobs.PopularityRefresh) {
+	adminRouter := router.Group("/admin", middlewares.VerifyToken, middlewares.Access("Admin"))
+	sh := handlers.NewStorageHandler(gc)
+	rh := handlers.NewReviewHandler(repositories.NewReviewRepository(db))
+	ph := handlers.NewPopularityHandler(popularity)
+	prh := handlers.NewPricingHandler(repositories.NewPricingRepository(db))
+	poh := handlers.NewPromoHandler(repositories.NewPromoRepository(db))
+
+	adminRouter.POST("/storage/gc", sh.CollectGarbage)
+	adminRouter.POST("/popularity/refresh", ph.RefreshPopularity)
+	adminRouter.GET("/reviews", rh.GetReviewsAdmin)
+	adminRouter.PATCH("/reviews/:id", rh.ModerateReview)
+
+	// aturan harga tiket & kalender hari libur
+	adminRouter.GET("/pricing/rules", prh.GetPriceRules)
+	adminRouter.POST("/pricing/rules", prh.CreatePriceRule)
+	adminRouter.PUT("/pricing/rules/:id", prh.UpdatePriceRule)
+	adminRouter.DELETE("/pricing/rules/:id", prh.DeletePriceRule)
+	adminRouter.GET("/pricing/holidays", prh.GetHolidays)
+	adminRouter.POST("/pricing/holidays", prh.SaveHoliday)
+	adminRouter.DELETE("/pricing/holidays/:date", prh.DeleteHoliday)
+
+	// kode promo & laporan pemakaiannya
+	adminRouter.GET("/promos", poh.GetPromos)
+	adminRouter.GET("/promos/report", poh.GetPromoReport)
+	adminRouter.GET("/promos/:id", poh.GetPromo)
+	adminRouter.GET("/promos/:id/redemptions", poh.GetPromoRedemptions)
+	adminRouter.POST("/promos", poh.CreatePromo)
+	adminRouter.PUT("/promos/:id", poh.UpdatePromo)
+	adminRouter.DELETE("/promos/:id", poh.DeletePromo)
+}
@@ -0,0 +1,27 @@
+package routers
+
+import (
+	"github.com/federus1105/weekly/internals/handlers"
+	"github.com/federus1105/weekly/internals/middlewares"
+	"github.com/federus1105/weekly/internals/repositories"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// InitCinemaRouter mendaftarkan jaringan bioskop: location -> cinema -> studio
+func InitCinemaRouter(router *gin.Engine, db *pgxpool.Pool) {
+	ch := handlers.NewCinemaHandler(repositories.NewCinemaRepository(db))
+
+	locationRouter := router.Group("/locations")
+	locationRouter.GET("", ch.GetLocations)
+	locationRouter.POST("", middlewares.VerifyToken, middlewares.Access("Admin"), ch.CreateLocation)
+
+	cinemaRouter := router.Group("/cinemas")
+	cinemaRouter.GET("", ch.GetCinemas)
+	cinemaRouter.GET("/:id", ch.GetCinema)
+	cinemaRouter.POST("", middlewares.VerifyToken, middlewares.Access("Admin"), ch.CreateCinema)
+	cinemaRouter.PATCH("/:id", middlewares.VerifyToken, middlewares.Access("Admin"), ch.UpdateCinema)
+	cinemaRouter.POST("/:id/studios", middlewares.VerifyToken, middlewares.Access("Admin"), ch.CreateStudio)
+	cinemaRouter.PATCH("/:id/studios/:studio_id", middlewares.VerifyToken, middlewares.Access("Admin"), ch.UpdateStudio)
+	cinemaRouter.DELETE("/:cinema_id/studios/:studio_id", middlewares.VerifyToken, middlewares.Access("Admin"), ch.DeleteStudio)
+}
@@ -4,15 +4,16 @@ import (
 	"github.com/federus1105/weekly/internals/handlers"
 	"github.com/federus1105/weekly/internals/middlewares"
 	"github.com/federus1105/weekly/internals/repositories"
+	"github.com/federus1105/weekly/pkg/storage"
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/redis/go-redis/v9"
 )
 
-func InitMoviesRouter(router *gin.Engine, db *pgxpool.Pool, rdb *redis.Client) {
+func InitMoviesRouter(router *gin.Engine, db *pgxpool.Pool, rdb *redis.Client, store storage.Storage) {
 	movieRouter := router.Group("/movies")
 	sr := repositories.NewMoviesRepository(db, rdb)
-	sh := handlers.NewMovieHandler(sr)
+	sh := handlers.NewMovieHandler(sr, repositories.NewUploadRepository(db), store)
 
 	movieRouter.GET("/genres/list", sh.GetAllGenres)
 	// movieRouter.GET("/genres", sh.GetMoviesByGenres)
@@ -20,9 +21,30 @@ func InitMoviesRouter(router *gin.Engine, db *pgxpool.Pool, rdb *redis.Client) {
 	movieRouter.GET("/", sh.GetAllMovie)
 	movieRouter.GET("/upcoming", sh.GetUpcomingMovies)
 	movieRouter.GET("/popular", sh.GetPopularMovies)
+	movieRouter.GET("/now-showing", sh.GetNowShowingMovies)
+	movieRouter.GET("/recommended", middlewares.VerifyToken, middlewares.Access("User", "Admin"), sh.GetRecommendedMovies)
 	movieRouter.GET("/:id", middlewares.VerifyToken, middlewares.Access("User", "Admin"), sh.GetDetailMovie)
 	movieRouter.GET("/allmovie", middlewares.VerifyToken, middlewares.Access("Admin"), sh.GetAllMovie)
 	movieRouter.DELETE("/:movie_id", middlewares.VerifyToken, middlewares.Access("Admin"), sh.DeleteMovie)
 	movieRouter.PATCH("/:id", middlewares.VerifyToken, middlewares.Access("Admin"), sh.EditMovie)
 	movieRouter.POST("/create", middlewares.VerifyToken, middlewares.Access("Admin"), sh.CreateMovie)
+
+	// galeri trailer & still
+	mh := handlers.NewMediaHandler(repositories.NewMediaRepository(db), store)
+	movieRouter.GET("/:id/media", mh.GetMovieMedia)
+	movieRouter.POST("/:id/trailers", middlewares.VerifyToken, middlewares.Access("Admin"), mh.CreateTrailer)
+	movieRouter.PATCH("/:id/trailers/:trailer_id", middlewares.VerifyToken, middlewares.Access("Admin"), mh.UpdateTrailer)
+	movieRouter.DELETE("/:movie_id/trailers/:trailer_id", middlewares.VerifyToken, middlewares.Access("Admin"), mh.DeleteTrailer)
+	movieRouter.POST("/:id/stills", middlewares.VerifyToken, middlewares.Access("Admin"), mh.CreateStill)
+	movieRouter.PUT("/:id/stills/order", middlewares.VerifyToken, middlewares.Access("Admin"), mh.ReorderStills)
+	movieRouter.PATCH("/:id/stills/:still_id", middlewares.VerifyToken, middlewares.Access("Admin"), mh.UpdateStill)
+	movieRouter.DELETE("/:movie_id/stills/:still_id", middlewares.VerifyToken, middlewares.Access("Admin"), mh.DeleteStill)
+
+	// review & rating dari user
+	rh := handlers.NewReviewHandler(repositories.NewReviewRepository(db))
+	movieRouter.GET("/:id/reviews", rh.GetReviews)
+	movieRouter.POST("/:id/reviews", middlewares.VerifyToken, middlewares.Access("User", "Admin"), rh.CreateReview)
+	movieRouter.PATCH("/:id/reviews/:review_id", middlewares.VerifyToken, middlewares.Access("User", "Admin"), rh.UpdateReview)
+	movieRouter.DELETE("/:movie_id/reviews/:review_id", middlewares.VerifyToken, middlewares.Access("User", "Admin"), rh.DeleteReview)
+	movieRouter.POST("/:id/reviews/:review_id/report", middlewares.VerifyToken, middlewares.Access("User", "Admin"), rh.ReportReview)
 }
@@ -4,15 +4,18 @@ import (
 	"github.com/federus1105/weekly/internals/handlers"
 	"github.com/federus1105/weekly/internals/middlewares"
 	"github.com/federus1105/weekly/internals/repositories"
+	"github.com/federus1105/weekly/pkg/pricing"
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-func InitOrderRouter(router *gin.Engine, db *pgxpool.Pool) {
+func InitOrderRouter(router *gin.Engine, db *pgxpool.Pool, charges pricing.Charges) {
 	orderRouter := router.Group("/order")
-	orderRepository := repositories.NewOrderRepository(db)
+	orderRepository := repositories.NewOrderRepository(db, charges)
 	OrderHandler := handlers.NewOrderHandler(orderRepository)
 
 	orderRouter.POST("", middlewares.VerifyToken, middlewares.Access("User"), middlewares.AuthMiddleware(), OrderHandler.CreateOrder)
+	orderRouter.POST("/quote", middlewares.VerifyToken, middlewares.Access("User"), middlewares.AuthMiddleware(), OrderHandler.QuoteOrder)
+	orderRouter.GET("/:id", middlewares.VerifyToken, middlewares.Access("User"), middlewares.AuthMiddleware(), OrderHandler.GetOrder)
 }
 		
\ No newline at end of file
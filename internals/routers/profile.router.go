@@ -4,15 +4,24 @@ import (
 	"github.com/federus1105/weekly/internals/handlers"
 	"github.com/federus1105/weekly/internals/middlewares"
 	"github.com/federus1105/weekly/internals/repositories"
+	"github.com/federus1105/weekly/pkg/storage"
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-func InitProfileRouter(router *gin.Engine, db *pgxpool.Pool) {
+func InitProfileRouter(router *gin.Engine, db *pgxpool.Pool, store storage.Storage) {
 	profileRouter := router.Group("/profile")
 	sr := repositories.NewProfileRepository(db)
-	sh := handlers.NewProfileHandler(sr)
+	sh := handlers.NewProfileHandler(sr, store)
 
 	profileRouter.GET("", middlewares.VerifyToken, middlewares.Access("User", "Admin"), middlewares.AuthMiddleware(), sh.GetProfile)
 	profileRouter.PATCH("/edit", middlewares.VerifyToken, middlewares.Access("Admin", "User"), middlewares.AuthMiddleware(), sh.EditProfile)
+
+	// watchlist & notifikasi jadwal
+	wh := handlers.NewWatchlistHandler(repositories.NewWatchlistRepository(db))
+	profileRouter.GET("/watchlist", middlewares.VerifyToken, middlewares.Access("User", "Admin"), middlewares.AuthMiddleware(), wh.GetWatchlist)
+	profileRouter.POST("/watchlist", middlewares.VerifyToken, middlewares.Access("User", "Admin"), middlewares.AuthMiddleware(), wh.AddToWatchlist)
+	profileRouter.DELETE("/watchlist/:movie_id", middlewares.VerifyToken, middlewares.Access("User", "Admin"), middlewares.AuthMiddleware(), wh.RemoveFromWatchlist)
+	profileRouter.GET("/notifications", middlewares.VerifyToken, middlewares.Access("User", "Admin"), middlewares.AuthMiddleware(), wh.GetNotifications)
+	profileRouter.PATCH("/notifications/:id/read", middlewares.VerifyToken, middlewares.Access("User", "Admin"), middlewares.AuthMiddleware(), wh.ReadNotification)
 }
@@ -1,10 +1,17 @@
 package routers
 
 import (
-	"net/http"
+	"log"
 
+	"github.com/federus1105/weekly/internals/handlers"
+	"github.com/federus1105/weekly/internals/jobs"
 	"github.com/federus1105/weekly/internals/middlewares"
 	"github.com/federus1105/weekly/internals/models"
+	"github.com/federus1105/weekly/internals/response"
+	"github.com/federus1105/weekly/pkg/apperror"
+	"github.com/federus1105/weekly/pkg/pricing"
+	"github.com/federus1105/weekly/pkg/storage"
+	"github.com/federus1105/weekly/pkg/validation"
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/redis/go-redis/v9"
@@ -14,11 +21,16 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
-func InitRouter(db *pgxpool.Pool, rdb *redis.Client) *gin.Engine {
+func InitRouter(db *pgxpool.Pool, rdb *redis.Client, store storage.Storage, gc *jobs.ImageGC, popularity *jobs.PopularityRefresh, charges pricing.Charges) *gin.Engine {
 	router := gin.Default()
 	router.Use(gin.Recovery())
 	router.Use(middlewares.MyLogger)
 	router.Use(middlewares.CORSMiddleware)
+	router.Use(middlewares.Locale)
+
+	if err := validation.Register(models.TimeZone(models.DefaultTimeZone)); err != nil {
+		log.Println("Failed to register validators\nCause: ", err)
+	}
 
 	docs.SwaggerInfo.BasePath = "/"
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerfiles.Handler))
@@ -31,22 +43,29 @@ func InitRouter(db *pgxpool.Pool, rdb *redis.Client) *gin.Engine {
 
 	// router.Use(cors.New(config))
 
-	router.Static("/img", "public")
+	// file lokal disajikan langsung, driver lain diarahkan ke signed URL
+	fh := handlers.NewFileHandler(store)
+	if _, ok := store.(*storage.Local); ok {
+		router.GET("/img/*key", fh.ServeLocal)
+		router.HEAD("/img/*key", fh.ServeLocal)
+	} else {
+		router.GET("/img/*key", fh.Redirect)
+	}
 
 	InitAuthRouter(router, db, rdb)
-	InitMoviesRouter(router, db, rdb)
+	InitMoviesRouter(router, db, rdb, store)
 	InitScheduleRouter(router, db, rdb)
+	InitCinemaRouter(router, db)
 	InitSeatsRouter(router, db)
-	InitProfileRouter(router, db)
-	InitOrderRouter(router, db)
+	InitProfileRouter(router, db, store)
+	InitOrderRouter(router, db, charges)
 	InitHistoryRouter(router, db)
 	InitPaymentRouter(router, db)
+	InitUploadRouter(router, db, rdb, store)
+	InitAdminRouter(router, db, gc, popularity)
 
 	router.NoRoute(func(ctx *gin.Context) {
-		ctx.JSON(http.StatusNotFound, models.Response{
-			Message: "Rute Salah",
-			Status:  "Rute Tidak Ditemukan",
-		})
+		response.Error(ctx, apperror.NotFound(apperror.CodeRouteNotFound, ""))
 	})
 	return router
 
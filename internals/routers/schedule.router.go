@@ -15,6 +15,16 @@ func InitScheduleRouter(router *gin.Engine, db *pgxpool.Pool, rdb *redis.Client)
 	sr := repositories.NewScheduleRepository(db, rdb)
 	sh := handlers.NewScheduleHandler(sr)
 
+	scheduleRouter.GET("/search", sh.SearchShowtimes)
 	scheduleRouter.GET("/:id_movie", middlewares.VerifyToken, middlewares.Access("User", "Admin"), sh.GetSchedule)
 	scheduleRouter.POST("/create", middlewares.VerifyToken, middlewares.Access("Admin"), sh.CreateSchedule)
+
+	// template jadwal berulang
+	templateRouter := scheduleRouter.Group("/templates", middlewares.VerifyToken, middlewares.Access("Admin"))
+	templateRouter.GET("", sh.GetScheduleTemplates)
+	templateRouter.GET("/:id", sh.GetScheduleTemplate)
+	templateRouter.POST("", sh.CreateScheduleTemplate)
+	templateRouter.POST("/preview", sh.PreviewScheduleTemplate)
+	templateRouter.POST("/:id/cancel", sh.CancelTemplateSchedules)
+	templateRouter.POST("/:id/shift", sh.ShiftTemplateSchedules)
 }
@@ -0,0 +1,30 @@
+package routers
+
+import (
+	"github.com/federus1105/weekly/internals/handlers"
+	"github.com/federus1105/weekly/internals/middlewares"
+	"github.com/federus1105/weekly/internals/repositories"
+	"github.com/federus1105/weekly/pkg/storage"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+)
+
+func InitUploadRouter(router *gin.Engine, db *pgxpool.Pool, rdb *redis.Client, store storage.Storage) {
+	uploadRouter := router.Group("/uploads")
+	uh := handlers.NewUploadHandler(
+		repositories.NewUploadRepository(db),
+		repositories.NewMoviesRepository(db, rdb),
+		repositories.NewProfileRepository(db),
+		store,
+	)
+
+	uploadRouter.POST("", middlewares.VerifyToken, middlewares.Access("User", "Admin"), middlewares.AuthMiddleware(), uh.CreateUpload)
+	uploadRouter.POST("/:id/confirm", middlewares.VerifyToken, middlewares.Access("User", "Admin"), middlewares.AuthMiddleware(), uh.ConfirmUpload)
+
+	// driver lokal menerima upload langsung di server ini
+	if _, ok := store.(*storage.Local); ok {
+		fh := handlers.NewFileHandler(store)
+		uploadRouter.PUT("/local/*key", fh.LocalUpload)
+	}
+}
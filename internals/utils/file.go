@@ -1,43 +1,177 @@
 package utils
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"log"
 	"mime/multipart"
-	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/federus1105/weekly/pkg/apperror"
+	"github.com/federus1105/weekly/pkg/imaging"
+	"github.com/federus1105/weekly/pkg/storage"
 )
 
-// maksimal ukuran file
+// maksimal ukuran file yang dikirim lewat multipart
 const MaxFileSize = 500 * 1024
 
-// tipe file yang di perbolehkan
-var allowedExtensions = map[string]bool{
-	".jpg":  true,
-	".jpeg": true,
-	".png":  true,
-	".webp": true,
+// maksimal ukuran file yang diupload langsung ke storage lewat presigned URL
+const MaxDirectUploadSize = 10 * 1024 * 1024
+
+// UploadStagingPrefix adalah prefix key untuk file mentah dari presigned upload.
+// File di sini belum divalidasi dan tidak boleh disajikan ke publik.
+const UploadStagingPrefix = "uploads/"
+
+// ReadUpload membaca file mentah hasil presigned upload dengan batas ukuran
+func ReadUpload(ctx context.Context, store storage.Storage, key string) ([]byte, error) {
+	src, err := store.Get(ctx, key)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, apperror.Validation(apperror.CodeUploadMissing, "")
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+
+	data, err := io.ReadAll(io.LimitReader(src, MaxDirectUploadSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > MaxDirectUploadSize {
+		return nil, FileTooLarge(MaxDirectUploadSize)
+	}
+	return data, nil
 }
 
-func UploadImageFile(ctx context.Context, file *multipart.FileHeader, uploadDir string, prefix string) (string, string, error) {
+// variantNames adalah semua variant yang dibuat imaging.Process
+var variantNames = []string{imaging.Original, imaging.Thumbnail, imaging.Medium, imaging.Large, imaging.WebP}
+
+// PendingImage adalah gambar upload yang sudah divalidasi dan diproses
+// tetapi belum ditulis ke storage. Key sudah final sehingga bisa disimpan
+// ke database lebih dulu, lalu Store dipanggil sebelum transaksi di-commit.
+type PendingImage struct {
+	Key      string
+	variants []imaging.Variant
+}
+
+// PrepareImage memvalidasi dan memproses gambar upload menjadi semua variant di preset.
+// Mengembalikan nil jika file kosong.
+func PrepareImage(file *multipart.FileHeader, prefix string, preset imaging.Preset) (*PendingImage, error) {
 	if file == nil {
-		return "", "", errors.New("file tidak ditemukan")
+		return nil, nil
 	}
 
-	ext := strings.ToLower(filepath.Ext(file.Filename))
-	if !allowedExtensions[ext] {
-		return "", "", errors.New("format file tidak didukung (hanya jpg, jpeg, png)")
+	if file.Size > MaxFileSize {
+		return nil, FileTooLarge(MaxFileSize)
 	}
 
-	if file.Size > MaxFileSize {
-		return "", "", errors.New("ukuran file maksimal 500 kb")
+	src, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("open upload: %w", err)
 	}
+	defer src.Close()
 
-	filename := fmt.Sprintf("%d_%s%s", time.Now().UnixNano(), prefix, ext)
-	savePath := filepath.Join(uploadDir, filename)
+	return ProcessImage(src, prefix, preset)
+}
 
-	return savePath, filename, nil
+// ProcessImage memproses isi gambar dari r. Tipe file ditentukan dari isi,
+// bukan dari nama file.
+func ProcessImage(r io.Reader, prefix string, preset imaging.Preset) (*PendingImage, error) {
+	variants, err := imaging.Process(r, preset)
+	switch {
+	case errors.Is(err, imaging.ErrUnsupported):
+		return nil, apperror.Validation(apperror.CodeInvalidFile, "")
+	case errors.Is(err, imaging.ErrCorrupt):
+		return nil, apperror.Validation(apperror.CodeCorruptImage, "").Wrap(err)
+	case errors.Is(err, imaging.ErrTooManyPixels):
+		return nil, apperror.Validation(apperror.CodeImageTooLarge, "")
+	case err != nil:
+		return nil, fmt.Errorf("process image: %w", err)
+	}
+
+	key := fmt.Sprintf("%d_%s%s", time.Now().UnixNano(), prefix, variants[0].Ext)
+	return &PendingImage{Key: key, variants: variants}, nil
+}
+
+// FileTooLarge membuat error validasi ukuran file beserta batas maksimalnya
+func FileTooLarge(max int64) error {
+	return apperror.Validation(apperror.CodeFileTooLarge, "").
+		WithDetails(map[string]any{"max_size": max})
 }
 
+// KeyPtr mengembalikan pointer ke key, nil jika tidak ada gambar
+func (p *PendingImage) KeyPtr() *string {
+	if p == nil {
+		return nil
+	}
+	return &p.Key
+}
+
+// StoreImages menulis semua variant ke storage. Jika salah satu gagal,
+// semua yang sudah tertulis dihapus lagi. Image nil dilewati.
+func StoreImages(ctx context.Context, store storage.Storage, images ...*PendingImage) error {
+	var stored []string
+	for _, img := range images {
+		if img == nil {
+			continue
+		}
+		for _, v := range img.variants {
+			key := imaging.VariantKey(img.Key, v.Name)
+			if err := store.Put(ctx, key, bytes.NewReader(v.Data), int64(len(v.Data)), v.ContentType); err != nil {
+				deleteKeys(store, stored)
+				return fmt.Errorf("store %s: %w", key, err)
+			}
+			stored = append(stored, key)
+		}
+	}
+	return nil
+}
+
+// DiscardImages menghapus variant dari image yang gagal dipakai
+// (misal transaksi database gagal setelah Store). Image nil dilewati.
+func DiscardImages(store storage.Storage, images ...*PendingImage) {
+	for _, img := range images {
+		if img != nil {
+			DeleteImage(store, img.Key)
+		}
+	}
+}
+
+// DeleteImage menghapus file original beserta semua variant-nya.
+// Gagal hapus hanya dicatat, sisanya akan dibersihkan oleh garbage collector.
+func DeleteImage(store storage.Storage, key string) {
+	if key == "" {
+		return
+	}
+	deleteKeys(store, ImageKeys(key))
+}
+
+// DeleteObject menghapus satu file, misal file mentah hasil presigned upload
+func DeleteObject(store storage.Storage, key string) {
+	deleteKeys(store, []string{key})
+}
+
+// ImageKeys mengembalikan key original beserta semua variant-nya
+func ImageKeys(key string) []string {
+	key = strings.TrimPrefix(key, "/")
+	keys := make([]string, 0, len(variantNames))
+	for _, name := range variantNames {
+		keys = append(keys, imaging.VariantKey(key, name))
+	}
+	return keys
+}
+
+func deleteKeys(store storage.Storage, keys []string) {
+	// dipanggil saat cleanup, jadi tidak ikut dibatalkan oleh context request
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	for _, key := range keys {
+		if err := store.Delete(ctx, key); err != nil {
+			log.Println("Failed to delete file", key, "\nCause:", err)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package apperror
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Jenis error yang dipetakan ke HTTP status oleh package response
+var (
+	ErrBadRequest   = errors.New("bad request")
+	ErrValidation   = errors.New("validation failed")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+)
+
+// Error membawa kode yang stabil untuk client beserta penyebab aslinya.
+// Message hanya diisi jika pesan dari katalog i18n perlu diganti.
+type Error struct {
+	Kind    error
+	Code    Code
+	Message string
+	Details any
+	Err     error
+}
+
+func (e *Error) Error() string {
+	msg := string(e.Code)
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", msg, e.Err)
+	}
+	return msg
+}
+
+func (e *Error) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+func (e *Error) WithDetails(details any) *Error {
+	e.Details = details
+	return e
+}
+
+func (e *Error) Wrap(err error) *Error {
+	e.Err = err
+	return e
+}
+
+func New(kind error, code Code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+func BadRequest(code Code, message string) *Error {
+	return New(ErrBadRequest, code, message)
+}
+
+func Validation(code Code, message string) *Error {
+	return New(ErrValidation, code, message)
+}
+
+func Unauthorized(code Code, message string) *Error {
+	return New(ErrUnauthorized, code, message)
+}
+
+func Forbidden(code Code, message string) *Error {
+	return New(ErrForbidden, code, message)
+}
+
+func NotFound(code Code, message string) *Error {
+	return New(ErrNotFound, code, message)
+}
+
+func Conflict(code Code, message string) *Error {
+	return New(ErrConflict, code, message)
+}
+
+// As mengambil *Error dari rantai error
+func As(err error) (*Error, bool) {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr, true
+	}
+	return nil, false
+}
@@ -0,0 +1,82 @@
+package apperror
+
+// Code adalah kode error yang stabil dan bisa dibaca mesin.
+// Pesan untuk tiap kode ada di katalog pkg/i18n.
+type Code string
+
+const (
+	CodeBadRequest         Code = "BAD_REQUEST"
+	CodeValidation         Code = "VALIDATION_FAILED"
+	CodeUnauthorized       Code = "UNAUTHORIZED"
+	CodeTokenMissing       Code = "TOKEN_MISSING"
+	CodeTokenInvalid       Code = "TOKEN_INVALID"
+	CodeTokenExpired       Code = "TOKEN_EXPIRED"
+	CodeForbidden          Code = "FORBIDDEN"
+	CodeNotFound           Code = "NOT_FOUND"
+	CodeRouteNotFound      Code = "ROUTE_NOT_FOUND"
+	CodeConflict           Code = "CONFLICT"
+	CodeInternal           Code = "INTERNAL_ERROR"
+	CodeInvalidCredentials Code = "INVALID_CREDENTIALS"
+	CodeWrongPassword      Code = "WRONG_PASSWORD"
+	CodeEmailTaken         Code = "EMAIL_ALREADY_REGISTERED"
+	CodeInvalidID          Code = "INVALID_ID"
+	CodeInvalidCursor      Code = "INVALID_CURSOR"
+	CodeInvalidFilter      Code = "INVALID_FILTER"
+	CodeInvalidSort        Code = "INVALID_SORT"
+	CodeInvalidSortOrder   Code = "INVALID_SORT_ORDER"
+	CodeGenreRequired      Code = "GENRE_REQUIRED"
+	CodeScheduleMismatch   Code = "SCHEDULE_LENGTH_MISMATCH"
+	CodeFileRequired       Code = "FILE_REQUIRED"
+	CodeInvalidFile        Code = "INVALID_FILE"
+	CodeFileTooLarge       Code = "FILE_TOO_LARGE"
+	CodeCorruptImage       Code = "CORRUPT_IMAGE"
+	CodeImageTooLarge      Code = "IMAGE_DIMENSIONS_TOO_LARGE"
+	CodeUploadNotFound     Code = "UPLOAD_NOT_FOUND"
+	CodeUploadMissing      Code = "UPLOAD_OBJECT_MISSING"
+	CodeUploadNotPending   Code = "UPLOAD_NOT_PENDING"
+	CodeUploadNotConfirmed Code = "UPLOAD_NOT_CONFIRMED"
+	CodeNoFieldsToUpdate   Code = "NO_FIELDS_TO_UPDATE"
+	CodeReferenceNotFound  Code = "REFERENCE_NOT_FOUND"
+	CodeUserNotFound       Code = "USER_NOT_FOUND"
+	CodeMovieNotFound      Code = "MOVIE_NOT_FOUND"
+	CodeScheduleNotFound   Code = "SCHEDULE_NOT_FOUND"
+	CodeSeatNotFound       Code = "SEAT_NOT_FOUND"
+	CodeSeatUnavailable    Code = "SEAT_UNAVAILABLE"
+	CodeAgeUnverified      Code = "AGE_VERIFICATION_REQUIRED"
+	CodeAgeRestricted      Code = "AGE_RESTRICTED"
+	CodeTrailerNotFound    Code = "TRAILER_NOT_FOUND"
+	CodeStillNotFound      Code = "STILL_NOT_FOUND"
+	CodeInvalidStillOrder  Code = "INVALID_STILL_ORDER"
+	CodeReviewNotFound     Code = "REVIEW_NOT_FOUND"
+	CodeReviewNotAllowed   Code = "REVIEW_NOT_ALLOWED"
+	CodeReviewExists       Code = "REVIEW_ALREADY_EXISTS"
+	CodeReviewReported     Code = "REVIEW_ALREADY_REPORTED"
+	CodeWatchlistNotFound  Code = "WATCHLIST_NOT_FOUND"
+	CodeNotifNotFound      Code = "NOTIFICATION_NOT_FOUND"
+	CodeCinemaNotFound     Code = "CINEMA_NOT_FOUND"
+	CodeStudioNotFound     Code = "STUDIO_NOT_FOUND"
+	CodeStudioExists       Code = "STUDIO_ALREADY_EXISTS"
+	CodeStudioInUse        Code = "STUDIO_IN_USE"
+	CodeScheduleConflict   Code = "SCHEDULE_CONFLICT"
+	CodeScheduleCancelled  Code = "SCHEDULE_CANCELLED"
+	CodeScheduleStarted    Code = "SCHEDULE_STARTED"
+	CodeTemplateNotFound   Code = "TEMPLATE_NOT_FOUND"
+	CodeInvalidDateRange   Code = "INVALID_DATE_RANGE"
+	CodeTemplateTooLarge   Code = "TEMPLATE_TOO_LARGE"
+	CodeTemplateEmpty      Code = "TEMPLATE_EMPTY"
+	CodePriceRuleNotFound  Code = "PRICE_RULE_NOT_FOUND"
+	CodeInvalidPriceRule   Code = "INVALID_PRICE_RULE"
+	CodeHolidayNotFound    Code = "HOLIDAY_NOT_FOUND"
+	CodeCategoryUnverified Code = "CATEGORY_UNVERIFIED"
+	CodeCategoryDenied     Code = "CATEGORY_NOT_ELIGIBLE"
+	CodeOrderNotFound      Code = "ORDER_NOT_FOUND"
+	CodePromoNotFound      Code = "PROMO_NOT_FOUND"
+	CodeInvalidPromo       Code = "INVALID_PROMO"
+	CodePromoCodeTaken     Code = "PROMO_CODE_TAKEN"
+	CodePromoInUse         Code = "PROMO_IN_USE"
+	CodePromoExpired       Code = "PROMO_EXPIRED"
+	CodePromoMinSpend      Code = "PROMO_MIN_SPEND_NOT_MET"
+	CodePromoNotApplicable Code = "PROMO_NOT_APPLICABLE"
+	CodePromoExhausted     Code = "PROMO_USAGE_EXHAUSTED"
+	CodePromoUserLimit     Code = "PROMO_USER_LIMIT_REACHED"
+)
@@ -0,0 +1,45 @@
+package apperror
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// kode SQLSTATE postgres yang dipetakan
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+	pgNotNullViolation    = "23502"
+	pgCheckViolation      = "23514"
+	pgInvalidText         = "22P02"
+	pgInvalidDatetime     = "22007"
+	pgDatetimeOverflow    = "22008"
+)
+
+// FromDB memetakan error pgx/postgres ke *Error. Error lain dikembalikan apa adanya.
+func FromDB(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := As(err); ok {
+		return err
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		return NotFound(CodeNotFound, "").Wrap(err)
+	}
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+	switch pgErr.Code {
+	case pgUniqueViolation:
+		return Conflict(CodeConflict, "").Wrap(err)
+	case pgForeignKeyViolation:
+		return Validation(CodeReferenceNotFound, "").Wrap(err)
+	case pgNotNullViolation, pgCheckViolation, pgInvalidText, pgInvalidDatetime, pgDatetimeOverflow:
+		return Validation(CodeValidation, "").Wrap(err)
+	}
+	return err
+}
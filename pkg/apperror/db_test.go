@@ -0,0 +1,73 @@
+package apperror
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestFromDB(t *testing.T) {
+	pgErr := func(code string) error {
+		return &pgconn.PgError{Code: code, Message: "pg error " + code}
+	}
+	tests := []struct {
+		name string
+		err  error
+		kind error
+		code Code
+	}{
+		{"no rows", pgx.ErrNoRows, ErrNotFound, CodeNotFound},
+		{"wrapped no rows", fmt.Errorf("scan: %w", pgx.ErrNoRows), ErrNotFound, CodeNotFound},
+		{"unique violation", pgErr("23505"), ErrConflict, CodeConflict},
+		{"foreign key violation", pgErr("23503"), ErrValidation, CodeReferenceNotFound},
+		{"not null violation", pgErr("23502"), ErrValidation, CodeValidation},
+		{"check violation", pgErr("23514"), ErrValidation, CodeValidation},
+		{"invalid text representation", pgErr("22P02"), ErrValidation, CodeValidation},
+		{"invalid datetime format", pgErr("22007"), ErrValidation, CodeValidation},
+		{"datetime overflow", pgErr("22008"), ErrValidation, CodeValidation},
+		{"wrapped unique violation", fmt.Errorf("insert: %w", pgErr("23505")), ErrConflict, CodeConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FromDB(tt.err)
+			appErr, ok := As(got)
+			if !ok {
+				t.Fatalf("FromDB = %v, want *Error", got)
+			}
+			if appErr.Kind != tt.kind || appErr.Code != tt.code {
+				t.Errorf("FromDB = %v/%s, want %v/%s", appErr.Kind, appErr.Code, tt.kind, tt.code)
+			}
+			// penyebab asli tetap bisa diperiksa
+			if !errors.Is(got, tt.kind) || !errors.Is(got, tt.err) {
+				t.Errorf("FromDB(%v) does not wrap kind and cause", tt.err)
+			}
+		})
+	}
+}
+
+func TestFromDBPassThrough(t *testing.T) {
+	plain := errors.New("connection refused")
+	deadlock := &pgconn.PgError{Code: "40P01"}
+	existing := Conflict(CodeSeatUnavailable, "")
+
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"plain error", plain},
+		{"unmapped sqlstate", deadlock},
+		{"already app error", existing},
+		{"wrapped app error", fmt.Errorf("order: %w", existing)},
+	}
+	for _, tt := range tests {
+		if got := FromDB(tt.err); got != tt.err {
+			t.Errorf("%s: FromDB = %v, want unchanged %v", tt.name, got, tt.err)
+		}
+	}
+	if FromDB(nil) != nil {
+		t.Error("FromDB(nil) != nil")
+	}
+}
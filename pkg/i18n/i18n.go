@@ -0,0 +1,74 @@
+package i18n
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/text/language"
+)
+
+// Locale adalah bahasa yang didukung oleh API
+type Locale string
+
+const (
+	ID Locale = "id"
+	EN Locale = "en"
+
+	// Default dipakai jika Accept-Language kosong atau tidak dikenali
+	Default = ID
+
+	// ContextKey adalah key gin context tempat middleware menyimpan locale
+	ContextKey = "locale"
+)
+
+// urutan harus sama dengan tag di matcher
+var supported = []Locale{ID, EN}
+
+var matcher = language.NewMatcher([]language.Tag{
+	language.Indonesian,
+	language.English,
+})
+
+var catalogs = map[Locale]map[string]string{
+	ID: messagesID,
+	EN: messagesEN,
+}
+
+// Parse memilih locale terbaik dari header Accept-Language
+func Parse(acceptLanguage string) Locale {
+	if acceptLanguage == "" {
+		return Default
+	}
+	_, idx := language.MatchStrings(matcher, acceptLanguage)
+	return supported[idx]
+}
+
+// FromContext mengambil locale yang di-set oleh middleware Locale
+func FromContext(ctx *gin.Context) Locale {
+	if v, ok := ctx.Get(ContextKey); ok {
+		if loc, ok := v.(Locale); ok {
+			return loc
+		}
+	}
+	return Parse(ctx.GetHeader("Accept-Language"))
+}
+
+// Message mengambil pesan dari katalog, fallback ke locale default lalu ke key itu sendiri
+func Message(loc Locale, key string, args ...any) string {
+	msg, ok := catalogs[loc][key]
+	if !ok {
+		msg, ok = catalogs[Default][key]
+	}
+	if !ok {
+		return key
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(msg, args...)
+	}
+	return msg
+}
+
+// T adalah shortcut Message dengan locale dari request
+func T(ctx *gin.Context, key string, args ...any) string {
+	return Message(FromContext(ctx), key, args...)
+}
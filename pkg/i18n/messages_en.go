@@ -0,0 +1,95 @@
+package i18n
+
+var messagesEN = map[string]string{
+	// error
+	"BAD_REQUEST":                "Bad request",
+	"VALIDATION_FAILED":          "Request validation failed",
+	"UNAUTHORIZED":               "Unauthorized",
+	"TOKEN_MISSING":              "Please log in first",
+	"TOKEN_INVALID":              "Invalid token, please log in again",
+	"TOKEN_EXPIRED":              "Session expired, please log in again",
+	"FORBIDDEN":                  "You do not have access to this resource",
+	"NOT_FOUND":                  "Resource not found",
+	"ROUTE_NOT_FOUND":            "Route not found",
+	"CONFLICT":                   "Resource already exists",
+	"INTERNAL_ERROR":             "Internal server error",
+	"INVALID_CREDENTIALS":        "Wrong email or password",
+	"WRONG_PASSWORD":             "Old password does not match",
+	"EMAIL_ALREADY_REGISTERED":   "Email is already registered",
+	"INVALID_ID":                 "Invalid ID",
+	"INVALID_CURSOR":             "Invalid cursor",
+	"INVALID_FILTER":             "Invalid filter",
+	"INVALID_SORT":               "sort must be one of title, release_date, rating, popularity",
+	"INVALID_SORT_ORDER":         "order must be asc or desc",
+	"GENRE_REQUIRED":             "No genre IDs provided",
+	"SCHEDULE_LENGTH_MISMATCH":   "date[], id_studio[] and start_time[] must have the same length",
+	"FILE_REQUIRED":              "File is required",
+	"INVALID_FILE":               "Only jpeg, png and webp images are allowed",
+	"FILE_TOO_LARGE":             "File is too large",
+	"CORRUPT_IMAGE":              "Image file is corrupt or does not match its format",
+	"IMAGE_DIMENSIONS_TOO_LARGE": "Image dimensions are too large",
+	"UPLOAD_NOT_FOUND":           "Upload not found",
+	"UPLOAD_OBJECT_MISSING":      "File has not been uploaded yet",
+	"UPLOAD_NOT_PENDING":         "Upload has already been confirmed or has expired",
+	"UPLOAD_NOT_CONFIRMED":       "Upload is not confirmed, already used or has expired",
+	"NO_FIELDS_TO_UPDATE":        "No fields to update",
+	"REFERENCE_NOT_FOUND":        "Referenced data not found",
+	"USER_NOT_FOUND":             "User not found",
+	"MOVIE_NOT_FOUND":            "Movie not found",
+	"SCHEDULE_NOT_FOUND":         "Schedule not found",
+	"SEAT_NOT_FOUND":             "Seat not found",
+	"SEAT_UNAVAILABLE":           "Seat is already booked",
+	"AGE_VERIFICATION_REQUIRED":  "This film has an age restriction, please add your date of birth to your profile first",
+	"AGE_RESTRICTED":             "You do not meet the minimum age for this film",
+	"TRAILER_NOT_FOUND":          "Trailer not found",
+	"STILL_NOT_FOUND":            "Still not found",
+	"INVALID_STILL_ORDER":        "ids must contain every still of the movie exactly once",
+	"REVIEW_NOT_FOUND":           "Review not found",
+	"REVIEW_NOT_ALLOWED":         "Only users with a paid order for this movie can write a review",
+	"REVIEW_ALREADY_EXISTS":      "You have already reviewed this movie",
+	"REVIEW_ALREADY_REPORTED":    "You have already reported this review",
+	"WATCHLIST_NOT_FOUND":        "Movie is not in your watchlist",
+	"NOTIFICATION_NOT_FOUND":     "Notification not found",
+	"CINEMA_NOT_FOUND":           "Cinema not found",
+	"STUDIO_NOT_FOUND":           "Studio not found",
+	"STUDIO_ALREADY_EXISTS":      "A studio with this name already exists in the cinema",
+	"STUDIO_IN_USE":              "Studio already has schedules and cannot be deleted",
+	"SCHEDULE_CONFLICT":          "Some schedules could not be created, see details",
+	"SCHEDULE_CANCELLED":         "This schedule has been cancelled",
+	"SCHEDULE_STARTED":           "This showtime has already started, tickets are no longer sold",
+	"TEMPLATE_NOT_FOUND":         "Schedule template not found",
+	"INVALID_DATE_RANGE":         "End date must not be before start date",
+	"TEMPLATE_TOO_LARGE":         "Template produces too many schedules, narrow the date range",
+	"TEMPLATE_EMPTY":             "Template does not produce any schedule",
+	"PRICE_RULE_NOT_FOUND":       "Price rule not found",
+	"INVALID_PRICE_RULE":         "set amount must not be negative, percent amount must be at least -100 and start_time must differ from end_time",
+	"HOLIDAY_NOT_FOUND":          "Holiday not found",
+	"CATEGORY_UNVERIFIED":        "Child and senior tickets require a date of birth in your profile",
+	"CATEGORY_NOT_ELIGIBLE":      "Your age does not match the selected ticket category",
+	"ORDER_NOT_FOUND":            "Order not found",
+	"PROMO_NOT_FOUND":            "Promo code not found",
+	"INVALID_PROMO":              "Invalid promo settings",
+	"PROMO_CODE_TAKEN":           "Promo code is already used by another promo",
+	"PROMO_IN_USE":               "Promo has been redeemed, deactivate it instead",
+	"PROMO_EXPIRED":              "Promo code is not valid at this time",
+	"PROMO_MIN_SPEND_NOT_MET":    "Order does not reach the promo minimum spend",
+	"PROMO_NOT_APPLICABLE":       "Promo code does not apply to this order",
+	"PROMO_USAGE_EXHAUSTED":      "Promo code has reached its usage limit",
+	"PROMO_USER_LIMIT_REACHED":   "You have used this promo code the maximum number of times",
+
+	// sukses
+	"MOVIE_DELETED":       "Movie deleted",
+	"PASSWORD_RESET":      "Password has been reset",
+	"LOGGED_OUT":          "Logged out",
+	"TRAILER_DELETED":     "Trailer deleted",
+	"REVIEW_DELETED":      "Review deleted",
+	"REVIEW_REPORTED":     "Review reported, thank you",
+	"STILL_DELETED":       "Still deleted",
+	"WATCHLIST_REMOVED":   "Movie removed from watchlist",
+	"STUDIO_DELETED":      "Studio deleted",
+	"SCHEDULES_CANCELLED": "Schedules cancelled",
+	"SCHEDULES_SHIFTED":   "Schedules shifted",
+	"PRICE_RULE_DELETED":  "Price rule deleted",
+	"HOLIDAY_DELETED":     "Holiday deleted",
+	"PROMO_DELETED":       "Promo deleted",
+}
@@ -0,0 +1,95 @@
+package i18n
+
+var messagesID = map[string]string{
+	// error
+	"BAD_REQUEST":                "Permintaan tidak valid",
+	"VALIDATION_FAILED":          "Validasi data gagal",
+	"UNAUTHORIZED":               "Tidak memiliki otorisasi",
+	"TOKEN_MISSING":              "Silahkan login terlebih dahulu",
+	"TOKEN_INVALID":              "Token tidak valid, silahkan login kembali",
+	"TOKEN_EXPIRED":              "Sesi telah berakhir, silahkan login kembali",
+	"FORBIDDEN":                  "Anda tidak memiliki akses ke resource ini",
+	"NOT_FOUND":                  "Data tidak ditemukan",
+	"ROUTE_NOT_FOUND":            "Rute salah",
+	"CONFLICT":                   "Data sudah ada",
+	"INTERNAL_ERROR":             "Terjadi kesalahan pada server",
+	"INVALID_CREDENTIALS":        "Email atau password salah",
+	"WRONG_PASSWORD":             "Password lama tidak sesuai",
+	"EMAIL_ALREADY_REGISTERED":   "Email sudah terdaftar",
+	"INVALID_ID":                 "ID tidak valid",
+	"INVALID_CURSOR":             "Cursor tidak valid",
+	"INVALID_FILTER":             "Filter tidak valid",
+	"INVALID_SORT":               "sort harus salah satu dari title, release_date, rating, popularity",
+	"INVALID_SORT_ORDER":         "order harus asc atau desc",
+	"GENRE_REQUIRED":             "ID genre belum diisi",
+	"SCHEDULE_LENGTH_MISMATCH":   "Jumlah date[], id_studio[] dan start_time[] harus sama",
+	"FILE_REQUIRED":              "File wajib diisi",
+	"INVALID_FILE":               "Hanya gambar jpeg, png dan webp yang diperbolehkan",
+	"FILE_TOO_LARGE":             "Ukuran file terlalu besar",
+	"CORRUPT_IMAGE":              "File gambar rusak atau tidak sesuai formatnya",
+	"IMAGE_DIMENSIONS_TOO_LARGE": "Dimensi gambar terlalu besar",
+	"UPLOAD_NOT_FOUND":           "Upload tidak ditemukan",
+	"UPLOAD_OBJECT_MISSING":      "File belum diupload",
+	"UPLOAD_NOT_PENDING":         "Upload sudah dikonfirmasi atau sudah kedaluwarsa",
+	"UPLOAD_NOT_CONFIRMED":       "Upload belum dikonfirmasi, sudah dipakai atau sudah kedaluwarsa",
+	"NO_FIELDS_TO_UPDATE":        "Tidak ada data yang diubah",
+	"REFERENCE_NOT_FOUND":        "Data referensi tidak ditemukan",
+	"USER_NOT_FOUND":             "User tidak ditemukan",
+	"MOVIE_NOT_FOUND":            "Film tidak ditemukan",
+	"SCHEDULE_NOT_FOUND":         "Jadwal tidak ditemukan",
+	"SEAT_NOT_FOUND":             "Kursi tidak ditemukan",
+	"SEAT_UNAVAILABLE":           "Kursi sudah dipesan",
+	"AGE_VERIFICATION_REQUIRED":  "Film ini memiliki batas usia, lengkapi tanggal lahir di profil terlebih dahulu",
+	"AGE_RESTRICTED":             "Usia Anda belum memenuhi batas usia film ini",
+	"TRAILER_NOT_FOUND":          "Trailer tidak ditemukan",
+	"STILL_NOT_FOUND":            "Still tidak ditemukan",
+	"INVALID_STILL_ORDER":        "ids harus berisi semua still movie tepat satu kali",
+	"REVIEW_NOT_FOUND":           "Review tidak ditemukan",
+	"REVIEW_NOT_ALLOWED":         "Hanya user yang sudah membeli tiket film ini yang bisa memberi review",
+	"REVIEW_ALREADY_EXISTS":      "Anda sudah memberi review untuk film ini",
+	"REVIEW_ALREADY_REPORTED":    "Anda sudah melaporkan review ini",
+	"WATCHLIST_NOT_FOUND":        "Film tidak ada di watchlist Anda",
+	"NOTIFICATION_NOT_FOUND":     "Notifikasi tidak ditemukan",
+	"CINEMA_NOT_FOUND":           "Cinema tidak ditemukan",
+	"STUDIO_NOT_FOUND":           "Studio tidak ditemukan",
+	"STUDIO_ALREADY_EXISTS":      "Nama studio sudah dipakai di cinema ini",
+	"STUDIO_IN_USE":              "Studio sudah memiliki jadwal dan tidak bisa dihapus",
+	"SCHEDULE_CONFLICT":          "Sebagian jadwal tidak bisa dibuat, lihat detail",
+	"SCHEDULE_CANCELLED":         "Jadwal ini sudah dibatalkan",
+	"SCHEDULE_STARTED":           "Jadwal ini sudah dimulai, tiket tidak lagi dijual",
+	"TEMPLATE_NOT_FOUND":         "Template jadwal tidak ditemukan",
+	"INVALID_DATE_RANGE":         "Tanggal akhir tidak boleh sebelum tanggal mulai",
+	"TEMPLATE_TOO_LARGE":         "Template menghasilkan terlalu banyak jadwal, persempit rentang tanggal",
+	"TEMPLATE_EMPTY":             "Template tidak menghasilkan jadwal apa pun",
+	"PRICE_RULE_NOT_FOUND":       "Aturan harga tidak ditemukan",
+	"INVALID_PRICE_RULE":         "amount untuk set tidak boleh negatif, amount untuk percent minimal -100 dan start_time harus berbeda dengan end_time",
+	"HOLIDAY_NOT_FOUND":          "Hari libur tidak ditemukan",
+	"CATEGORY_UNVERIFIED":        "Tiket anak dan lansia membutuhkan tanggal lahir di profil",
+	"CATEGORY_NOT_ELIGIBLE":      "Usia kamu tidak sesuai dengan kategori tiket yang dipilih",
+	"ORDER_NOT_FOUND":            "Order tidak ditemukan",
+	"PROMO_NOT_FOUND":            "Kode promo tidak ditemukan",
+	"INVALID_PROMO":              "Pengaturan promo tidak valid",
+	"PROMO_CODE_TAKEN":           "Kode promo sudah dipakai promo lain",
+	"PROMO_IN_USE":               "Promo sudah pernah dipakai, nonaktifkan saja",
+	"PROMO_EXPIRED":              "Kode promo tidak berlaku saat ini",
+	"PROMO_MIN_SPEND_NOT_MET":    "Order belum mencapai minimum belanja promo",
+	"PROMO_NOT_APPLICABLE":       "Kode promo tidak berlaku untuk order ini",
+	"PROMO_USAGE_EXHAUSTED":      "Kuota kode promo sudah habis",
+	"PROMO_USER_LIMIT_REACHED":   "Kamu sudah mencapai batas pemakaian kode promo ini",
+
+	// sukses
+	"MOVIE_DELETED":       "Film berhasil dihapus",
+	"PASSWORD_RESET":      "Password berhasil diubah",
+	"LOGGED_OUT":          "Berhasil logout",
+	"TRAILER_DELETED":     "Trailer berhasil dihapus",
+	"REVIEW_DELETED":      "Review berhasil dihapus",
+	"REVIEW_REPORTED":     "Review berhasil dilaporkan, terima kasih",
+	"STILL_DELETED":       "Still berhasil dihapus",
+	"WATCHLIST_REMOVED":   "Film berhasil dihapus dari watchlist",
+	"STUDIO_DELETED":      "Studio berhasil dihapus",
+	"SCHEDULES_CANCELLED": "Jadwal berhasil dibatalkan",
+	"SCHEDULES_SHIFTED":   "Jadwal berhasil digeser",
+	"PRICE_RULE_DELETED":  "Aturan harga berhasil dihapus",
+	"HOLIDAY_DELETED":     "Hari libur berhasil dihapus",
+	"PROMO_DELETED":       "Promo berhasil dihapus",
+}
@@ -0,0 +1,210 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/HugoSmits86/nativewebp"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+var (
+	// ErrUnsupported jika isi file (bukan ekstensinya) bukan jpeg, png atau webp
+	ErrUnsupported = errors.New("imaging: unsupported image type")
+	// ErrCorrupt jika file tidak bisa di-decode atau isinya tidak sesuai tipe
+	ErrCorrupt = errors.New("imaging: corrupt image")
+	// ErrTooManyPixels jika dimensi gambar melebihi MaxPixels
+	ErrTooManyPixels = errors.New("imaging: image dimensions too large")
+)
+
+// MaxPixels membatasi dimensi gambar sebelum di-decode (decompression bomb)
+const MaxPixels = 40_000_000
+
+// kualitas jpeg untuk semua variant
+const jpegQuality = 85
+
+// format hasil sniffing -> nama format dari image.Decode
+var sniffFormats = map[string]string{
+	"image/jpeg": "jpeg",
+	"image/png":  "png",
+	"image/webp": "webp",
+}
+
+// Nama variant, dipakai sebagai suffix key
+const (
+	Original  = "original"
+	Thumbnail = "thumb"
+	Medium    = "medium"
+	Large     = "large"
+	WebP      = "webp"
+)
+
+// Mode menentukan cara resize
+type Mode int
+
+const (
+	// Fit mengecilkan sampai lebar maksimal, rasio dipertahankan
+	Fit Mode = iota
+	// Fill memotong tengah menjadi persegi lalu mengecilkan
+	Fill
+)
+
+// Preset adalah ukuran (lebar px) tiap variant untuk satu jenis gambar
+type Preset struct {
+	Mode      Mode
+	Original  int
+	Thumbnail int
+	Medium    int
+	Large     int
+}
+
+var (
+	PosterPreset   = Preset{Mode: Fit, Original: 2000, Thumbnail: 185, Medium: 342, Large: 780}
+	BackdropPreset = Preset{Mode: Fit, Original: 2560, Thumbnail: 300, Medium: 780, Large: 1280}
+	AvatarPreset   = Preset{Mode: Fill, Original: 1024, Thumbnail: 64, Medium: 128, Large: 256}
+)
+
+// Variant adalah satu file hasil proses yang siap disimpan
+type Variant struct {
+	Name        string
+	Ext         string
+	ContentType string
+	Data        []byte
+}
+
+// Process membaca gambar upload, memastikan isinya benar-benar gambar,
+// menerapkan orientasi EXIF lalu meng-encode ulang tanpa metadata
+// menjadi semua variant di preset.
+func Process(r io.Reader, preset Preset) ([]Variant, error) {
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	contentType := http.DetectContentType(raw)
+	format, ok := sniffFormats[contentType]
+	if !ok {
+		return nil, ErrUnsupported
+	}
+
+	cfg, cfgFormat, err := image.DecodeConfig(bytes.NewReader(raw))
+	if err != nil || cfgFormat != format {
+		return nil, ErrCorrupt
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > MaxPixels {
+		return nil, ErrTooManyPixels
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorrupt, err)
+	}
+	if format == "jpeg" {
+		img = orient(img, exifOrientation(raw))
+	}
+
+	// gambar dengan alpha disimpan sebagai png, sisanya jpeg
+	ext, encode := ".jpg", encodeJPEG
+	if !opaque(img) {
+		ext, encode = ".png", encodePNG
+	}
+
+	sizes := []struct {
+		name  string
+		width int
+	}{
+		{Original, preset.Original},
+		{Thumbnail, preset.Thumbnail},
+		{Medium, preset.Medium},
+		{Large, preset.Large},
+	}
+
+	variants := make([]Variant, 0, len(sizes)+1)
+	var large image.Image
+	for _, size := range sizes {
+		resized := resize(img, size.width, preset.Mode)
+		if size.name == Large {
+			large = resized
+		}
+		data, err := encode(resized)
+		if err != nil {
+			return nil, err
+		}
+		variants = append(variants, Variant{Name: size.name, Ext: ext, ContentType: mimeOf(ext), Data: data})
+	}
+
+	var buf bytes.Buffer
+	if err := nativewebp.Encode(&buf, large, nil); err != nil {
+		return nil, err
+	}
+	variants = append(variants, Variant{Name: WebP, Ext: ".webp", ContentType: "image/webp", Data: buf.Bytes()})
+	return variants, nil
+}
+
+// VariantKey menurunkan key variant dari key original secara deterministik,
+// misal "123_poster.jpg" -> "123_poster_thumb.jpg" dan "123_poster.webp"
+func VariantKey(key, name string) string {
+	ext := path.Ext(key)
+	base := strings.TrimSuffix(key, ext)
+	switch name {
+	case Original:
+		return key
+	case WebP:
+		return base + ".webp"
+	}
+	return base + "_" + name + ext
+}
+
+func resize(img image.Image, width int, mode Mode) image.Image {
+	src := img.Bounds()
+	if mode == Fill {
+		side := min(src.Dx(), src.Dy())
+		x := src.Min.X + (src.Dx()-side)/2
+		y := src.Min.Y + (src.Dy()-side)/2
+		src = image.Rect(x, y, x+side, y+side)
+	}
+	if width <= 0 || src.Dx() <= width {
+		width = src.Dx()
+	}
+	height := max(1, src.Dy()*width/src.Dx())
+
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, src, draw.Src, nil)
+	return dst
+}
+
+func opaque(img image.Image) bool {
+	if o, ok := img.(interface{ Opaque() bool }); ok {
+		return o.Opaque()
+	}
+	return false
+}
+
+func encodeJPEG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality})
+	return buf.Bytes(), err
+}
+
+func encodePNG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := png.Encoder{CompressionLevel: png.BestCompression}
+	err := enc.Encode(&buf, img)
+	return buf.Bytes(), err
+}
+
+func mimeOf(ext string) string {
+	if ext == ".png" {
+		return "image/png"
+	}
+	return "image/jpeg"
+}
@@ -0,0 +1,338 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+var (
+	red  = color.NRGBA{R: 255, A: 255}
+	blue = color.NRGBA{B: 255, A: 255}
+)
+
+// halfImage membuat gambar w x h dengan setengah kiri merah dan setengah kanan biru
+func halfImage(w, h int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			c := red
+			if x >= w/2 {
+				c = blue
+			}
+			img.SetNRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+func encodeTestJPEG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 95}); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func encodeTestPNG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// exifSegment membuat segmen APP1 berisi satu tag Orientation
+func exifSegment(order binary.ByteOrder, orientation int) []byte {
+	tiff := make([]byte, 26)
+	if order == binary.LittleEndian {
+		copy(tiff, "II")
+	} else {
+		copy(tiff, "MM")
+	}
+	order.PutUint16(tiff[2:], 42)
+	order.PutUint32(tiff[4:], 8)
+	order.PutUint16(tiff[8:], 1)
+	order.PutUint16(tiff[10:], 0x0112)
+	order.PutUint16(tiff[12:], 3) // SHORT
+	order.PutUint32(tiff[14:], 1)
+	order.PutUint16(tiff[18:], uint16(orientation))
+
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	segment := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+	return append(segment, payload...)
+}
+
+// withExif menyisipkan segmen EXIF tepat setelah SOI
+func withExif(jpg []byte, order binary.ByteOrder, orientation int) []byte {
+	out := append([]byte{}, jpg[:2]...)
+	out = append(out, exifSegment(order, orientation)...)
+	return append(out, jpg[2:]...)
+}
+
+func variantsByName(t *testing.T, variants []Variant) map[string]Variant {
+	t.Helper()
+	byName := make(map[string]Variant, len(variants))
+	for _, v := range variants {
+		byName[v.Name] = v
+	}
+	for _, name := range []string{Original, Thumbnail, Medium, Large, WebP} {
+		if _, ok := byName[name]; !ok {
+			t.Fatalf("variant %s missing", name)
+		}
+	}
+	return byName
+}
+
+func decodeVariant(t *testing.T, v Variant) image.Image {
+	t.Helper()
+	img, _, err := image.Decode(bytes.NewReader(v.Data))
+	if err != nil {
+		t.Fatalf("decode %s: %v", v.Name, err)
+	}
+	return img
+}
+
+// near true jika warna mendekati want, jpeg tidak menyimpan warna persis
+func near(got color.Color, want color.NRGBA) bool {
+	r, g, b, _ := got.RGBA()
+	diff := func(a uint32, b uint8) bool {
+		d := int(a>>8) - int(b)
+		return d > -40 && d < 40
+	}
+	return diff(r, want.R) && diff(g, want.G) && diff(b, want.B)
+}
+
+func TestProcessRejects(t *testing.T) {
+	// png valid yang IHDR-nya diubah menjadi 10000 x 10000, CRC dihitung ulang
+	huge := encodeTestPNG(t, halfImage(4, 4))
+	binary.BigEndian.PutUint32(huge[16:], 10000)
+	binary.BigEndian.PutUint32(huge[20:], 10000)
+	binary.BigEndian.PutUint32(huge[29:], crc32.ChecksumIEEE(huge[12:29]))
+
+	var gifBuf bytes.Buffer
+	if err := gif.Encode(&gifBuf, halfImage(4, 4), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{"plain text", []byte("hello, this is not an image"), ErrUnsupported},
+		{"html with jpg name", []byte("<html><script>alert(1)</script></html>"), ErrUnsupported},
+		{"gif", gifBuf.Bytes(), ErrUnsupported},
+		{"empty", nil, ErrUnsupported},
+		{"png header only", encodeTestPNG(t, halfImage(4, 4))[:20], ErrCorrupt},
+		{"truncated jpeg", encodeTestJPEG(t, halfImage(32, 32))[:200], ErrCorrupt},
+		{"too many pixels", huge, ErrTooManyPixels},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Process(bytes.NewReader(tt.data), PosterPreset); !errors.Is(err, tt.want) {
+				t.Errorf("Process err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestExifOrientation(t *testing.T) {
+	jpg := encodeTestJPEG(t, halfImage(8, 8))
+	tests := []struct {
+		name string
+		data []byte
+		want int
+	}{
+		{"no exif", jpg, 1},
+		{"big endian 6", withExif(jpg, binary.BigEndian, 6), 6},
+		{"little endian 8", withExif(jpg, binary.LittleEndian, 8), 8},
+		{"little endian 3", withExif(jpg, binary.LittleEndian, 3), 3},
+		{"out of range", withExif(jpg, binary.BigEndian, 9), 1},
+		{"not a jpeg", []byte("not a jpeg"), 1},
+		{"truncated segment", withExif(jpg, binary.BigEndian, 6)[:12], 1},
+	}
+	for _, tt := range tests {
+		if got := exifOrientation(tt.data); got != tt.want {
+			t.Errorf("%s: exifOrientation = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestProcessAppliesOrientation(t *testing.T) {
+	// gambar 64 x 32, kiri merah & kanan biru
+	jpg := encodeTestJPEG(t, halfImage(64, 32))
+
+	tests := []struct {
+		name        string
+		orientation int
+		w, h        int
+		// warna di seperempat atas dan seperempat bawah original
+		top, bottom color.NRGBA
+	}{
+		// diputar 90° searah jarum jam: sisi kiri menjadi atas
+		{"rotate 90 cw", 6, 32, 64, red, blue},
+		// diputar 90° berlawanan jarum jam: sisi kiri menjadi bawah
+		{"rotate 90 ccw", 8, 32, 64, blue, red},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			variants, err := Process(bytes.NewReader(withExif(jpg, binary.BigEndian, tt.orientation)), PosterPreset)
+			if err != nil {
+				t.Fatal(err)
+			}
+			original := variantsByName(t, variants)[Original]
+			img := decodeVariant(t, original)
+			if b := img.Bounds(); b.Dx() != tt.w || b.Dy() != tt.h {
+				t.Fatalf("original = %dx%d, want %dx%d", b.Dx(), b.Dy(), tt.w, tt.h)
+			}
+			if got := img.At(tt.w/2, tt.h/4); !near(got, tt.top) {
+				t.Errorf("top = %v, want %v", got, tt.top)
+			}
+			if got := img.At(tt.w/2, tt.h*3/4); !near(got, tt.bottom) {
+				t.Errorf("bottom = %v, want %v", got, tt.bottom)
+			}
+		})
+	}
+}
+
+func TestProcessStripsExif(t *testing.T) {
+	src := withExif(encodeTestJPEG(t, halfImage(64, 32)), binary.LittleEndian, 6)
+	variants, err := Process(bytes.NewReader(src), PosterPreset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range variants {
+		if bytes.Contains(v.Data, []byte("Exif\x00\x00")) {
+			t.Errorf("variant %s still contains EXIF", v.Name)
+		}
+		// orientasi sudah diterapkan, tidak boleh diputar lagi oleh client
+		if got := exifOrientation(v.Data); got != 1 {
+			t.Errorf("variant %s orientation = %d", v.Name, got)
+		}
+	}
+}
+
+func TestProcessVariantSizes(t *testing.T) {
+	type size struct{ w, h int }
+	tests := []struct {
+		name   string
+		data   []byte
+		preset Preset
+		ext    string
+		want   map[string]size
+	}{
+		{
+			name:   "poster fit",
+			data:   encodeTestJPEG(t, halfImage(1000, 1500)),
+			preset: PosterPreset,
+			ext:    ".jpg",
+			want: map[string]size{
+				Original:  {1000, 1500},
+				Thumbnail: {185, 277},
+				Medium:    {342, 513},
+				Large:     {780, 1170},
+				WebP:      {780, 1170},
+			},
+		},
+		{
+			name:   "small image never upscaled",
+			data:   encodeTestJPEG(t, halfImage(300, 200)),
+			preset: BackdropPreset,
+			ext:    ".jpg",
+			want: map[string]size{
+				Original:  {300, 200},
+				Thumbnail: {300, 200},
+				Medium:    {300, 200},
+				Large:     {300, 200},
+				WebP:      {300, 200},
+			},
+		},
+		{
+			name:   "avatar fill crops square",
+			data:   encodeTestPNG(t, halfImage(300, 200)),
+			preset: AvatarPreset,
+			ext:    ".jpg",
+			want: map[string]size{
+				Original:  {200, 200},
+				Thumbnail: {64, 64},
+				Medium:    {128, 128},
+				Large:     {200, 200},
+				WebP:      {200, 200},
+			},
+		},
+		{
+			name: "transparent png stays png",
+			data: func() []byte {
+				img := halfImage(400, 400)
+				img.SetNRGBA(0, 0, color.NRGBA{})
+				return encodeTestPNG(t, img)
+			}(),
+			preset: AvatarPreset,
+			ext:    ".png",
+			want: map[string]size{
+				Original:  {400, 400},
+				Thumbnail: {64, 64},
+				Medium:    {128, 128},
+				Large:     {256, 256},
+				WebP:      {256, 256},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			variants, err := Process(bytes.NewReader(tt.data), tt.preset)
+			if err != nil {
+				t.Fatal(err)
+			}
+			byName := variantsByName(t, variants)
+			for name, want := range tt.want {
+				v := byName[name]
+				wantExt := tt.ext
+				if name == WebP {
+					wantExt = ".webp"
+				}
+				if v.Ext != wantExt {
+					t.Errorf("%s ext = %s, want %s", name, v.Ext, wantExt)
+				}
+				cfg, format, err := image.DecodeConfig(bytes.NewReader(v.Data))
+				if err != nil {
+					t.Fatalf("%s: %v", name, err)
+				}
+				if sniffFormats[v.ContentType] != format {
+					t.Errorf("%s content type %s but encoded as %s", name, v.ContentType, format)
+				}
+				if cfg.Width != want.w || cfg.Height != want.h {
+					t.Errorf("%s = %dx%d, want %dx%d", name, cfg.Width, cfg.Height, want.w, want.h)
+				}
+			}
+		})
+	}
+}
+
+func TestVariantKey(t *testing.T) {
+	tests := []struct {
+		key, name, want string
+	}{
+		{"123_poster.jpg", Original, "123_poster.jpg"},
+		{"123_poster.jpg", Thumbnail, "123_poster_thumb.jpg"},
+		{"123_poster.png", Large, "123_poster_large.png"},
+		{"123_poster.jpg", WebP, "123_poster.webp"},
+		{"user_1.png", Medium, "user_1_medium.png"},
+	}
+	for _, tt := range tests {
+		if got := VariantKey(tt.key, tt.name); got != tt.want {
+			t.Errorf("VariantKey(%q, %q) = %q, want %q", tt.key, tt.name, got, tt.want)
+		}
+	}
+}
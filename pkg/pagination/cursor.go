@@ -0,0 +1,179 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor menyimpan posisi baris terakhir (nilai kolom sort + id) secara opaque
+type Cursor struct {
+	Key  string `json:"k,omitempty"`
+	ID   int    `json:"i"`
+	Prev bool   `json:"p,omitempty"`
+}
+
+func (c Cursor) Encode() string {
+	bt, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(bt)
+}
+
+func Decode(raw string) (*Cursor, error) {
+	bt, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c Cursor
+	if err := json.Unmarshal(bt, &c); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+// Request adalah parameter pagination dari query string.
+// Jika Cursor nil maka dipakai page (fallback LIMIT/OFFSET).
+type Request struct {
+	Limit  int
+	Page   int
+	Cursor *Cursor
+}
+
+// Parse membaca query cursor, page dan limit
+func Parse(ctx *gin.Context, defaultLimit, maxLimit int) (Request, error) {
+	req := Request{Limit: defaultLimit, Page: 1}
+	if v, err := strconv.Atoi(ctx.Query("limit")); err == nil && v > 0 {
+		req.Limit = min(v, maxLimit)
+	}
+	if raw := ctx.Query("cursor"); raw != "" {
+		c, err := Decode(raw)
+		if err != nil {
+			return req, err
+		}
+		req.Cursor = c
+		return req, nil
+	}
+	if v, err := strconv.Atoi(ctx.Query("page")); err == nil && v > 0 {
+		req.Page = v
+	}
+	return req, nil
+}
+
+// Offset hanya dipakai ketika tidak ada cursor
+func (r Request) Offset() int {
+	if r.Cursor != nil {
+		return 0
+	}
+	return (r.Page - 1) * r.Limit
+}
+
+// IsFirst true untuk halaman pertama tanpa cursor (dipakai untuk cache)
+func (r Request) IsFirst() bool {
+	return r.Cursor == nil && r.Page == 1
+}
+
+type Meta struct {
+	Limit      int    `json:"limit"`
+	Page       int    `json:"page,omitempty"`
+	Total      *int   `json:"total,omitempty"`
+	TotalPages *int   `json:"total_pages,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+}
+
+// WithTotal melengkapi meta dengan jumlah total baris
+func (m Meta) WithTotal(total int) Meta {
+	pages := 0
+	if m.Limit > 0 {
+		pages = (total + m.Limit - 1) / m.Limit
+	}
+	m.Total = &total
+	m.TotalPages = &pages
+	return m
+}
+
+// Keyset mendeskripsikan urutan (kolom sort, id) untuk keyset pagination
+type Keyset struct {
+	Column   string // ekspresi sort, kosong jika hanya berdasarkan id
+	Cast     string // tipe postgres untuk nilai cursor, mis. "date"
+	IDColumn string
+	Desc     bool
+}
+
+func (k Keyset) descending(c *Cursor) bool {
+	if c != nil && c.Prev {
+		return !k.Desc
+	}
+	return k.Desc
+}
+
+// Where menghasilkan kondisi keyset mulai dari placeholder argIdx
+func (k Keyset) Where(c *Cursor, argIdx int) (string, []any) {
+	if c == nil {
+		return "", nil
+	}
+	op := ">"
+	if k.descending(c) {
+		op = "<"
+	}
+	if k.Column == "" {
+		return fmt.Sprintf("%s %s $%d", k.IDColumn, op, argIdx), []any{c.ID}
+	}
+	return fmt.Sprintf("(%s, %s) %s ($%d::%s, $%d)", k.Column, k.IDColumn, op, argIdx, k.Cast, argIdx+1),
+		[]any{c.Key, c.ID}
+}
+
+// OrderBy menghasilkan klausa ORDER BY (dibalik saat menuju halaman sebelumnya)
+func (k Keyset) OrderBy(c *Cursor) string {
+	dir := "ASC"
+	if k.descending(c) {
+		dir = "DESC"
+	}
+	if k.Column == "" {
+		return fmt.Sprintf("ORDER BY %s %s", k.IDColumn, dir)
+	}
+	return fmt.Sprintf("ORDER BY %s %s, %s %s", k.Column, dir, k.IDColumn, dir)
+}
+
+// Slice memotong hasil query (diambil Limit+1 baris) dan menyusun cursor next/prev.
+// key mengembalikan nilai kolom sort dan id untuk satu baris.
+func Slice[T any](items []T, req Request, key func(T) (string, int)) ([]T, Meta) {
+	meta := Meta{Limit: req.Limit}
+	if req.Cursor == nil {
+		meta.Page = req.Page
+	}
+	hasExtra := len(items) > req.Limit
+	if hasExtra {
+		items = items[:req.Limit]
+	}
+	backward := req.Cursor != nil && req.Cursor.Prev
+	if backward {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+	}
+	if len(items) == 0 {
+		return items, meta
+	}
+
+	hasNext := hasExtra
+	hasPrev := req.Cursor != nil || req.Page > 1
+	if backward {
+		hasNext = true
+		hasPrev = hasExtra
+	}
+	if hasNext {
+		k, id := key(items[len(items)-1])
+		meta.NextCursor = Cursor{Key: k, ID: id}.Encode()
+	}
+	if hasPrev {
+		k, id := key(items[0])
+		meta.PrevCursor = Cursor{Key: k, ID: id, Prev: true}.Encode()
+	}
+	return items, meta
+}
@@ -0,0 +1,191 @@
+package pagination
+
+import (
+	"errors"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	for _, c := range []Cursor{
+		{ID: 7},
+		{Key: "2026-10-19", ID: 3},
+		{Key: "4.5", ID: 12, Prev: true},
+	} {
+		got, err := Decode(c.Encode())
+		if err != nil {
+			t.Fatalf("Decode(%+v): %v", c, err)
+		}
+		if *got != c {
+			t.Errorf("round trip = %+v, want %+v", *got, c)
+		}
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	for _, raw := range []string{"%%%", "bm90IGpzb24", "e30=", "WyJhIl0"} {
+		if _, err := Decode(raw); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("Decode(%q) err = %v, want ErrInvalidCursor", raw, err)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	parse := func(query string) (Request, error) {
+		ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+		ctx.Request = httptest.NewRequest("GET", "/?"+query, nil)
+		return Parse(ctx, 5, 20)
+	}
+
+	tests := []struct {
+		query string
+		want  Request
+	}{
+		{"", Request{Limit: 5, Page: 1}},
+		{"limit=10&page=3", Request{Limit: 10, Page: 3}},
+		{"limit=100", Request{Limit: 20, Page: 1}},
+		{"limit=-1&page=0", Request{Limit: 5, Page: 1}},
+		{"limit=abc&page=x", Request{Limit: 5, Page: 1}},
+	}
+	for _, tt := range tests {
+		got, err := parse(tt.query)
+		if err != nil || got.Limit != tt.want.Limit || got.Page != tt.want.Page || got.Cursor != nil {
+			t.Errorf("Parse(%q) = %+v, %v, want %+v", tt.query, got, err, tt.want)
+		}
+	}
+
+	cursor := Cursor{ID: 9}
+	got, err := parse("page=4&cursor=" + cursor.Encode())
+	if err != nil || got.Cursor == nil || *got.Cursor != cursor {
+		t.Errorf("Parse with cursor = %+v, %v", got, err)
+	}
+	if got.Offset() != 0 || got.IsFirst() {
+		t.Errorf("cursor request offset = %d, first = %v", got.Offset(), got.IsFirst())
+	}
+	if _, err := parse("cursor=***"); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("Parse bad cursor err = %v, want ErrInvalidCursor", err)
+	}
+	if r := (Request{Limit: 10, Page: 3}); r.Offset() != 20 {
+		t.Errorf("Offset = %d, want 20", r.Offset())
+	}
+}
+
+func TestKeysetSQL(t *testing.T) {
+	byID := Keyset{IDColumn: "r.id", Desc: true}
+	byDate := Keyset{Column: "m.release_date", Cast: "date", IDColumn: "m.id"}
+
+	tests := []struct {
+		name    string
+		keyset  Keyset
+		cursor  *Cursor
+		where   string
+		args    []any
+		orderBy string
+	}{
+		{"first page", byID, nil, "", nil, "ORDER BY r.id DESC"},
+		{"id next", byID, &Cursor{ID: 10}, "r.id < $3", []any{10}, "ORDER BY r.id DESC"},
+		{"id prev", byID, &Cursor{ID: 10, Prev: true}, "r.id > $3", []any{10}, "ORDER BY r.id ASC"},
+		{"column next", byDate, &Cursor{Key: "2025-01-02", ID: 4},
+			"(m.release_date, m.id) > ($3::date, $4)", []any{"2025-01-02", 4}, "ORDER BY m.release_date ASC, m.id ASC"},
+		{"column prev", byDate, &Cursor{Key: "2025-01-02", ID: 4, Prev: true},
+			"(m.release_date, m.id) < ($3::date, $4)", []any{"2025-01-02", 4}, "ORDER BY m.release_date DESC, m.id DESC"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			where, args := tt.keyset.Where(tt.cursor, 3)
+			if where != tt.where || !slices.Equal(args, tt.args) {
+				t.Errorf("Where = %q %v, want %q %v", where, args, tt.where, tt.args)
+			}
+			if got := tt.keyset.OrderBy(tt.cursor); got != tt.orderBy {
+				t.Errorf("OrderBy = %q, want %q", got, tt.orderBy)
+			}
+		})
+	}
+}
+
+// fetch mensimulasikan query keyset descending by id: ambil Limit+1 baris setelah cursor
+func fetch(ids []int, req Request) []int {
+	desc := req.Cursor == nil || !req.Cursor.Prev
+	rows := slices.Clone(ids)
+	if desc {
+		slices.SortFunc(rows, func(a, b int) int { return b - a })
+	} else {
+		slices.Sort(rows)
+	}
+	var out []int
+	for _, id := range rows {
+		if req.Cursor != nil && ((desc && id >= req.Cursor.ID) || (!desc && id <= req.Cursor.ID)) {
+			continue
+		}
+		out = append(out, id)
+		if len(out) == req.Limit+1 {
+			break
+		}
+	}
+	return out
+}
+
+func TestSlicePaging(t *testing.T) {
+	ids := []int{1, 2, 3, 4, 5, 6, 7}
+	key := func(id int) (string, int) { return "", id }
+	page := func(req Request) ([]int, Meta) { return Slice(fetch(ids, req), req, key) }
+	next := func(m Meta) Request {
+		c, err := Decode(m.NextCursor)
+		if err != nil {
+			t.Fatalf("next cursor: %v", err)
+		}
+		return Request{Limit: 3, Cursor: c}
+	}
+	prev := func(m Meta) Request {
+		c, err := Decode(m.PrevCursor)
+		if err != nil {
+			t.Fatalf("prev cursor: %v", err)
+		}
+		return Request{Limit: 3, Cursor: c}
+	}
+
+	// maju
+	p1, m1 := page(Request{Limit: 3, Page: 1})
+	if !slices.Equal(p1, []int{7, 6, 5}) || m1.PrevCursor != "" || m1.NextCursor == "" || m1.Page != 1 {
+		t.Fatalf("page 1 = %v %+v", p1, m1)
+	}
+	p2, m2 := page(next(m1))
+	if !slices.Equal(p2, []int{4, 3, 2}) || m2.PrevCursor == "" || m2.NextCursor == "" || m2.Page != 0 {
+		t.Fatalf("page 2 = %v %+v", p2, m2)
+	}
+	p3, m3 := page(next(m2))
+	if !slices.Equal(p3, []int{1}) || m3.NextCursor != "" || m3.PrevCursor == "" {
+		t.Fatalf("page 3 = %v %+v", p3, m3)
+	}
+
+	// mundur lagi ke halaman pertama
+	b2, bm2 := page(prev(m3))
+	if !slices.Equal(b2, []int{4, 3, 2}) || bm2.NextCursor == "" || bm2.PrevCursor == "" {
+		t.Fatalf("back to page 2 = %v %+v", b2, bm2)
+	}
+	b1, bm1 := page(prev(bm2))
+	if !slices.Equal(b1, []int{7, 6, 5}) || bm1.PrevCursor != "" || bm1.NextCursor == "" {
+		t.Fatalf("back to page 1 = %v %+v", b1, bm1)
+	}
+
+	// cursor setelah baris terakhir
+	empty, me := page(Request{Limit: 3, Cursor: &Cursor{ID: 1}})
+	if len(empty) != 0 || me.NextCursor != "" || me.PrevCursor != "" {
+		t.Errorf("past the end = %v %+v", empty, me)
+	}
+}
+
+func TestMetaWithTotal(t *testing.T) {
+	m := Meta{Limit: 5}.WithTotal(11)
+	if *m.Total != 11 || *m.TotalPages != 3 {
+		t.Errorf("WithTotal = %d/%d, want 11/3", *m.Total, *m.TotalPages)
+	}
+	m = Meta{Limit: 5}.WithTotal(0)
+	if *m.TotalPages != 0 {
+		t.Errorf("WithTotal(0) pages = %d", *m.TotalPages)
+	}
+}
@@ -0,0 +1,166 @@
+// Package pricing menghitung harga tiket dari harga dasar cinema dan aturan harga
+// berdasarkan tipe kursi, hari, jam tayang, hari libur, format studio & kategori penonton.
+package pricing
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// jenis penyesuaian harga
+const (
+	// harga diganti amount
+	AdjustSet = "set"
+	// harga ditambah amount, negatif untuk potongan
+	AdjustAdd = "add"
+	// harga ditambah amount persen, negatif untuk diskon
+	AdjustPercent = "percent"
+)
+
+// Rule adalah satu aturan harga. Kondisi yang kosong berlaku untuk semua tiket.
+type Rule struct {
+	ID       int
+	Name     string
+	CinemaID *int
+	SeatType string
+	// 0 = Minggu
+	Weekdays []int
+	// rentang jam mulai lokal "HH:MM", end eksklusif. Start > end berarti melewati tengah malam.
+	StartTime string
+	EndTime   string
+	Holiday   *bool
+	Format    string
+	Category  string
+
+	Adjustment string
+	Amount     decimal.Decimal
+	Priority   int
+}
+
+// Ticket adalah satu kursi yang dihargai, Start memakai zona waktu cinema
+type Ticket struct {
+	CinemaID int
+	SeatType string
+	Start    time.Time
+	Holiday  bool
+	Format   string
+	Category string
+}
+
+// Matches true jika semua kondisi rule terpenuhi tiket
+func (r Rule) Matches(t Ticket) bool {
+	if r.CinemaID != nil && *r.CinemaID != t.CinemaID {
+		return false
+	}
+	if r.SeatType != "" && r.SeatType != t.SeatType {
+		return false
+	}
+	if len(r.Weekdays) > 0 && !slices.Contains(r.Weekdays, int(t.Start.Weekday())) {
+		return false
+	}
+	if r.StartTime != "" && r.EndTime != "" && !inWindow(t.Start.Format("15:04"), r.StartTime, r.EndTime) {
+		return false
+	}
+	if r.Holiday != nil && *r.Holiday != t.Holiday {
+		return false
+	}
+	if r.Format != "" && r.Format != t.Format {
+		return false
+	}
+	if r.Category != "" && r.Category != t.Category {
+		return false
+	}
+	return true
+}
+
+// "HH:MM" bisa dibandingkan sebagai string
+func inWindow(clock, start, end string) bool {
+	if start <= end {
+		return clock >= start && clock < end
+	}
+	return clock >= start || clock < end
+}
+
+var hundred = decimal.NewFromInt(100)
+
+func (r Rule) apply(price decimal.Decimal) decimal.Decimal {
+	switch r.Adjustment {
+	case AdjustSet:
+		return r.Amount
+	case AdjustAdd:
+		return price.Add(r.Amount)
+	case AdjustPercent:
+		return price.Add(price.Mul(r.Amount).Div(hundred))
+	}
+	return price
+}
+
+// Adjustment adalah perubahan harga oleh satu rule, negatif berarti diskon
+type Adjustment struct {
+	Rule   Rule
+	Amount decimal.Decimal
+}
+
+// Price menerapkan rule yang cocok berurutan dari priority terkecil (lalu ID), sehingga
+// rule "set" dengan priority kecil bisa menjadi harga dasar baru bagi rule setelahnya.
+// Setiap langkah dibulatkan ke 2 desimal dan tidak negatif, jadi jumlah adjustment
+// selalu sama dengan selisih harga akhir dan harga dasar.
+func Price(base decimal.Decimal, rules []Rule, t Ticket) (price decimal.Decimal, applied []Adjustment) {
+	ordered := slices.Clone(rules)
+	slices.SortStableFunc(ordered, func(a, b Rule) int {
+		return cmp.Or(cmp.Compare(a.Priority, b.Priority), cmp.Compare(a.ID, b.ID))
+	})
+
+	price = base.Round(2)
+	for _, r := range ordered {
+		if !r.Matches(t) {
+			continue
+		}
+		next := decimal.Max(decimal.Zero, r.apply(price).Round(2))
+		applied = append(applied, Adjustment{Rule: r, Amount: next.Sub(price)})
+		price = next
+	}
+	return price, applied
+}
+
+// Charges adalah biaya di luar aturan harga yang dikenakan per tiket
+type Charges struct {
+	// biaya layanan per tiket
+	BookingFee decimal.Decimal
+	// persen pajak dari harga tiket + biaya layanan
+	TaxRate decimal.Decimal
+}
+
+// Line adalah rincian harga satu tiket
+type Line struct {
+	Base        decimal.Decimal
+	Adjustments []Adjustment
+	Subtotal    decimal.Decimal
+	Fee         decimal.Decimal
+	Tax         decimal.Decimal
+	Total       decimal.Decimal
+}
+
+// Tax adalah pajak satu tiket dari harga tiket + biaya layanan, dibulatkan ke 2 desimal
+func (c Charges) Tax(subtotal, fee decimal.Decimal) decimal.Decimal {
+	return subtotal.Add(fee).Mul(c.TaxRate).Div(hundred).Round(2)
+}
+
+// Line menghitung harga tiket lalu menambahkan biaya layanan & pajak, pajak dibulatkan
+// per tiket supaya total order selalu jumlah total tiap tiket
+func (c Charges) Line(base decimal.Decimal, rules []Rule, t Ticket) Line {
+	subtotal, applied := Price(base, rules, t)
+	fee := c.BookingFee.Round(2)
+	tax := c.Tax(subtotal, fee)
+	return Line{
+		Base:        base.Round(2),
+		Adjustments: applied,
+		Subtotal:    subtotal,
+		Fee:         fee,
+		Tax:         tax,
+		Total:       subtotal.Add(fee).Add(tax),
+	}
+}
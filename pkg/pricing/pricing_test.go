@@ -0,0 +1,196 @@
+package pricing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func d(s string) decimal.Decimal { return decimal.RequireFromString(s) }
+
+// Sabtu 24 Mei 2025 21:30
+var saturdayNight = time.Date(2025, 5, 24, 21, 30, 0, 0, time.UTC)
+
+func TestPrice(t *testing.T) {
+	premium := Ticket{CinemaID: 1, SeatType: "premium", Start: saturdayNight, Format: "IMAX", Category: "general"}
+
+	tests := []struct {
+		name  string
+		base  string
+		rules []Rule
+		want  string
+		// perubahan harga per rule yang cocok, berurutan
+		adjustments []string
+	}{
+		{name: "no rules", base: "35000", want: "35000"},
+		{name: "base rounded to cents", base: "35000.004", want: "35000"},
+		{
+			name: "add then percent by priority",
+			base: "35000",
+			rules: []Rule{
+				{ID: 2, Adjustment: AdjustPercent, Amount: d("-10"), Priority: 2},
+				{ID: 1, SeatType: "premium", Adjustment: AdjustAdd, Amount: d("15000"), Priority: 1},
+			},
+			want:        "45000",
+			adjustments: []string{"15000", "-5000"},
+		},
+		{
+			name: "set with lower priority becomes new base",
+			base: "35000",
+			rules: []Rule{
+				{ID: 1, Adjustment: AdjustAdd, Amount: d("5000"), Priority: 5},
+				{ID: 2, Format: "IMAX", Adjustment: AdjustSet, Amount: d("60000"), Priority: 0},
+			},
+			want:        "65000",
+			adjustments: []string{"25000", "5000"},
+		},
+		{
+			name: "set last overrides earlier rules",
+			base: "35000",
+			rules: []Rule{
+				{ID: 1, Adjustment: AdjustAdd, Amount: d("5000"), Priority: 0},
+				{ID: 2, Adjustment: AdjustSet, Amount: d("50000"), Priority: 9},
+			},
+			want:        "50000",
+			adjustments: []string{"5000", "10000"},
+		},
+		{
+			name: "same priority ordered by id",
+			base: "10000",
+			rules: []Rule{
+				{ID: 9, Adjustment: AdjustPercent, Amount: d("50")},
+				{ID: 3, Adjustment: AdjustAdd, Amount: d("2000")},
+			},
+			want:        "18000",
+			adjustments: []string{"2000", "6000"},
+		},
+		{
+			name: "each step rounded to cents",
+			base: "33333.33",
+			rules: []Rule{
+				{ID: 1, Adjustment: AdjustPercent, Amount: d("7.5")},
+				{ID: 2, Adjustment: AdjustPercent, Amount: d("-33.33")},
+			},
+			want:        "23890.08",
+			adjustments: []string{"2500", "-11943.25"},
+		},
+		{
+			name:        "negative result clamped at zero",
+			base:        "35000",
+			rules:       []Rule{{ID: 1, Adjustment: AdjustAdd, Amount: d("-50000")}},
+			want:        "0",
+			adjustments: []string{"-35000"},
+		},
+		{
+			name: "non matching rules ignored",
+			base: "35000",
+			rules: []Rule{
+				{ID: 1, SeatType: "sweetbox", Adjustment: AdjustAdd, Amount: d("20000")},
+				{ID: 2, Category: "student", Adjustment: AdjustPercent, Amount: d("-20")},
+				{ID: 3, Weekdays: []int{1, 2, 3, 4, 5}, Adjustment: AdjustSet, Amount: d("25000")},
+			},
+			want: "35000",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			price, applied := Price(d(tt.base), tt.rules, premium)
+			if !price.Equal(d(tt.want)) {
+				t.Errorf("price = %s, want %s", price, tt.want)
+			}
+			if len(applied) != len(tt.adjustments) {
+				t.Fatalf("applied %d rules, want %d", len(applied), len(tt.adjustments))
+			}
+			sum := d(tt.base).Round(2)
+			for i, a := range applied {
+				if !a.Amount.Equal(d(tt.adjustments[i])) {
+					t.Errorf("adjustment %d (rule %d) = %s, want %s", i, a.Rule.ID, a.Amount, tt.adjustments[i])
+				}
+				sum = sum.Add(a.Amount)
+			}
+			// rincian di struk harus selalu sama dengan harga akhir
+			if !sum.Equal(price) {
+				t.Errorf("base + adjustments = %s, price = %s", sum, price)
+			}
+		})
+	}
+}
+
+func TestRuleMatches(t *testing.T) {
+	yes, no := true, false
+	cinema := 2
+	ticket := Ticket{CinemaID: 1, SeatType: "regular", Start: saturdayNight, Holiday: true, Format: "2D", Category: "child"}
+
+	tests := []struct {
+		name string
+		rule Rule
+		want bool
+	}{
+		{"empty rule matches all", Rule{}, true},
+		{"other cinema", Rule{CinemaID: &cinema}, false},
+		{"weekend", Rule{Weekdays: []int{0, 6}}, true},
+		{"weekday", Rule{Weekdays: []int{1, 2, 3, 4, 5}}, false},
+		{"evening window", Rule{StartTime: "18:00", EndTime: "22:00"}, true},
+		{"end is exclusive", Rule{StartTime: "18:00", EndTime: "21:30"}, false},
+		{"window past midnight", Rule{StartTime: "21:00", EndTime: "02:00"}, true},
+		{"morning window", Rule{StartTime: "06:00", EndTime: "12:00"}, false},
+		{"holiday only", Rule{Holiday: &yes}, true},
+		{"non holiday only", Rule{Holiday: &no}, false},
+		{"format", Rule{Format: "IMAX"}, false},
+		{"category", Rule{Category: "child"}, true},
+	}
+	for _, tt := range tests {
+		if got := tt.rule.Matches(ticket); got != tt.want {
+			t.Errorf("%s: Matches = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestChargesLine(t *testing.T) {
+	premium := Rule{ID: 1, SeatType: "premium", Adjustment: AdjustAdd, Amount: d("15000")}
+	ticket := Ticket{SeatType: "premium", Start: saturdayNight}
+
+	tests := []struct {
+		name                      string
+		charges                   Charges
+		base                      string
+		rules                     []Rule
+		subtotal, fee, tax, total string
+	}{
+		{"no charges", Charges{}, "35000", nil, "35000", "0", "0", "35000"},
+		{"fee and tax on fee", Charges{BookingFee: d("4000"), TaxRate: d("11")}, "35000", []Rule{premium},
+			"50000", "4000", "5940", "59940"},
+		{"tax rounded to cents", Charges{TaxRate: d("11")}, "33333.33", nil, "33333.33", "0", "3666.67", "37000"},
+		{"tax half rounds up", Charges{TaxRate: d("5")}, "0.10", nil, "0.1", "0", "0.01", "0.11"},
+		{"fee rounded to cents", Charges{BookingFee: d("2500.505")}, "10000", nil, "10000", "2500.51", "0", "12500.51"},
+		{"free ticket still pays fee", Charges{BookingFee: d("3000"), TaxRate: d("10")}, "35000",
+			[]Rule{{ID: 2, Adjustment: AdjustSet, Amount: d("0")}}, "0", "3000", "300", "3300"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			line := tt.charges.Line(d(tt.base), tt.rules, ticket)
+			for _, c := range []struct {
+				field     string
+				got, want decimal.Decimal
+			}{
+				{"subtotal", line.Subtotal, d(tt.subtotal)},
+				{"fee", line.Fee, d(tt.fee)},
+				{"tax", line.Tax, d(tt.tax)},
+				{"total", line.Total, d(tt.total)},
+			} {
+				if !c.got.Equal(c.want) {
+					t.Errorf("%s = %s, want %s", c.field, c.got, c.want)
+				}
+			}
+			if !line.Total.Equal(line.Subtotal.Add(line.Fee).Add(line.Tax)) {
+				t.Errorf("total %s != subtotal + fee + tax", line.Total)
+			}
+			// quote & order menghitung ulang dengan input yang sama, hasilnya harus identik
+			again := tt.charges.Line(d(tt.base), tt.rules, ticket)
+			if !again.Total.Equal(line.Total) || !again.Tax.Equal(line.Tax) {
+				t.Errorf("line not deterministic: %s vs %s", again.Total, line.Total)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package pricing
+
+import "github.com/shopspring/decimal"
+
+// jenis potongan promo
+const (
+	// potongan amount persen dari subtotal
+	PromoPercent = "percent"
+	// potongan tetap sebesar amount
+	PromoFixed = "fixed"
+)
+
+// Promo adalah potongan kode promo terhadap subtotal tiket (sebelum biaya layanan & pajak)
+type Promo struct {
+	Type   string
+	Amount decimal.Decimal
+	// batas potongan untuk promo persen, nil berarti tanpa batas
+	MaxDiscount *decimal.Decimal
+}
+
+// Discount menghitung potongan untuk subtotal, tidak pernah melebihi subtotal
+func (p Promo) Discount(subtotal decimal.Decimal) decimal.Decimal {
+	discount := p.Amount
+	if p.Type == PromoPercent {
+		discount = subtotal.Mul(p.Amount).Div(hundred)
+	}
+	if p.MaxDiscount != nil {
+		discount = decimal.Min(discount, *p.MaxDiscount)
+	}
+	return decimal.Max(decimal.Zero, decimal.Min(discount, subtotal)).Round(2)
+}
+
+var cent = decimal.New(1, -2)
+
+// Allocate membagi amount (dalam sen) ke setiap bobot secara proporsional. Sisa pembulatan
+// dibagikan satu sen per bobot secara berurutan tanpa melebihi bobotnya, jadi jumlah bagian
+// selalu sama dengan amount selama amount tidak melebihi jumlah bobot.
+func Allocate(amount decimal.Decimal, weights []decimal.Decimal) []decimal.Decimal {
+	shares := make([]decimal.Decimal, len(weights))
+	total := decimal.Sum(decimal.Zero, weights...)
+	if !total.IsPositive() {
+		return shares
+	}
+	left := amount
+	for i, w := range weights {
+		shares[i] = amount.Mul(w).Div(total).RoundFloor(2)
+		left = left.Sub(shares[i])
+	}
+	for left.GreaterThanOrEqual(cent) {
+		given := false
+		for i, w := range weights {
+			if left.GreaterThanOrEqual(cent) && w.Sub(shares[i]).GreaterThanOrEqual(cent) {
+				shares[i] = shares[i].Add(cent)
+				left = left.Sub(cent)
+				given = true
+			}
+		}
+		if !given {
+			break
+		}
+	}
+	return shares
+}
@@ -0,0 +1,99 @@
+package pricing
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestPromoDiscount(t *testing.T) {
+	capAt := func(s string) *decimal.Decimal { v := d(s); return &v }
+
+	tests := []struct {
+		name     string
+		promo    Promo
+		subtotal string
+		want     string
+	}{
+		{"percent", Promo{Type: PromoPercent, Amount: d("25")}, "100000", "25000"},
+		{"percent under cap", Promo{Type: PromoPercent, Amount: d("10"), MaxDiscount: capAt("20000")}, "100000", "10000"},
+		{"percent capped", Promo{Type: PromoPercent, Amount: d("25"), MaxDiscount: capAt("20000")}, "100000", "20000"},
+		{"percent rounded to cents", Promo{Type: PromoPercent, Amount: d("12.5")}, "33333.33", "4166.67"},
+		{"fixed", Promo{Type: PromoFixed, Amount: d("15000")}, "70000", "15000"},
+		{"fixed ignores cap above amount", Promo{Type: PromoFixed, Amount: d("15000"), MaxDiscount: capAt("50000")}, "70000", "15000"},
+		{"fixed larger than subtotal", Promo{Type: PromoFixed, Amount: d("50000")}, "35000", "35000"},
+		{"full percent", Promo{Type: PromoPercent, Amount: d("100")}, "35000.50", "35000.5"},
+		{"zero subtotal", Promo{Type: PromoFixed, Amount: d("10000")}, "0", "0"},
+	}
+	for _, tt := range tests {
+		if got := tt.promo.Discount(d(tt.subtotal)); !got.Equal(d(tt.want)) {
+			t.Errorf("%s: Discount(%s) = %s, want %s", tt.name, tt.subtotal, got, tt.want)
+		}
+	}
+}
+
+func TestAllocate(t *testing.T) {
+	tests := []struct {
+		name    string
+		amount  string
+		weights []string
+		want    []string
+	}{
+		{"even split", "10000", []string{"50000", "50000"}, []string{"5000", "5000"}},
+		{"proportional", "9000", []string{"60000", "30000"}, []string{"6000", "3000"}},
+		{"remainder to first seat with room", "100", []string{"35000", "35000", "35000"}, []string{"33.34", "33.33", "33.33"}},
+		{"remainder cents", "0.05", []string{"1", "1", "1"}, []string{"0.02", "0.02", "0.01"}},
+		{"skips seats already full", "83333.34", []string{"50000", "0", "33333.33", "0.01"},
+			[]string{"50000", "0", "33333.33", "0.01"}},
+		{"tiny seat never exceeds its weight", "12345.67", []string{"50000", "0", "33333.33", "0.01"},
+			[]string{"7407.41", "0", "4938.26", "0"}},
+		{"single seat", "4166.67", []string{"33333.33"}, []string{"4166.67"}},
+		{"zero amount", "0", []string{"35000", "35000"}, []string{"0", "0"}},
+		{"zero weights", "100", []string{"0", "0"}, []string{"0", "0"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			weights := make([]decimal.Decimal, len(tt.weights))
+			for i, w := range tt.weights {
+				weights[i] = d(w)
+			}
+			shares := Allocate(d(tt.amount), weights)
+			if len(shares) != len(weights) {
+				t.Fatalf("got %d shares, want %d", len(shares), len(weights))
+			}
+			sum := decimal.Zero
+			for i, share := range shares {
+				if !share.Equal(d(tt.want[i])) {
+					t.Errorf("share %d = %s, want %s", i, share, tt.want[i])
+				}
+				if share.GreaterThan(weights[i]) || share.IsNegative() {
+					t.Errorf("share %d = %s outside [0, %s]", i, share, weights[i])
+				}
+				if !share.Equal(share.Round(2)) {
+					t.Errorf("share %d = %s is not in cents", i, share)
+				}
+				sum = sum.Add(share)
+			}
+			if total := decimal.Sum(decimal.Zero, weights...); total.IsPositive() && !sum.Equal(d(tt.amount)) {
+				t.Errorf("shares sum to %s, want %s", sum, tt.amount)
+			}
+		})
+	}
+}
+
+// potongan promo dari subtotal beberapa kursi selalu habis terbagi tepat ke sen
+func TestDiscountAllocatedExactly(t *testing.T) {
+	capAt := d("20000")
+	promo := Promo{Type: PromoPercent, Amount: d("17.5"), MaxDiscount: &capAt}
+	seats := []decimal.Decimal{d("45000"), d("33333.33"), d("0"), d("12345.67"), d("0.01")}
+	subtotal := decimal.Sum(decimal.Zero, seats...)
+
+	discount := promo.Discount(subtotal)
+	if !discount.Equal(d("15868.83")) {
+		t.Fatalf("discount = %s, want 15868.83", discount)
+	}
+	sum := decimal.Sum(decimal.Zero, Allocate(discount, seats)...)
+	if !sum.Equal(discount) {
+		t.Errorf("allocated %s, want %s", sum, discount)
+	}
+}
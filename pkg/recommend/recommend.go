@@ -0,0 +1,120 @@
+// Package recommend menghitung rekomendasi movie di dalam proses dari
+// kemiripan fitur (genre, sutradara, aktor) dengan histori user dan popularitas.
+package recommend
+
+import "sort"
+
+// bobot tiap komponen skor
+const (
+	genreWeight    = 0.5
+	actorWeight    = 0.3
+	directorWeight = 0.2
+
+	affinityWeight   = 0.75
+	popularityWeight = 0.25
+)
+
+// Features adalah fitur movie yang dibandingkan dengan selera user
+type Features struct {
+	Genres   []int
+	Actors   []int
+	Director int
+}
+
+// Profile adalah selera user, akumulasi fitur movie yang pernah ditonton
+type Profile struct {
+	genres    map[int]float64
+	actors    map[int]float64
+	directors map[int]float64
+	movies    int
+}
+
+func NewProfile() *Profile {
+	return &Profile{genres: map[int]float64{}, actors: map[int]float64{}, directors: map[int]float64{}}
+}
+
+// Add menambahkan satu movie yang ditonton user. Bobot tiap jenis fitur dibagi
+// rata supaya movie dengan banyak aktor tidak mendominasi profile.
+func (p *Profile) Add(f Features) {
+	p.movies++
+	spread(p.genres, f.Genres)
+	spread(p.actors, f.Actors)
+	if f.Director > 0 {
+		p.directors[f.Director]++
+	}
+}
+
+func spread(weights map[int]float64, ids []int) {
+	for _, id := range ids {
+		weights[id] += 1 / float64(len(ids))
+	}
+}
+
+// Empty true jika user belum punya histori
+func (p *Profile) Empty() bool {
+	return p.movies == 0
+}
+
+// Affinity bernilai 0..1, porsi selera user yang cocok dengan fitur movie
+func (p *Profile) Affinity(f Features) float64 {
+	if p.Empty() {
+		return 0
+	}
+	score := genreWeight*match(p.genres, f.Genres) + actorWeight*match(p.actors, f.Actors)
+	if f.Director > 0 {
+		score += directorWeight * p.directors[f.Director] / float64(p.movies)
+	}
+	return score
+}
+
+func match(weights map[int]float64, ids []int) float64 {
+	var total, matched float64
+	for _, w := range weights {
+		total += w
+	}
+	if total == 0 {
+		return 0
+	}
+	for _, id := range ids {
+		matched += weights[id]
+	}
+	return min(matched/total, 1)
+}
+
+// Candidate adalah movie yang bisa direkomendasikan
+type Candidate struct {
+	ID         int
+	Features   Features
+	Popularity float64
+}
+
+type Result struct {
+	ID    int
+	Score float64
+}
+
+// Rank mengurutkan kandidat dari skor tertinggi. Popularitas dinormalisasi
+// terhadap kandidat paling populer sehingga skor selalu 0..1.
+func Rank(p *Profile, candidates []Candidate) []Result {
+	var maxPopularity float64
+	for _, c := range candidates {
+		maxPopularity = max(maxPopularity, c.Popularity)
+	}
+
+	results := make([]Result, 0, len(candidates))
+	for _, c := range candidates {
+		score := affinityWeight * p.Affinity(c.Features)
+		if maxPopularity > 0 {
+			score += popularityWeight * c.Popularity / maxPopularity
+		}
+		results = append(results, Result{ID: c.ID, Score: score})
+	}
+	// urutan stabil untuk cursor: skor lalu id, sama-sama menurun
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].ID > results[j].ID
+	})
+	return results
+}
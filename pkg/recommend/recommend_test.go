@@ -0,0 +1,136 @@
+package recommend
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func approx(a, b float64) bool { return math.Abs(a-b) < 1e-9 }
+
+// profil user yang menonton dua movie
+func watchedTwo() *Profile {
+	p := NewProfile()
+	p.Add(Features{Genres: []int{1, 2}, Actors: []int{10, 11}, Director: 100})
+	p.Add(Features{Genres: []int{1}, Actors: []int{12}, Director: 101})
+	return p
+}
+
+func TestAffinity(t *testing.T) {
+	tests := []struct {
+		name     string
+		profile  *Profile
+		features Features
+		want     float64
+	}{
+		{"new user", NewProfile(), Features{Genres: []int{1}, Actors: []int{10}, Director: 100}, 0},
+		// genre 1.5/2, aktor 1/2, sutradara 1/2 movie
+		{"partial match", watchedTwo(), Features{Genres: []int{1}, Actors: []int{12}, Director: 101}, 0.5*0.75 + 0.3*0.5 + 0.2*0.5},
+		{"genre only", watchedTwo(), Features{Genres: []int{2}}, 0.5 * 0.25},
+		{"all features", watchedTwo(), Features{Genres: []int{1, 2}, Actors: []int{10, 11, 12}, Director: 100}, 0.5 + 0.3 + 0.2*0.5},
+		{"nothing in common", watchedTwo(), Features{Genres: []int{3}, Actors: []int{13}, Director: 102}, 0},
+		{"extra genres do not exceed 1", watchedTwo(), Features{Genres: []int{1, 2, 3, 4}}, 0.5},
+	}
+	for _, tt := range tests {
+		if got := tt.profile.Affinity(tt.features); !approx(got, tt.want) {
+			t.Errorf("%s: Affinity = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestProfileAddSpreadsWeight(t *testing.T) {
+	// movie dengan banyak aktor tidak mendominasi profile
+	p := NewProfile()
+	p.Add(Features{Actors: []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}})
+	p.Add(Features{Actors: []int{20}})
+	if got := p.Affinity(Features{Actors: []int{20}}); !approx(got, 0.3*0.5) {
+		t.Errorf("single actor affinity = %v, want %v", got, 0.3*0.5)
+	}
+	if got := p.Affinity(Features{Actors: []int{1}}); !approx(got, 0.3*0.05) {
+		t.Errorf("one of ten actors affinity = %v, want %v", got, 0.3*0.05)
+	}
+	if p.Empty() {
+		t.Error("profile with history is empty")
+	}
+	// movie tanpa fitur tetap dihitung sebagai histori
+	q := NewProfile()
+	q.Add(Features{})
+	if q.Empty() || q.Affinity(Features{Genres: []int{1}, Director: 1}) != 0 {
+		t.Error("featureless history")
+	}
+}
+
+func TestRank(t *testing.T) {
+	candidates := []Candidate{
+		{ID: 1, Features: Features{Genres: []int{3}}, Popularity: 100},
+		{ID: 2, Features: Features{Genres: []int{1, 2}, Actors: []int{10, 11, 12}, Director: 100}, Popularity: 10},
+		{ID: 3, Features: Features{Genres: []int{1}, Actors: []int{12}, Director: 101}, Popularity: 50},
+		{ID: 4, Features: Features{Genres: []int{3}}, Popularity: 50},
+		{ID: 5, Features: Features{Genres: []int{4}}, Popularity: 0},
+	}
+
+	tests := []struct {
+		name    string
+		profile *Profile
+		cands   []Candidate
+		order   []int
+		scores  []float64
+	}{
+		{
+			// user baru: murni popularitas, seri diurutkan id menurun
+			name:    "new user falls back to popularity",
+			profile: NewProfile(),
+			cands:   candidates,
+			order:   []int{1, 4, 3, 2, 5},
+			scores:  []float64{0.25, 0.125, 0.125, 0.025, 0},
+		},
+		{
+			name:    "history outweighs popularity",
+			profile: watchedTwo(),
+			cands:   candidates,
+			order:   []int{2, 3, 1, 4, 5},
+			scores: []float64{
+				0.75*0.9 + 0.25*0.1,
+				0.75*0.625 + 0.25*0.5,
+				0.25,
+				0.125,
+				0,
+			},
+		},
+		{
+			name:    "no popularity data",
+			profile: NewProfile(),
+			cands:   []Candidate{{ID: 7}, {ID: 9}, {ID: 8}},
+			order:   []int{9, 8, 7},
+			scores:  []float64{0, 0, 0},
+		},
+		{name: "no candidates", profile: watchedTwo()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := Rank(tt.profile, tt.cands)
+			var order []int
+			for i, r := range results {
+				order = append(order, r.ID)
+				if r.Score < 0 || r.Score > 1 {
+					t.Errorf("movie %d score %v outside 0..1", r.ID, r.Score)
+				}
+				if i < len(tt.scores) && !approx(r.Score, tt.scores[i]) {
+					t.Errorf("movie %d score = %v, want %v", r.ID, r.Score, tt.scores[i])
+				}
+			}
+			if !slices.Equal(order, tt.order) {
+				t.Errorf("order = %v, want %v", order, tt.order)
+			}
+		})
+	}
+}
+
+func TestRankDeterministic(t *testing.T) {
+	candidates := []Candidate{{ID: 1, Popularity: 5}, {ID: 2, Popularity: 5}, {ID: 3, Popularity: 5}}
+	first := Rank(watchedTwo(), candidates)
+	slices.Reverse(candidates)
+	if again := Rank(watchedTwo(), candidates); !slices.Equal(first, again) {
+		t.Errorf("rank depends on input order: %v vs %v", first, again)
+	}
+}
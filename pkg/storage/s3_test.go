@@ -0,0 +1,265 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeS3 adalah stand-in S3 path-style yang menyimpan object di memori dan memeriksa
+// ulang signature setiap request dari sisi server
+type fakeS3 struct {
+	t       *testing.T
+	bucket  string
+	signer  signer
+	mu      sync.Mutex
+	objects map[string][]byte
+	types   map[string]string
+}
+
+func newFakeS3(t *testing.T, s signer, bucket string) *httptest.Server {
+	f := &fakeS3{t: t, bucket: bucket, signer: s, objects: map[string][]byte{}, types: map[string]string{}}
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := f.verify(r); err != nil {
+		f.t.Errorf("%s %s: %v", r.Method, r.URL, err)
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	prefix := "/" + f.bucket + "/"
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		http.Error(w, "no such bucket", http.StatusNotFound)
+		return
+	}
+	key := strings.TrimPrefix(r.URL.Path, prefix)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch {
+	case key == "" && r.Method == http.MethodGet && r.URL.Query().Get("list-type") == "2":
+		f.list(w, r.URL.Query())
+	case r.Method == http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+		if r.ContentLength != int64(len(data)) {
+			http.Error(w, "bad content length", http.StatusBadRequest)
+			return
+		}
+		f.objects[key] = data
+		f.types[key] = r.Header.Get("Content-Type")
+	case r.Method == http.MethodGet:
+		data, ok := f.objects[key]
+		if !ok {
+			http.Error(w, "no such key", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", f.types[key])
+		w.Write(data)
+	case r.Method == http.MethodDelete:
+		if _, ok := f.objects[key]; !ok {
+			http.Error(w, "no such key", http.StatusNotFound)
+			return
+		}
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "unsupported", http.StatusMethodNotAllowed)
+	}
+}
+
+// list mengembalikan maksimal 2 object per halaman supaya continuation token terpakai
+func (f *fakeS3) list(w http.ResponseWriter, q url.Values) {
+	var keys []string
+	for k := range f.objects {
+		if strings.HasPrefix(k, q.Get("prefix")) {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+	start, _ := strconv.Atoi(q.Get("continuation-token"))
+	end := min(start+2, len(keys))
+
+	var result listResult
+	for _, k := range keys[start:end] {
+		result.Contents = append(result.Contents, struct {
+			Key          string    `xml:"Key"`
+			Size         int64     `xml:"Size"`
+			LastModified time.Time `xml:"LastModified"`
+		}{k, int64(len(f.objects[k])), time.Now().UTC()})
+	}
+	if end < len(keys) {
+		result.IsTruncated = true
+		result.NextContinuationToken = strconv.Itoa(end)
+	}
+	xml.NewEncoder(w).Encode(result)
+}
+
+// verify menghitung ulang signature dari request yang diterima server
+func (f *fakeS3) verify(r *http.Request) error {
+	u := &url.URL{Scheme: "http", Host: r.Host, Path: r.URL.Path}
+	q := r.URL.Query()
+
+	if sig := q.Get("X-Amz-Signature"); sig != "" {
+		signedAt, err := time.Parse(amzDateLayout, q.Get("X-Amz-Date"))
+		if err != nil {
+			return err
+		}
+		expires, _ := strconv.Atoi(q.Get("X-Amz-Expires"))
+		if time.Now().After(signedAt.Add(time.Duration(expires) * time.Second)) {
+			return errors.New("presigned url expired")
+		}
+		for k := range q {
+			if strings.HasPrefix(k, "X-Amz-") {
+				q.Del(k)
+			}
+		}
+		u.RawQuery = q.Encode()
+		want, _ := url.Parse(f.signer.presign(r.Method, u, time.Duration(expires)*time.Second, signedAt))
+		if want.Query().Get("X-Amz-Signature") != sig {
+			return errors.New("presigned signature mismatch")
+		}
+		return nil
+	}
+
+	signedAt, err := time.Parse(amzDateLayout, r.Header.Get("X-Amz-Date"))
+	if err != nil {
+		return err
+	}
+	u.RawQuery = r.URL.RawQuery
+	check, _ := http.NewRequest(r.Method, u.String(), nil)
+	for name, values := range r.Header {
+		if name != "Authorization" {
+			check.Header[name] = values
+		}
+	}
+	f.signer.sign(check, r.Header.Get("X-Amz-Content-Sha256"), signedAt)
+	if got, want := r.Header.Get("Authorization"), check.Header.Get("Authorization"); got != want {
+		return errors.New("authorization mismatch: " + got)
+	}
+	return nil
+}
+
+func newTestS3(t *testing.T) *S3 {
+	cfg := S3Config{Region: "ap-southeast-1", Bucket: "weekly", AccessKey: "minio", SecretKey: "minio-secret", PathStyle: true}
+	srv := newFakeS3(t, signer{accessKey: cfg.AccessKey, secretKey: cfg.SecretKey, region: cfg.Region}, cfg.Bucket)
+	cfg.Endpoint = srv.URL
+	s3, err := NewS3(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s3
+}
+
+func TestS3RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	s3 := newTestS3(t)
+	key := "posters/a b+c.png"
+	data := []byte("poster bytes")
+
+	if err := s3.Put(ctx, key, bytes.NewReader(data), int64(len(data)), "image/png"); err != nil {
+		t.Fatal(err)
+	}
+	rc, err := s3.Get(ctx, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := io.ReadAll(rc)
+	rc.Close()
+	if !bytes.Equal(got, data) {
+		t.Errorf("Get = %q, want %q", got, data)
+	}
+
+	if err := s3.Delete(ctx, key); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s3.Get(ctx, key); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get after delete err = %v, want ErrNotFound", err)
+	}
+	// object yang sudah tidak ada tidak dianggap error
+	if err := s3.Delete(ctx, key); err != nil {
+		t.Errorf("Delete missing object err = %v", err)
+	}
+	if _, err := s3.Get(ctx, "../"); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("Get invalid key err = %v, want ErrInvalidKey", err)
+	}
+}
+
+func TestS3Presign(t *testing.T) {
+	ctx := context.Background()
+	s3 := newTestS3(t)
+	key := "uploads/id 1"
+	data := []byte("raw upload")
+
+	putURL, err := s3.PresignPut(ctx, key, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, _ := http.NewRequest(http.MethodPut, putURL, bytes.NewReader(data))
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("presigned PUT status = %d", resp.StatusCode)
+	}
+
+	getURL, err := s3.SignedURL(ctx, key, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err = http.Get(getURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !bytes.Equal(got, data) {
+		t.Errorf("presigned GET = %d %q, want 200 %q", resp.StatusCode, got, data)
+	}
+}
+
+func TestS3List(t *testing.T) {
+	ctx := context.Background()
+	s3 := newTestS3(t)
+	keys := []string{"a_1.png", "a_2.png", "a_3.webp", "b_1.png", "uploads/x"}
+	for _, k := range keys {
+		if err := s3.Put(ctx, k, strings.NewReader(k), int64(len(k)), ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	objects, err := s3.List(ctx, "a_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, o := range objects {
+		got = append(got, o.Key)
+	}
+	if want := keys[:3]; !slices.Equal(got, want) {
+		t.Errorf("List(a_) = %v, want %v", got, want)
+	}
+
+	objects, err = s3.List(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(objects) != len(keys) {
+		t.Errorf("List() returned %d objects, want %d", len(objects), len(keys))
+	}
+}
@@ -0,0 +1,47 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path"
+	"strings"
+	"time"
+)
+
+// ErrNotFound dikembalikan Get jika object tidak ada
+var ErrNotFound = errors.New("storage: object not found")
+
+// ErrInvalidKey dikembalikan jika key kosong atau keluar dari root
+var ErrInvalidKey = errors.New("storage: invalid key")
+
+// ErrInvalidSignature dikembalikan jika URL upload lokal tidak valid atau kedaluwarsa
+var ErrInvalidSignature = errors.New("storage: invalid or expired signature")
+
+// Storage adalah tempat menyimpan file upload (poster, backdrop, avatar).
+// Key adalah path relatif dengan pemisah "/", misal "poster_12.png".
+type Storage interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error)
+	// PresignPut membuat URL untuk upload langsung dari client dengan method PUT
+	PresignPut(ctx context.Context, key string, ttl time.Duration) (string, error)
+	List(ctx context.Context, prefix string) ([]Object, error)
+}
+
+// Object adalah info singkat file di storage, hasil dari List
+type Object struct {
+	Key     string
+	Size    int64
+	ModTime time.Time
+}
+
+// cleanKey menormalkan key dan menolak key yang keluar dari root
+func cleanKey(key string) (string, error) {
+	key = strings.TrimPrefix(path.Clean("/"+key), "/")
+	if key == "" || key == "." {
+		return "", ErrInvalidKey
+	}
+	return key, nil
+}
@@ -0,0 +1,121 @@
+package validation
+
+import (
+	"reflect"
+	"regexp"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// MaxSeatsPerOrder adalah jumlah kursi maksimal dalam satu order
+const MaxSeatsPerOrder = 10
+
+// DateLayout adalah format tanggal yang diterima dari client
+const DateLayout = "2006-01-02"
+
+// ClockLayout adalah format jam mulai tayang yang diterima dari client
+const ClockLayout = "15:04"
+
+// zona waktu untuk menentukan "hari ini" di rule not_past & birthdate,
+// diisi zona waktu default cinema saat Register
+var location = time.UTC
+
+// now bisa diganti di test
+var now = time.Now
+
+// nomor HP Indonesia: 08xx, 628xx atau +628xx
+var phoneRegex = regexp.MustCompile(`^(\+62|62|0)8[1-9][0-9]{6,11}$`)
+
+var rules = map[string]validator.Func{
+	"phone":       validatePhone,
+	"seats":       validateSeats,
+	"date":        validateDate,
+	"not_past":    validateNotPast,
+	"schedule_id": validateScheduleID,
+	"birthdate":   validateBirthdate,
+	"clock":       validateClock,
+}
+
+// phone: nomor HP Indonesia
+func validatePhone(fl validator.FieldLevel) bool {
+	return phoneRegex.MatchString(fl.Field().String())
+}
+
+// seats: list id kursi tidak kosong, positif, unik dan tidak lebih dari MaxSeatsPerOrder
+func validateSeats(fl validator.FieldLevel) bool {
+	field := fl.Field()
+	if field.Kind() != reflect.Slice || field.Len() == 0 || field.Len() > MaxSeatsPerOrder {
+		return false
+	}
+	seen := make(map[int64]bool, field.Len())
+	for i := 0; i < field.Len(); i++ {
+		id := field.Index(i).Int()
+		if id < 1 || seen[id] {
+			return false
+		}
+		seen[id] = true
+	}
+	return true
+}
+
+// date: string dengan format YYYY-MM-DD
+func validateDate(fl validator.FieldLevel) bool {
+	_, err := time.Parse(DateLayout, fl.Field().String())
+	return err == nil
+}
+
+// not_past: tanggal (string YYYY-MM-DD atau time.Time) tidak boleh sebelum hari ini
+func validateNotPast(fl validator.FieldLevel) bool {
+	var date time.Time
+	switch v := fl.Field().Interface().(type) {
+	case string:
+		parsed, err := time.Parse(DateLayout, v)
+		if err != nil {
+			return false
+		}
+		date = parsed
+	case time.Time:
+		date = v
+	default:
+		return false
+	}
+	return !calendarDate(date).Before(today())
+}
+
+// schedule_id: id referensi jadwal (schedule, studio) harus positif
+func validateScheduleID(fl validator.FieldLevel) bool {
+	switch fl.Field().Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return fl.Field().Int() > 0
+	}
+	return false
+}
+
+// birthdate: tanggal lahir YYYY-MM-DD, tidak di masa depan dan tidak lebih dari 120 tahun lalu
+func validateBirthdate(fl validator.FieldLevel) bool {
+	date, err := time.Parse(DateLayout, fl.Field().String())
+	if err != nil {
+		return false
+	}
+	t := today()
+	return !date.After(t) && date.After(t.AddDate(-120, 0, 0))
+}
+
+// today adalah tanggal hari ini di zona waktu cinema, disimpan sebagai tengah malam UTC
+// supaya bisa dibandingkan dengan tanggal hasil parse DateLayout
+func today() time.Time {
+	return calendarDate(now().In(location))
+}
+
+// calendarDate membuang jam dan zona waktu, hanya tanggal kalendernya yang dipakai
+func calendarDate(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// clock: jam 24 jam dengan format HH:MM
+func validateClock(fl validator.FieldLevel) bool {
+	_, err := time.Parse(ClockLayout, fl.Field().String())
+	return err == nil && len(fl.Field().String()) == len(ClockLayout)
+}
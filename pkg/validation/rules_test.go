@@ -0,0 +1,110 @@
+package validation
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/go-playground/validator/v10"
+)
+
+func newTestValidator(t *testing.T) *validator.Validate {
+	t.Helper()
+	v := validator.New()
+	for tag, fn := range rules {
+		if err := v.RegisterValidation(tag, fn); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return v
+}
+
+// setNow memakai jam tetap di zona waktu Asia/Jakarta selama test
+func setNow(t *testing.T, at time.Time) {
+	t.Helper()
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldNow, oldLocation := now, location
+	now, location = func() time.Time { return at }, jakarta
+	t.Cleanup(func() { now, location = oldNow, oldLocation })
+}
+
+func TestRules(t *testing.T) {
+	// 19 Oktober 2026 02:00 WIB, di UTC masih tanggal 18
+	setNow(t, time.Date(2026, 10, 18, 19, 0, 0, 0, time.UTC))
+	v := newTestValidator(t)
+
+	tests := []struct {
+		tag   string
+		value any
+		want  bool
+	}{
+		{"phone", "081234567890", true},
+		{"phone", "6281234567890", true},
+		{"phone", "+6281234567890", true},
+		{"phone", "0812345", false},
+		{"phone", "0712345678", false},
+		{"phone", "080123456789", false},
+		{"phone", "+62812345678901234", false},
+		{"phone", "0812-3456-7890", false},
+
+		{"seats", []int{1, 2, 3}, true},
+		{"seats", []int{}, false},
+		{"seats", []int{1, 1}, false},
+		{"seats", []int{0, 2}, false},
+		{"seats", []int{-1}, false},
+		{"seats", []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, true},
+		{"seats", []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}, false},
+
+		{"clock", "00:00", true},
+		{"clock", "23:59", true},
+		{"clock", "24:00", false},
+		{"clock", "9:30", false},
+		{"clock", "09:30:00", false},
+		{"clock", "9.30", false},
+
+		{"date", "2026-02-28", true},
+		{"date", "2026-02-30", false},
+		{"date", "28-02-2026", false},
+
+		// hari ini dihitung di WIB: tanggal 18 sudah kemarin walaupun di UTC masih tanggal 18
+		{"not_past", "2026-10-19", true},
+		{"not_past", "2026-10-20", true},
+		{"not_past", "2026-10-18", false},
+		{"not_past", "2026/10/19", false},
+		{"not_past", time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC), true},
+		{"not_past", time.Date(2026, 10, 18, 23, 59, 0, 0, time.UTC), false},
+		{"not_past", 20261019, false},
+
+		{"birthdate", "2000-01-31", true},
+		{"birthdate", "2026-10-19", true},
+		{"birthdate", "2026-10-20", false},
+		{"birthdate", "1906-10-20", true},
+		{"birthdate", "1906-10-19", false},
+		{"birthdate", "31-01-2000", false},
+
+		{"schedule_id", 1, true},
+		{"schedule_id", 0, false},
+		{"schedule_id", "1", false},
+	}
+	for _, tt := range tests {
+		err := v.Var(tt.value, tt.tag)
+		if got := err == nil; got != tt.want {
+			t.Errorf("%s(%v) valid = %v, want %v", tt.tag, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestNotPastLateEvening(t *testing.T) {
+	// 19 Oktober 2026 23:30 WIB, di UTC juga masih tanggal 19
+	setNow(t, time.Date(2026, 10, 19, 16, 30, 0, 0, time.UTC))
+	v := newTestValidator(t)
+	if err := v.Var("2026-10-19", "not_past"); err != nil {
+		t.Errorf("today rejected: %v", err)
+	}
+	if err := v.Var("2026-10-18", "not_past"); err == nil {
+		t.Error("yesterday accepted")
+	}
+}
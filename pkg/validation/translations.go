@@ -0,0 +1,63 @@
+package validation
+
+import (
+	"strconv"
+
+	"github.com/federus1105/weekly/pkg/i18n"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+)
+
+// pesan untuk custom validator, {0} adalah nama field
+var customMessages = map[i18n.Locale]map[string]string{
+	i18n.EN: {
+		"phone":       "{0} must be a valid Indonesian phone number",
+		"seats":       "{0} must contain 1 to {1} unique seat IDs",
+		"date":        "{0} must be a date in YYYY-MM-DD format",
+		"not_past":    "{0} must not be in the past",
+		"schedule_id": "{0} must be a valid ID",
+		"birthdate":   "{0} must be a valid date of birth in YYYY-MM-DD format",
+		"clock":       "{0} must be a time in HH:MM format",
+		"timezone":    "{0} must be a valid IANA time zone, e.g. Asia/Makassar",
+	},
+	i18n.ID: {
+		"phone":       "{0} harus berupa nomor HP Indonesia yang valid",
+		"seats":       "{0} harus berisi 1 sampai {1} ID kursi yang unik",
+		"date":        "{0} harus berupa tanggal dengan format YYYY-MM-DD",
+		"not_past":    "{0} tidak boleh tanggal yang sudah lewat",
+		"schedule_id": "{0} harus berupa ID yang valid",
+		"birthdate":   "{0} harus berupa tanggal lahir yang valid dengan format YYYY-MM-DD",
+		"clock":       "{0} harus berupa jam dengan format HH:MM",
+		"timezone":    "{0} harus berupa zona waktu IANA yang valid, mis. Asia/Makassar",
+	},
+}
+
+func registerTranslations(v *validator.Validate) error {
+	for loc, messages := range customMessages {
+		trans := Translator(loc)
+		for tag, msg := range messages {
+			err := v.RegisterTranslation(tag, trans,
+				func(ut ut.Translator) error {
+					return ut.Add(tag, msg, true)
+				},
+				translate,
+			)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func translate(ut ut.Translator, fe validator.FieldError) string {
+	params := []string{fe.Field()}
+	if fe.Tag() == "seats" {
+		params = append(params, strconv.Itoa(MaxSeatsPerOrder))
+	}
+	msg, err := ut.T(fe.Tag(), params...)
+	if err != nil {
+		return fe.Error()
+	}
+	return msg
+}
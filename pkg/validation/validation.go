@@ -0,0 +1,106 @@
+package validation
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/federus1105/weekly/pkg/i18n"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/id"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	enTranslations "github.com/go-playground/validator/v10/translations/en"
+	idTranslations "github.com/go-playground/validator/v10/translations/id"
+)
+
+// FieldError adalah satu error validasi per field yang dikirim ke client
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+var uni = ut.New(en.New(), en.New(), id.New())
+
+// Register memasang nama field dari tag json/form, custom validator dan
+// terjemahannya ke engine binding gin. Dipanggil sekali saat init router.
+// loc adalah zona waktu yang menentukan "hari ini" untuk rule tanggal.
+func Register(loc *time.Location) error {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return errors.New("binding engine is not go-playground validator")
+	}
+	location = loc
+	v.RegisterTagNameFunc(fieldName)
+
+	for tag, fn := range rules {
+		if err := v.RegisterValidation(tag, fn); err != nil {
+			return err
+		}
+	}
+
+	if err := enTranslations.RegisterDefaultTranslations(v, Translator(i18n.EN)); err != nil {
+		return err
+	}
+	if err := idTranslations.RegisterDefaultTranslations(v, Translator(i18n.ID)); err != nil {
+		return err
+	}
+	return registerTranslations(v)
+}
+
+// Translator mengembalikan translator validator untuk locale
+func Translator(loc i18n.Locale) ut.Translator {
+	trans, _ := uni.GetTranslator(string(loc))
+	return trans
+}
+
+// Fields mengubah validator.ValidationErrors menjadi list per field.
+// Mengembalikan nil jika err bukan error validasi.
+func Fields(err error, loc i18n.Locale) []FieldError {
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		return nil
+	}
+	trans := Translator(loc)
+	fields := make([]FieldError, 0, len(verrs))
+	for _, fe := range verrs {
+		fields = append(fields, FieldError{
+			Field:   namespace(fe),
+			Rule:    fe.Tag(),
+			Param:   fe.Param(),
+			Message: fe.Translate(trans),
+		})
+	}
+	return fields
+}
+
+// Struct memvalidasi struct yang tidak berasal dari ShouldBind
+func Struct(obj any) error {
+	return binding.Validator.ValidateStruct(obj)
+}
+
+// fieldName memakai nama dari tag json, lalu form, lalu nama field Go
+func fieldName(fld reflect.StructField) string {
+	for _, tag := range []string{"json", "form"} {
+		name, _, _ := strings.Cut(fld.Tag.Get(tag), ",")
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return fld.Name
+}
+
+// namespace membuang nama struct paling luar, misal "Order.seats[0]" -> "seats[0]"
+func namespace(fe validator.FieldError) string {
+	if _, rest, ok := strings.Cut(fe.Namespace(), "."); ok {
+		return rest
+	}
+	return fe.Field()
+}